@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"bpmn-manager/anomaly"
+	"bpmn-manager/api"
+	"bpmn-manager/models"
+)
+
+// -----------------------------------------------------------------------
+// anomalyConfigPath returns where the anomaly limits are configured
+func anomalyConfigPath() string {
+	return filepath.Join(viewsDir(), "anomalies.json")
+}
+
+// -----------------------------------------------------------------------
+// detectAnomalies learns thresholds from the completed instances and
+// checks the running instances of the process definition key, or of all
+// processes when key is empty. It reports how many instances it checked.
+func detectAnomalies(client *api.APIClient, config *anomaly.Config, key string, now time.Time) ([]anomaly.Anomaly, int, error) {
+	completed, err := client.GetCompletedProcesses()
+	if err != nil {
+		return nil, 0, err
+	}
+	running, err := client.GetRunningProcesses()
+	if err != nil {
+		return nil, 0, err
+	}
+	return checkRunning(client, config, completed, running, key, now)
+}
+
+// -----------------------------------------------------------------------
+// checkRunning fetches the histories of the running instances of key, or
+// of all of them when key is empty, and checks them against thresholds
+// learned from completed. Instances that finish while they are fetched
+// are skipped.
+func checkRunning(client *api.APIClient, config *anomaly.Config, completed []models.ProcessDetails,
+	running []models.RunningProcess, key string, now time.Time) ([]anomaly.Anomaly, int, error) {
+	var processes []models.ProcessDetails
+	for _, r := range running {
+		if key != "" && r.ProcessDefinitionKey != key {
+			continue
+		}
+		process, err := client.GetProcessDetails(r.ProcessID)
+		if errors.Is(err, api.ErrNotFound) {
+			continue // finished since it was listed
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("instance %s: %w", r.ProcessID, err)
+		}
+		if process.EndTime != "" {
+			continue
+		}
+		if process.ProcessDefinitionKey == "" {
+			process.ProcessDefinitionKey = r.ProcessDefinitionKey
+		}
+		processes = append(processes, *process)
+	}
+	return anomaly.NewDetector(config, completed).DetectAll(processes, now), len(processes), nil
+}
+
+// -----------------------------------------------------------------------
+// anomalyText explains a finding in one line
+func anomalyText(a anomaly.Anomaly) string {
+	switch a.Kind {
+	case anomaly.KindLongPath:
+		return fmt.Sprintf("ran %d steps, usually at most %d", a.Count, a.Limit)
+	case anomaly.KindLoop:
+		return fmt.Sprintf("ran %s %d times, %s %d", a.Activity, a.Count, limitWord(a.Learned), a.Limit)
+	}
+	return fmt.Sprintf("in %s for %s, %s %s", a.Activity, formatAge(a.Elapsed), limitWord(a.Learned), formatAge(a.Threshold))
+}
+
+// -----------------------------------------------------------------------
+func limitWord(learned bool) string {
+	if learned {
+		return "usually at most"
+	}
+	return "limit"
+}
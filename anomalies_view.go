@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bpmn-manager/anomaly"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// attentionPanelSize is how many findings the dashboard panel lists
+const attentionPanelSize = 8
+
+// anomalyMaxAge is how long a detection is shown again before the
+// running instances are checked anew
+const anomalyMaxAge = 5 * time.Minute
+
+// anomalyScan is the outcome of one run of the anomaly detector
+type anomalyScan struct {
+	found   []anomaly.Anomaly
+	checked int
+	err     error
+	at      time.Time
+	mode    string // timeMode the durations were measured in
+}
+
+// -----------------------------------------------------------------------
+// freshAnomalyScan returns the last detection if it is recent and used
+// the current clock
+func (m *BPMNManager) freshAnomalyScan() *anomalyScan {
+	scan := m.anomalyScan
+	if scan == nil || time.Since(scan.at) > anomalyMaxAge || scan.mode != m.timeMode() {
+		return nil
+	}
+	return scan
+}
+
+// -----------------------------------------------------------------------
+// findAnomalies hands a detection to done on the UI goroutine, running
+// the detector in the background with the current clock unless a fresh
+// one is cached. The running and completed instances are taken from data
+// when it holds them. Callers arriving while a detection runs wait for
+// that one. It must be called on the UI goroutine.
+func (m *BPMNManager) findAnomalies(data *dashboardData, done func(scan *anomalyScan)) {
+	if scan := m.freshAnomalyScan(); scan != nil {
+		done(scan)
+		return
+	}
+	scanning := m.anomalyWaiters != nil
+	m.anomalyWaiters = append(m.anomalyWaiters, done)
+	if scanning {
+		return
+	}
+
+	mode, clock := m.timeMode(), m.clock()
+	go func() {
+		scan := &anomalyScan{at: time.Now(), mode: mode}
+		config, err := anomaly.Load(anomalyConfigPath())
+		if err == nil {
+			config.Clock = clock
+			if data != nil && data.processesErr == nil {
+				scan.found, scan.checked, err = checkRunning(m.apiClient, config, data.completed, data.running, "", scan.at)
+			} else {
+				scan.found, scan.checked, err = detectAnomalies(m.apiClient, config, "", scan.at)
+			}
+		}
+		scan.err = err
+		m.app.QueueUpdateDraw(func() {
+			if err == nil {
+				m.anomalyScan = scan
+			}
+			waiters := m.anomalyWaiters
+			m.anomalyWaiters = nil
+			for _, done := range waiters {
+				done(scan)
+			}
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// refreshAttentionPanel fills the dashboard's Needs Attention panel from
+// the instances the dashboard loaded into data
+func (m *BPMNManager) refreshAttentionPanel(data *dashboardData) {
+	m.findAnomalies(data, m.drawAttentionPanel)
+}
+
+// -----------------------------------------------------------------------
+// drawAttentionPanel lists the most severe findings of scan, or says the
+// instances are being checked while scan is nil
+func (m *BPMNManager) drawAttentionPanel(scan *anomalyScan) {
+	m.attentionPanel.SetTitle(" ⚠️ Needs Attention ").
+		SetBorder(true).SetBorderColor(tcell.Color102)
+	switch {
+	case scan == nil:
+		m.attentionPanel.SetText("[gray]Checking running instances…")
+		return
+	case scan.err != nil:
+		m.attentionPanel.SetText("[gray]Anomaly detection failed: " + tview.Escape(scan.err.Error()))
+		return
+	}
+	m.attentionPanel.SetTitle(fmt.Sprintf(" ⚠️ Needs Attention (%d) ", len(scan.found)))
+	if len(scan.found) == 0 {
+		m.attentionPanel.SetText(fmt.Sprintf("[green]All %d running instances look normal", scan.checked))
+		return
+	}
+	var b strings.Builder
+	for i, a := range scan.found {
+		if i == attentionPanelSize {
+			fmt.Fprintf(&b, "[gray]… and %d more, press a to see all", len(scan.found)-i)
+			break
+		}
+		fmt.Fprintf(&b, "[%s]%-9s[white] %s • %s\n", anomalyColorName(a), a.Kind,
+			a.InstanceID, tview.Escape(rtl(anomalyText(a))))
+	}
+	m.attentionPanel.SetText(b.String())
+}
+
+// -----------------------------------------------------------------------
+// showAnomalies lists every running instance that needs attention
+func (m *BPMNManager) showAnomalies() {
+	m.findAnomalies(nil, func(scan *anomalyScan) {
+		if scan.err != nil {
+			m.showError("Anomaly detection failed: " + scan.err.Error())
+			return
+		}
+		m.createAnomaliesView(scan.found, scan.checked)
+	})
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createAnomaliesView(found []anomaly.Anomaly, checked int) {
+	table := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102).
+		SetTitle(fmt.Sprintf(" Needs Attention • %d findings in %d running instances • %s ", len(found), checked, m.timeMode()))
+
+	headers := []string{"Kind", "|Instance", "|Process", "|Finding"}
+	for i, header := range headers {
+		table.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+	for row, a := range found {
+		color := anomalyColor(a)
+		table.SetCell(row+1, 0, tview.NewTableCell(a.Kind.String()).SetTextColor(color))
+		table.SetCell(row+1, 1, tview.NewTableCell("| "+a.InstanceID))
+		table.SetCell(row+1, 2, tview.NewTableCell("| "+tview.Escape(a.ProcessKey)))
+		table.SetCell(row+1, 3, tview.NewTableCell("| "+tview.Escape(rtl(anomalyText(a)))).SetExpansion(1))
+	}
+	table.Select(1, 0)
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(found) {
+			return event
+		}
+		if event.Key() == tcell.KeyEnter {
+			m.showInstanceDetails(found[row-1].InstanceID, "anomalies")
+			return nil
+		}
+		return m.handleProcessKey(found[row-1].InstanceID, event)
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Enter details • t timeline • v variables • Esc back • limits in " + tview.Escape(anomalyConfigPath()))
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	m.pages.AddPage("anomalies", layout, true, true)
+	m.pages.SwitchToPage("anomalies")
+	m.app.SetFocus(table)
+}
+
+// -----------------------------------------------------------------------
+// anomalyColor marks findings at least twice their threshold red
+func anomalyColor(a anomaly.Anomaly) tcell.Color {
+	if a.Severity() >= 2 {
+		return tcell.ColorRed
+	}
+	return tcell.ColorOrange
+}
+
+// -----------------------------------------------------------------------
+func anomalyColorName(a anomaly.Anomaly) string {
+	if a.Severity() >= 2 {
+		return "red"
+	}
+	return "orange"
+}
@@ -0,0 +1,288 @@
+package anomaly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+	"bpmn-manager/sla"
+)
+
+// Kind is what is abnormal about a running instance
+type Kind int
+
+const (
+	KindStuck    Kind = iota // in its current activity for too long
+	KindLongPath             // ran far more steps than usual
+	KindLoop                 // repeated an activity more often than usual
+)
+
+// -----------------------------------------------------------------------
+func (k Kind) String() string {
+	switch k {
+	case KindLongPath:
+		return "long path"
+	case KindLoop:
+		return "loop"
+	}
+	return "stuck"
+}
+
+// Config holds fixed limits and how sensitive the thresholds learned from
+// completed instances are
+type Config struct {
+	Limits     map[string]sla.Duration `json:"limits,omitempty"`     // time allowed in an activity, by activity id
+	Fence      float64                 `json:"fence,omitempty"`      // interquartile ranges above the third quartile, default 3
+	MinSamples int                     `json:"minSamples,omitempty"` // histories needed to learn a threshold, default 5
+	MaxRepeats int                     `json:"maxRepeats,omitempty"` // executions of an activity always tolerated, default 3
+
+	Clock calendar.Clock `json:"-"` // nil counts wall-clock time
+}
+
+// -----------------------------------------------------------------------
+// Load reads a config file. A missing file yields the defaults, which
+// only use thresholds learned from history.
+func Load(path string) (*Config, error) {
+	config := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid anomaly config %s: %w", path, err)
+	}
+	if config.Fence < 0 || config.MinSamples < 0 || config.MaxRepeats < 0 {
+		return nil, fmt.Errorf("invalid anomaly config %s: fence, minSamples and maxRepeats must not be negative", path)
+	}
+	return config, nil
+}
+
+// -----------------------------------------------------------------------
+func (c *Config) fence() float64 {
+	if c.Fence == 0 {
+		return 3
+	}
+	return c.Fence
+}
+
+// -----------------------------------------------------------------------
+func (c *Config) minSamples() int {
+	if c.MinSamples == 0 {
+		return 5
+	}
+	return c.MinSamples
+}
+
+// -----------------------------------------------------------------------
+func (c *Config) maxRepeats() int {
+	if c.MaxRepeats == 0 {
+		return 3
+	}
+	return c.MaxRepeats
+}
+
+// Anomaly is one finding on a running instance. Stuck instances carry the
+// time spent and the threshold; path and loop findings carry counts.
+type Anomaly struct {
+	Kind       Kind
+	InstanceID string
+	ProcessKey string
+	ActivityID string // the activity stuck or repeated, empty for paths
+	Activity   string
+	Elapsed    time.Duration
+	Threshold  time.Duration
+	Count      int
+	Limit      int
+	Learned    bool // the threshold was learned from history, not configured
+}
+
+// -----------------------------------------------------------------------
+// Severity is how far the instance is past its threshold, 1 at the
+// threshold itself; findings are ranked by it
+func (a Anomaly) Severity() float64 {
+	// a zero threshold counts as a second or a single step, keeping the
+	// severity finite for JSON output
+	if a.Kind == KindStuck {
+		threshold := a.Threshold
+		if threshold <= 0 {
+			threshold = time.Second
+		}
+		return float64(a.Elapsed) / float64(threshold)
+	}
+	limit := a.Limit
+	if limit <= 0 {
+		limit = 1
+	}
+	return float64(a.Count) / float64(limit)
+}
+
+// Detector checks running instances against thresholds learned from the
+// completed instances of their process definition key
+type Detector struct {
+	config  *Config
+	clock   calendar.Clock
+	stuck   map[string]time.Duration // by definition key and activity id
+	repeats map[string]int           // most executions of an activity in one history
+	steps   map[string]int           // path length limit by definition key
+}
+
+// -----------------------------------------------------------------------
+func activityKey(processKey, activityID string) string {
+	return processKey + "\x00" + activityID
+}
+
+// -----------------------------------------------------------------------
+// NewDetector learns the thresholds from completed instances. The time
+// an activity may take is the upper outer fence, Q3 + Fence·IQR, of the
+// durations it took; the path length limit is the same fence over the
+// number of steps.
+func NewDetector(config *Config, completed []models.ProcessDetails) *Detector {
+	d := &Detector{
+		config:  config,
+		clock:   config.Clock,
+		stuck:   make(map[string]time.Duration),
+		repeats: make(map[string]int),
+		steps:   make(map[string]int),
+	}
+	if d.clock == nil {
+		d.clock = calendar.WallClock{}
+	}
+
+	durations := make(map[string][]float64)
+	lengths := make(map[string][]float64)
+	for _, process := range completed {
+		trace := mining.Trace(process)
+		lengths[process.ProcessDefinitionKey] = append(lengths[process.ProcessDefinitionKey], float64(len(trace)))
+		counts := make(map[string]int)
+		for _, activity := range trace {
+			key := activityKey(process.ProcessDefinitionKey, activity.ID)
+			counts[key]++
+			if !activity.StartTime.IsZero() && !activity.EndTime.IsZero() {
+				durations[key] = append(durations[key], float64(d.clock.Between(activity.StartTime, activity.EndTime)))
+			}
+		}
+		for key, n := range counts {
+			if n > d.repeats[key] {
+				d.repeats[key] = n
+			}
+		}
+	}
+
+	for key, values := range durations {
+		if len(values) >= config.minSamples() {
+			d.stuck[key] = time.Duration(upperFence(values, config.fence()))
+		}
+	}
+	for key, values := range lengths {
+		if len(values) >= config.minSamples() {
+			d.steps[key] = int(math.Ceil(upperFence(values, config.fence())))
+		}
+	}
+	return d
+}
+
+// -----------------------------------------------------------------------
+// Detect checks one running instance whose activity history is known
+func (d *Detector) Detect(process models.ProcessDetails, now time.Time) []Anomaly {
+	var found []Anomaly
+	base := Anomaly{InstanceID: process.ID, ProcessKey: process.ProcessDefinitionKey}
+	trace := mining.Trace(process)
+
+	counts := make(map[string]int)
+	names := make(map[string]string)
+	var order []string
+	for _, activity := range trace {
+		if counts[activity.ID] == 0 {
+			order = append(order, activity.ID)
+		}
+		counts[activity.ID]++
+		names[activity.ID] = activity.Name
+		if names[activity.ID] == "" {
+			names[activity.ID] = activity.ID
+		}
+
+		if activity.StartTime.IsZero() || !activity.EndTime.IsZero() {
+			continue
+		}
+		threshold, learned := d.threshold(process.ProcessDefinitionKey, activity.ID)
+		elapsed := d.clock.Between(activity.StartTime, now)
+		if threshold > 0 && elapsed > threshold {
+			a := base
+			a.Kind, a.ActivityID, a.Activity = KindStuck, activity.ID, names[activity.ID]
+			a.Elapsed, a.Threshold, a.Learned = elapsed, threshold, learned
+			found = append(found, a)
+		}
+	}
+
+	// a limit of zero steps was learned from empty histories only
+	if limit := d.steps[process.ProcessDefinitionKey]; limit > 0 && len(trace) > limit {
+		a := base
+		a.Kind, a.Count, a.Limit, a.Learned = KindLongPath, len(trace), limit, true
+		found = append(found, a)
+	}
+
+	for _, id := range order {
+		limit := d.repeats[activityKey(process.ProcessDefinitionKey, id)]
+		learned := true
+		if limit < d.config.maxRepeats() {
+			limit, learned = d.config.maxRepeats(), false
+		}
+		if counts[id] > limit {
+			a := base
+			a.Kind, a.ActivityID, a.Activity = KindLoop, id, names[id]
+			a.Count, a.Limit, a.Learned = counts[id], limit, learned
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+// -----------------------------------------------------------------------
+// threshold returns the time allowed in an activity, a configured limit
+// taking precedence over a learned one; zero when there is neither
+func (d *Detector) threshold(processKey, activityID string) (time.Duration, bool) {
+	if limit, ok := d.config.Limits[activityID]; ok {
+		return time.Duration(limit), false
+	}
+	threshold, ok := d.stuck[activityKey(processKey, activityID)]
+	return threshold, ok
+}
+
+// -----------------------------------------------------------------------
+// DetectAll checks running instances, most severe finding first
+func (d *Detector) DetectAll(processes []models.ProcessDetails, now time.Time) []Anomaly {
+	var found []Anomaly
+	for _, process := range processes {
+		found = append(found, d.Detect(process, now)...)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Severity() > found[j].Severity()
+	})
+	return found
+}
+
+// -----------------------------------------------------------------------
+// upperFence returns Q3 + k·IQR of values, with quartiles by the nearest
+// rank. values is sorted in place.
+func upperFence(values []float64, k float64) float64 {
+	sort.Float64s(values)
+	quartile := func(p float64) float64 {
+		rank := int(math.Ceil(p * float64(len(values))))
+		if rank < 1 {
+			rank = 1
+		}
+		return values[rank-1]
+	}
+	q1, q3 := quartile(0.25), quartile(0.75)
+	return q3 + k*(q3-q1)
+}
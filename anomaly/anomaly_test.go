@@ -0,0 +1,159 @@
+package anomaly
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"bpmn-manager/models"
+	"bpmn-manager/sla"
+)
+
+var start = time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+
+// -----------------------------------------------------------------------
+// history runs the activities one after another, taking the given minutes
+// each; a negative duration leaves the activity running
+func history(id string, steps ...interface{}) models.ProcessDetails {
+	process := models.ProcessDetails{ID: id, ProcessDefinitionKey: "order"}
+	at := start
+	for i := 0; i < len(steps); i += 2 {
+		activity := models.ProcessActivity{ID: steps[i].(string), Order: i / 2, StartTime: at}
+		if minutes := steps[i+1].(int); minutes >= 0 {
+			at = at.Add(time.Duration(minutes) * time.Minute)
+			activity.EndTime = at
+		}
+		process.Activities = append(process.Activities, activity)
+	}
+	return process
+}
+
+// -----------------------------------------------------------------------
+// completed learns check taking 10 to 30 minutes: Q1 10, Q3 20, so the
+// default fence allows 50 minutes; every history has three steps
+func completed() []models.ProcessDetails {
+	var list []models.ProcessDetails
+	for _, minutes := range []int{10, 10, 20, 20, 30} {
+		list = append(list, history("done", "start", 0, "check", minutes, "ship", 10))
+	}
+	return list
+}
+
+func TestUpperFence(t *testing.T) {
+	tests := []struct {
+		values []float64
+		k      float64
+		want   float64
+	}{
+		{[]float64{5}, 3, 5},
+		{[]float64{30, 10, 20, 10, 20}, 3, 50},
+		{[]float64{30, 10, 20, 10, 20}, 1.5, 35},
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8}, 1, 10},
+	}
+	for _, tt := range tests {
+		if got := upperFence(append([]float64(nil), tt.values...), tt.k); got != tt.want {
+			t.Errorf("upperFence(%v, %v) = %v, want %v", tt.values, tt.k, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	type finding struct {
+		Kind      Kind
+		Activity  string
+		Threshold time.Duration
+		Count     int
+		Limit     int
+		Learned   bool
+	}
+	tests := []struct {
+		name    string
+		config  Config
+		running models.ProcessDetails
+		elapsed time.Duration
+		want    []finding
+	}{
+		{"on time", Config{}, history("a", "start", 0, "check", -1), 40 * time.Minute, nil},
+		{"stuck", Config{}, history("b", "start", 0, "check", -1), time.Hour,
+			[]finding{{KindStuck, "check", 50 * time.Minute, 0, 0, true}}},
+		{"configured limit", Config{Limits: map[string]sla.Duration{"check": sla.Duration(30 * time.Minute)}},
+			history("c", "start", 0, "check", -1), 40 * time.Minute,
+			[]finding{{KindStuck, "check", 30 * time.Minute, 0, 0, false}}},
+		{"unknown activity", Config{}, history("d", "start", 0, "review", -1), time.Hour, nil},
+		{"loop", Config{}, history("e", "start", 0, "check", 1, "check", 1, "check", 1, "check", 1), time.Hour,
+			[]finding{
+				{Kind: KindLongPath, Count: 5, Limit: 3, Learned: true},
+				{Kind: KindLoop, Activity: "check", Count: 4, Limit: 3},
+			}},
+		{"repeats tolerated", Config{MaxRepeats: 4}, history("f", "start", 0, "check", 1, "check", 1, "check", 1), time.Hour,
+			[]finding{{Kind: KindLongPath, Count: 4, Limit: 3, Learned: true}}},
+	}
+	for _, tt := range tests {
+		config := tt.config
+		var got []finding
+		for _, a := range NewDetector(&config, completed()).Detect(tt.running, start.Add(tt.elapsed)) {
+			got = append(got, finding{a.Kind, a.ActivityID, a.Threshold, a.Count, a.Limit, a.Learned})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Detect() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetectAll(t *testing.T) {
+	detector := NewDetector(&Config{}, completed())
+	running := []models.ProcessDetails{
+		history("slow", "start", 0, "check", -1),
+		history("slower", "start", 0, "check", -1),
+	}
+	running[1].Activities[1].StartTime = start.Add(-time.Hour)
+
+	found := detector.DetectAll(running, start.Add(time.Hour))
+	var ids []string
+	for _, a := range found {
+		ids = append(ids, a.InstanceID)
+	}
+	if !reflect.DeepEqual(ids, []string{"slower", "slow"}) {
+		t.Errorf("DetectAll() = %v, want most severe first [slower slow]", ids)
+	}
+	if got := found[0].Severity(); got != 2.4 {
+		t.Errorf("Severity() = %v, want 2.4", got)
+	}
+}
+
+func TestSeverityIsFinite(t *testing.T) {
+	for _, a := range []Anomaly{
+		{Kind: KindStuck, Elapsed: time.Hour},
+		{Kind: KindLongPath, Count: 4},
+		{Kind: KindLoop, Count: 5, Limit: 0},
+	} {
+		severity := a.Severity()
+		if math.IsInf(severity, 0) || math.IsNaN(severity) {
+			t.Errorf("%v severity = %v", a.Kind, severity)
+		}
+		if _, err := json.Marshal(severity); err != nil {
+			t.Errorf("%v severity does not encode: %v", a.Kind, err)
+		}
+	}
+}
+
+func TestDetectSkipsZeroPathLimit(t *testing.T) {
+	// completed instances without recorded activities learn a path limit
+	// of zero steps, which must not flag every running instance
+	completed := make([]models.ProcessDetails, 5)
+	for i := range completed {
+		completed[i] = models.ProcessDetails{ID: "done", ProcessDefinitionKey: "order"}
+	}
+	detector := NewDetector(&Config{}, completed)
+
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	running := models.ProcessDetails{ID: "running", ProcessDefinitionKey: "order", Activities: []models.ProcessActivity{
+		{ID: "start", StartTime: start, EndTime: start},
+		{ID: "check", StartTime: start, EndTime: start.Add(time.Minute)},
+	}}
+	if found := detector.Detect(running, start.Add(time.Hour)); len(found) != 0 {
+		t.Errorf("Detect = %+v, want no findings", found)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// GetTaskAttachments lists the attachments of a task
+func (c *APIClient) GetTaskAttachments(taskID string) ([]models.Attachment, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/task/%s/attachments", url.PathEscape(taskID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.Attachment
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse attachments: %v", err)
+	}
+
+	return response, nil
+}
+
+// UploadTaskAttachment attaches a local file to a task. The file is
+// streamed as multipart form data, so it is never held in memory.
+func (c *APIClient) UploadTaskAttachment(taskID, path, description string) (*models.Attachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		err := form.WriteField("description", description)
+		if err == nil {
+			var part io.Writer
+			part, err = form.CreateFormFile("content", filepath.Base(path))
+			if err == nil {
+				_, err = io.Copy(part, file)
+			}
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	req, err := c.newTransferRequest("POST", fmt.Sprintf("/api/task/%s/attachments", url.PathEscape(taskID)), reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response models.Attachment
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse attachment: %v", err)
+	}
+
+	return &response, nil
+}
+
+// DownloadTaskAttachment streams the content of an attachment to w and
+// returns the number of bytes written
+func (c *APIClient) DownloadTaskAttachment(taskID, attachmentID string, w io.Writer) (int64, error) {
+	endpoint := fmt.Sprintf("/api/task/%s/attachments/%s/data", url.PathEscape(taskID), url.PathEscape(attachmentID))
+	req, err := c.newTransferRequest("GET", endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("API returned status %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// newTransferRequest creates an authenticated request for file content
+func (c *APIClient) newTransferRequest(method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "BPMN-Manager-CLI/1.0")
+	req.SetBasicAuth("workflow", "wrkflw-system")
+
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	return req, nil
+}
+
+// transferClient shares the client's transport but has no overall
+// timeout, since large files may take longer than API calls
+func (c *APIClient) transferClient() *http.Client {
+	return &http.Client{Transport: c.httpClient.Transport}
+}
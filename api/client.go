@@ -4,13 +4,19 @@ import (
 	"bpmn-manager/models"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// ErrNotFound is wrapped by the error of a request answered with 404,
+// e.g. for an instance that finished and is gone from the runtime
+var ErrNotFound = errors.New("404 Not Found")
+
 type APIClient struct {
 	baseURL    string
 	httpClient *http.Client
@@ -53,6 +59,9 @@ func (c *APIClient) doRequest(method, endpoint string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("API returned status %d: %w", resp.StatusCode, ErrNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, resp.Status)
 	}
@@ -65,6 +74,49 @@ func (c *APIClient) doRequest(method, endpoint string) ([]byte, error) {
 	return body, nil
 }
 
+// doJSONRequest sends payload (if any) as a JSON body and accepts any 2xx
+// status. The response body is returned for the caller to decode.
+func (c *APIClient) doJSONRequest(method, endpoint string, payload interface{}) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BPMN-Manager-CLI/1.0")
+	req.SetBasicAuth("workflow", "wrkflw-system")
+
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
 // func (c *APIClient) GetUserTasks() ([]models.UserTask, error) {
 
 // 	body, err := c.doRequest("GET", "/api/user/tasks")
@@ -173,7 +225,7 @@ func (c *APIClient) GetProcessDetails(processID string) (*models.ProcessDetails,
 	endpoint := fmt.Sprintf("/api/%s/details", processID)
 	body, err := c.doRequest("GET", endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse process details: %v", err)
+		return nil, fmt.Errorf("failed to parse process details: %w", err)
 		// return nil, err
 	}
 
@@ -185,6 +237,25 @@ func (c *APIClient) GetProcessDetails(processID string) (*models.ProcessDetails,
 	return &response, nil
 }
 
+// GetProcessDefinitionXML returns the deployed BPMN 2.0 XML of a process definition
+func (c *APIClient) GetProcessDefinitionXML(definitionID string) ([]byte, error) {
+	endpoint := fmt.Sprintf("/api/process-definition/%s/xml", url.PathEscape(definitionID))
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.ProcessDefinitionXML
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse process definition xml: %v", err)
+	}
+	if response.BPMN20XML == "" {
+		return nil, fmt.Errorf("process definition %s has no BPMN xml", definitionID)
+	}
+
+	return []byte(response.BPMN20XML), nil
+}
+
 func (c *APIClient) GetCompletedProcesses() ([]models.ProcessDetails, error) {
 	body, err := c.doRequest("GET", "/api/completed-processes")
 	if err != nil {
@@ -278,6 +349,22 @@ func (c *APIClient) CompleteTask(taskID string, formData models.FormData) error
 	return nil
 }
 
+// GetCurrentUser returns the profile and group memberships of the
+// authenticated user
+func (c *APIClient) GetCurrentUser() (*models.User, error) {
+	body, err := c.doRequest("GET", "/api/user/me")
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.User
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse current user: %v", err)
+	}
+
+	return &response, nil
+}
+
 func (c *APIClient) StartProcess() error {
 	body, err := c.doRequest("GET", "/api/start")
 	if err != nil {
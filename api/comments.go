@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetTaskComments returns the comments of a task, oldest first
+func (c *APIClient) GetTaskComments(taskID string) ([]models.Comment, error) {
+	return c.getComments(fmt.Sprintf("/api/task/%s/comments", url.PathEscape(taskID)))
+}
+
+// AddTaskComment adds a comment to a task
+func (c *APIClient) AddTaskComment(taskID, message string) (*models.Comment, error) {
+	return c.addComment(fmt.Sprintf("/api/task/%s/comments", url.PathEscape(taskID)), message)
+}
+
+// GetProcessInstanceComments returns the comments of a process instance,
+// including the ones left on its tasks
+func (c *APIClient) GetProcessInstanceComments(instanceID string) ([]models.Comment, error) {
+	return c.getComments(fmt.Sprintf("/api/process-instance/%s/comments", url.PathEscape(instanceID)))
+}
+
+// AddProcessInstanceComment adds a comment to a process instance
+func (c *APIClient) AddProcessInstanceComment(instanceID, message string) (*models.Comment, error) {
+	return c.addComment(fmt.Sprintf("/api/process-instance/%s/comments", url.PathEscape(instanceID)), message)
+}
+
+func (c *APIClient) getComments(endpoint string) ([]models.Comment, error) {
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.Comment
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse comments: %v", err)
+	}
+
+	return response, nil
+}
+
+func (c *APIClient) addComment(endpoint, message string) (*models.Comment, error) {
+	payload := map[string]string{"message": message}
+	body, err := c.doJSONRequest("POST", endpoint, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+
+	var response models.Comment
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse comment: %v", err)
+	}
+
+	return &response, nil
+}
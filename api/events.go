@@ -0,0 +1,43 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// CorrelateMessage delivers a message to the waiting message events or
+// receive tasks matching its name, business key and correlation keys
+func (c *APIClient) CorrelateMessage(message models.MessageCorrelation) error {
+	if _, err := c.doJSONRequest("POST", "/api/message", message); err != nil {
+		return fmt.Errorf("failed to correlate message %s: %w", message.MessageName, err)
+	}
+	return nil
+}
+
+// BroadcastSignal throws a signal, optionally to a single execution
+func (c *APIClient) BroadcastSignal(signal models.SignalBroadcast) error {
+	if _, err := c.doJSONRequest("POST", "/api/signal", signal); err != nil {
+		return fmt.Errorf("failed to broadcast signal %s: %w", signal.Name, err)
+	}
+	return nil
+}
+
+// GetSignalExecutions returns the executions of an instance that wait for
+// the named signal, e.g. one per parallel branch or sub-process
+func (c *APIClient) GetSignalExecutions(instanceID, signalName string) ([]models.Execution, error) {
+	params := url.Values{}
+	params.Set("processInstanceId", instanceID)
+	params.Set("signalEventSubscriptionName", signalName)
+	body, err := c.doRequest("GET", "/api/execution?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.Execution
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse executions: %v", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (c *APIClient) GetIncidents() ([]models.Incident, error) {
+	body, err := c.doRequest("GET", "/api/incidents")
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.Incident
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse incidents: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetFailedJobs returns jobs that threw an exception, including the ones
+// that still have retries left
+func (c *APIClient) GetFailedJobs() ([]models.Job, error) {
+	body, err := c.doRequest("GET", "/api/jobs/failed")
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.Job
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse jobs: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetJobStacktrace returns the exception stack trace of a failed job
+func (c *APIClient) GetJobStacktrace(jobID string) (string, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/job/%s/stacktrace", url.PathEscape(jobID)))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// SetJobRetries sets the retry count of a job, which makes the engine
+// execute it again and resolves its incident
+func (c *APIClient) SetJobRetries(jobID string, retries int) error {
+	payload := map[string]int{"retries": retries}
+	if _, err := c.doJSONRequest("PUT", fmt.Sprintf("/api/job/%s/retries", url.PathEscape(jobID)), payload); err != nil {
+		return fmt.Errorf("failed to retry job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// SetJobsRetries sets the retry count of several jobs in one call
+func (c *APIClient) SetJobsRetries(jobIDs []string, retries int) error {
+	payload := struct {
+		JobIDs  []string `json:"jobIds"`
+		Retries int      `json:"retries"`
+	}{jobIDs, retries}
+	if _, err := c.doJSONRequest("POST", "/api/jobs/retries", payload); err != nil {
+		return fmt.Errorf("failed to retry %d jobs: %w", len(jobIDs), err)
+	}
+	return nil
+}
+
+// AnnotateIncident stores a free-text resolution note on an incident
+func (c *APIClient) AnnotateIncident(incidentID, annotation string) error {
+	payload := map[string]string{"annotation": annotation}
+	if _, err := c.doJSONRequest("PUT", fmt.Sprintf("/api/incident/%s/annotation", url.PathEscape(incidentID)), payload); err != nil {
+		return fmt.Errorf("failed to annotate incident %s: %w", incidentID, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+)
+
+// GetProcessDefinitions returns all deployed process definition versions
+func (c *APIClient) GetProcessDefinitions() ([]models.ProcessDefinition, error) {
+	body, err := c.doRequest("GET", "/api/process-definitions")
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.ProcessDefinition
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse process definitions: %v", err)
+	}
+
+	return response, nil
+}
+
+// ValidateMigrationPlan asks the engine to check a plan without migrating
+// anything
+func (c *APIClient) ValidateMigrationPlan(plan models.MigrationPlan) (*models.MigrationValidationReport, error) {
+	body, err := c.doJSONRequest("POST", "/api/migration/validate", plan)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.MigrationValidationReport
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse migration validation: %v", err)
+	}
+
+	return &response, nil
+}
+
+// ExecuteMigration migrates a set of instances synchronously
+func (c *APIClient) ExecuteMigration(execution models.MigrationExecution) error {
+	if _, err := c.doJSONRequest("POST", "/api/migration/execute", execution); err != nil {
+		return fmt.Errorf("failed to migrate %d instance(s): %w", len(execution.ProcessInstanceIDs), err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetActivityInstances returns the activity instance tree of a running
+// process instance
+func (c *APIClient) GetActivityInstances(instanceID string) (*models.ActivityInstance, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/process-instance/%s/activity-instances", url.PathEscape(instanceID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.ActivityInstance
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse activity instances: %v", err)
+	}
+
+	return &response, nil
+}
+
+// ModifyProcessInstance cancels and starts activities of a running
+// instance in one transaction
+func (c *APIClient) ModifyProcessInstance(instanceID string, modification models.ProcessInstanceModification) error {
+	endpoint := fmt.Sprintf("/api/process-instance/%s/modification", url.PathEscape(instanceID))
+	if _, err := c.doJSONRequest("POST", endpoint, modification); err != nil {
+		return fmt.Errorf("failed to modify instance %s: %w", instanceID, err)
+	}
+	return nil
+}
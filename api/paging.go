@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// pageParams copies the filter parameters and adds the page window
+func pageParams(params url.Values, offset, limit int) url.Values {
+	page := url.Values{}
+	for key, values := range params {
+		page[key] = append([]string(nil), values...)
+	}
+	page.Set("firstResult", strconv.Itoa(offset))
+	page.Set("maxResults", strconv.Itoa(limit))
+	return page
+}
+
+// decodePage reads a {items,total} page. A backend that does not page
+// the endpoint returns a plain array, which is taken as the last page.
+func decodePage[T any](body []byte, offset int) (*models.Page[T], error) {
+	var page models.Page[T]
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &page.Items); err != nil {
+			return nil, err
+		}
+		page.Total = offset + len(page.Items)
+		return &page, nil
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetUserTasksPage returns limit user tasks starting at offset and the
+// total number of tasks matching params
+func (c *APIClient) GetUserTasksPage(params url.Values, offset, limit int) (*models.Page[models.UserTask], error) {
+	body, err := c.doRequest("GET", "/api/user/tasks?"+pageParams(params, offset, limit).Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := decodePage[models.UserTask](body, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user tasks page: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetRunningProcessesPage returns limit running instances starting at
+// offset and the total number of instances matching params
+func (c *APIClient) GetRunningProcessesPage(params url.Values, offset, limit int) (*models.Page[models.RunningProcess], error) {
+	body, err := c.doRequest("GET", "/api/instances?"+pageParams(params, offset, limit).Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := decodePage[models.RunningProcess](body, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse running processes page: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetCompletedProcessesPage returns limit completed instances with their
+// activity histories starting at offset and the total number matching
+// params. When the backend answers with the plain array that
+// GetCompletedProcesses reads, that array is the only page.
+func (c *APIClient) GetCompletedProcessesPage(params url.Values, offset, limit int) (*models.Page[models.ProcessDetails], error) {
+	body, err := c.doRequest("GET", "/api/completed-processes?"+pageParams(params, offset, limit).Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := decodePage[models.ProcessDetails](body, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse completed processes page: %v", err)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetUserTasksWhere returns the user tasks matching engine query
+// parameters, all tasks when params is empty
+func (c *APIClient) GetUserTasksWhere(params url.Values) ([]models.UserTask, error) {
+	if len(params) == 0 {
+		return c.GetUserTasks()
+	}
+	body, err := c.doRequest("GET", "/api/user/tasks/all?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.UserTask
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse user tasks: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetRunningProcessesWhere returns the running instances matching engine
+// query parameters, all instances when params is empty
+func (c *APIClient) GetRunningProcessesWhere(params url.Values) ([]models.RunningProcess, error) {
+	if len(params) == 0 {
+		return c.GetRunningProcesses()
+	}
+	body, err := c.doRequest("GET", "/api/all-instances?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.RunningProcess
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse running processes: %v", err)
+	}
+
+	return response, nil
+}
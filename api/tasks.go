@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetTask returns a single user task with all its fields
+func (c *APIClient) GetTask(taskID string) (*models.UserTask, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/task/%s", url.PathEscape(taskID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.UserTask
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse task: %v", err)
+	}
+
+	return &response, nil
+}
+
+// GetTaskIdentityLinks returns the current assignee, owner and candidate
+// users and groups of a task
+func (c *APIClient) GetTaskIdentityLinks(taskID string) ([]models.IdentityLink, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/task/%s/identity-links", url.PathEscape(taskID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.IdentityLink
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse identity links: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetTaskIdentityLinkHistory returns every claim, assignment and
+// candidate change of a task
+func (c *APIClient) GetTaskIdentityLinkHistory(taskID string) ([]models.IdentityLinkLog, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/api/task/%s/identity-link-history", url.PathEscape(taskID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.IdentityLinkLog
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse identity link history: %v", err)
+	}
+
+	return response, nil
+}
+
+// GetGroupTasks returns the unassigned tasks offered to any of groups.
+// /api/user/tasks/all passes its parameters on to the engine's task
+// query, where candidateGroups takes a comma-separated list and
+// unassigned=true leaves out claimed tasks, as in the Camunda 7 REST API.
+// A backend that ignores them answers with every task, so callers still
+// check UserTask.Claimable.
+func (c *APIClient) GetGroupTasks(groups []string) ([]models.UserTask, error) {
+	params := url.Values{}
+	params.Set("candidateGroups", strings.Join(groups, ","))
+	params.Set("unassigned", "true")
+	return c.GetUserTasksWhere(params)
+}
+
+// ClaimTask makes userID the assignee of an unassigned task
+func (c *APIClient) ClaimTask(taskID, userID string) error {
+	payload := map[string]string{"userId": userID}
+	if _, err := c.doJSONRequest("POST", fmt.Sprintf("/api/task/%s/claim", url.PathEscape(taskID)), payload); err != nil {
+		return fmt.Errorf("failed to claim task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// UnclaimTask returns a task to its candidate groups
+func (c *APIClient) UnclaimTask(taskID string) error {
+	if _, err := c.doJSONRequest("POST", fmt.Sprintf("/api/task/%s/unclaim", url.PathEscape(taskID)), nil); err != nil {
+		return fmt.Errorf("failed to unclaim task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// SetAssignee reassigns a task, whether or not it is claimed
+func (c *APIClient) SetAssignee(taskID, userID string) error {
+	payload := map[string]string{"userId": userID}
+	if _, err := c.doJSONRequest("POST", fmt.Sprintf("/api/task/%s/assignee", url.PathEscape(taskID)), payload); err != nil {
+		return fmt.Errorf("failed to assign task %s: %w", taskID, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// SetVariable creates or replaces a variable on a running process instance
+func (c *APIClient) SetVariable(instanceID, name string, value models.VariableValue) error {
+	endpoint := fmt.Sprintf("/api/process-instance/%s/variables/%s", url.PathEscape(instanceID), url.PathEscape(name))
+	if _, err := c.doJSONRequest("PUT", endpoint, value); err != nil {
+		return fmt.Errorf("failed to set variable %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteVariable removes a variable from a running process instance
+func (c *APIClient) DeleteVariable(instanceID, name string) error {
+	endpoint := fmt.Sprintf("/api/process-instance/%s/variables/%s", url.PathEscape(instanceID), url.PathEscape(name))
+	if _, err := c.doJSONRequest("DELETE", endpoint, nil); err != nil {
+		return fmt.Errorf("failed to delete variable %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetVariableHistory returns every recorded variable update of an instance
+func (c *APIClient) GetVariableHistory(instanceID string) ([]models.VariableUpdate, error) {
+	endpoint := fmt.Sprintf("/api/process-instance/%s/variable-history", url.PathEscape(instanceID))
+	body, err := c.doRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []models.VariableUpdate
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse variable history: %v", err)
+	}
+
+	return response, nil
+}
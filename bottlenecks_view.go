@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"bpmn-manager/export"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showBottlenecks loads the completed instances and ranks the activities
+// of the chosen process by the time instances spend in them
+func (m *BPMNManager) showBottlenecks() {
+	go func() {
+		processes, err := m.apiClient.GetCompletedProcesses()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load completed processes: " + err.Error())
+				return
+			}
+			if len(processes) == 0 {
+				m.showError("There are no completed process instances to analyse")
+				return
+			}
+			m.chooseDefinition("bottlenecks", processes, m.showBottleneckReport)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// mainDefinitionID returns the definition id most of the instances ran
+// on, so a diagram can be drawn for them
+func mainDefinitionID(processes []models.ProcessDetails) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, process := range processes {
+		counts[process.ProcessDefinitionId]++
+		if counts[process.ProcessDefinitionId] > counts[best] {
+			best = process.ProcessDefinitionId
+		}
+	}
+	return best
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showBottleneckReport(key string, processes []models.ProcessDetails) {
+	stats := mining.Bottlenecks(processes, m.clock())
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102).
+		SetTitle(fmt.Sprintf(" Bottlenecks • %s • %d instances • %s ", key, len(processes), m.timeMode()))
+
+	headers := []string{"Activity", "|Type", "|Count", "|Mean", "|Median", "|P90", "|P99", "|Wait", "|Wait P90", "|Heat"}
+	for i, header := range headers {
+		table.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	var worst float64
+	for _, s := range stats {
+		if float64(s.Score()) > worst {
+			worst = float64(s.Score())
+		}
+	}
+	for row, s := range stats {
+		heat := 0.0
+		if worst > 0 {
+			heat = float64(s.Score()) / worst
+		}
+		color := tcell.ColorWhite
+		switch {
+		case heat >= 0.75:
+			color = tcell.ColorRed
+		case heat >= 0.5:
+			color = tcell.ColorOrange
+		}
+		name := s.Name
+		if name == "" {
+			name = s.ActivityID
+		}
+		table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(rtl(name))).SetTextColor(color))
+		table.SetCell(row+1, 1, tview.NewTableCell("| "+s.Type))
+		table.SetCell(row+1, 2, tview.NewTableCell(fmt.Sprintf("| %d", s.Count)))
+		table.SetCell(row+1, 3, tview.NewTableCell("| "+formatAge(s.Mean)))
+		table.SetCell(row+1, 4, tview.NewTableCell("| "+formatAge(s.Median)))
+		table.SetCell(row+1, 5, tview.NewTableCell("| "+formatAge(s.P90)))
+		table.SetCell(row+1, 6, tview.NewTableCell("| "+formatAge(s.P99)))
+		table.SetCell(row+1, 7, tview.NewTableCell("| "+formatAge(s.MeanWait)))
+		table.SetCell(row+1, 8, tview.NewTableCell("| "+formatAge(s.P90Wait)))
+		table.SetCell(row+1, 9, tview.NewTableCell("| "+strings.Repeat("█", int(heat*15+0.5))).SetTextColor(color))
+	}
+	table.Select(1, 0)
+
+	definitionID := mainDefinitionID(processes)
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'e' {
+			m.showBottleneckExport(definitionID, stats)
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Ranked by mean wait + duration • [red]red[white]: ≥75% of the worst • e export heat diagram • Esc back")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	m.pages.AddPage("bottlenecks", layout, true, true)
+	m.pages.SwitchToPage("bottlenecks")
+	m.app.SetFocus(table)
+}
+
+// -----------------------------------------------------------------------
+// showBottleneckExport writes the process diagram heat-coloured by the
+// bottleneck statistics
+func (m *BPMNManager) showBottleneckExport(definitionID string, stats []mining.ActivityStats) {
+	formats := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		formats[i] = string(f)
+	}
+	name := strings.NewReplacer(":", "_", "/", "_").Replace(definitionID) + "-bottlenecks"
+
+	form := tview.NewForm().
+		AddTextView("Definition:", definitionID, 50, 1, true, false).
+		AddDropDown("Format", formats, 2, nil).
+		AddInputField("Output file", name+".svg", 50, nil, nil)
+	form.GetFormItem(1).(*tview.DropDown).SetSelectedFunc(func(text string, index int) {
+		form.GetFormItem(2).(*tview.InputField).SetText(name + export.Format(text).Extension())
+	})
+
+	form.AddButton("Export", func() {
+		_, formatName := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		path := form.GetFormItem(2).(*tview.InputField).GetText()
+		go func() {
+			err := m.writeDiagram(definitionID, export.Format(formatName), export.Bottlenecks(stats), path)
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Export failed: " + err.Error())
+					return
+				}
+				m.showMessage("Diagram exported to " + path)
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("bottlenecks")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("bottlenecks")
+	})
+	form.SetBorder(true).SetTitle(" Export Bottleneck Diagram ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("bottleneck_export", form, true, true)
+	m.pages.SwitchToPage("bottleneck_export")
+}
+
+// -----------------------------------------------------------------------
+// writeDiagram exports the diagram of a definition with an overlay
+func (m *BPMNManager) writeDiagram(definitionID string, format export.Format, overlay *export.Overlay, path string) error {
+	model, err := loadDefinitionModel(m.apiClient, definitionID)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(file, model, format, overlay); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,261 @@
+package bpmn
+
+import (
+	"bpmn-manager/models"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Node is a flow node (activity, event or gateway) of a parsed process
+type Node struct {
+	ID         string
+	Name       string
+	Kind       string // BPMN element name, e.g. userTask, exclusiveGateway
+	Parent     string // enclosing sub process, empty at process level
+	AttachedTo string // host activity of a boundary event
+	Interrupt  bool   // boundary event cancels its host
+	Loop       bool   // multi-instance or loop activity, executed repeatedly per token
+	Default    string // default outgoing flow of a gateway
+	EventType  string // message, signal, timer or error
+	EventRef   string // referenced message/signal/error id
+	Incoming   []*Flow
+	Outgoing   []*Flow
+	Boundary   []*Node
+	Shape      *models.Bounds
+}
+
+// Flow is a sequence flow between two nodes
+type Flow struct {
+	ID        string
+	Name      string
+	Source    string
+	Target    string
+	Condition string
+	Waypoints []models.Waypoint
+}
+
+// Model is the flow graph of all processes in a BPMN definitions document
+type Model struct {
+	ProcessID   string
+	ProcessName string
+	Nodes       map[string]*Node
+	Order       []string // node ids in document order
+	Flows       []*Flow
+	Messages    map[string]string // message id -> name
+	Signals     map[string]string // signal id -> name
+}
+
+var nodeKinds = map[string]bool{
+	"task": true, "userTask": true, "serviceTask": true, "scriptTask": true,
+	"sendTask": true, "receiveTask": true, "manualTask": true, "businessRuleTask": true,
+	"callActivity": true, "subProcess": true, "adHocSubProcess": true, "transaction": true,
+	"startEvent": true, "endEvent": true, "intermediateCatchEvent": true,
+	"intermediateThrowEvent": true, "boundaryEvent": true,
+	"exclusiveGateway": true, "parallelGateway": true, "inclusiveGateway": true,
+	"eventBasedGateway": true, "complexGateway": true,
+}
+
+// -----------------------------------------------------------------------
+// Parse decodes a BPMN 2.0 XML document into a Model
+func Parse(data []byte) (*Model, error) {
+	var defs models.Definitions
+	if err := xml.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse BPMN: %v", err)
+	}
+	if len(defs.Processes) == 0 {
+		return nil, fmt.Errorf("failed to parse BPMN: no process found")
+	}
+
+	model := &Model{
+		Nodes:    make(map[string]*Node),
+		Messages: make(map[string]string),
+		Signals:  make(map[string]string),
+	}
+	for _, msg := range defs.Messages {
+		model.Messages[msg.ID] = msg.Name
+	}
+	for _, sig := range defs.Signals {
+		model.Signals[sig.ID] = sig.Name
+	}
+
+	for _, process := range defs.Processes {
+		if model.ProcessID == "" || (process.IsExecutable && model.ProcessName == "") {
+			model.ProcessID = process.ID
+			model.ProcessName = process.Name
+		}
+		model.addElements(process.FlowElements, "")
+	}
+
+	for _, flow := range model.Flows {
+		source, ok := model.Nodes[flow.Source]
+		if !ok {
+			return nil, fmt.Errorf("failed to parse BPMN: flow %s has unknown source %s", flow.ID, flow.Source)
+		}
+		target, ok := model.Nodes[flow.Target]
+		if !ok {
+			return nil, fmt.Errorf("failed to parse BPMN: flow %s has unknown target %s", flow.ID, flow.Target)
+		}
+		source.Outgoing = append(source.Outgoing, flow)
+		target.Incoming = append(target.Incoming, flow)
+	}
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		if host, ok := model.Nodes[node.AttachedTo]; ok {
+			host.Boundary = append(host.Boundary, node)
+		}
+	}
+
+	model.applyDiagram(defs.Diagrams)
+	return model, nil
+}
+
+// -----------------------------------------------------------------------
+// ParseFile reads and parses a BPMN file from disk
+func ParseFile(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// -----------------------------------------------------------------------
+func (m *Model) addElements(elements []models.FlowElement, parent string) {
+	for _, el := range elements {
+		kind := el.XMLName.Local
+		if kind == "sequenceFlow" {
+			m.Flows = append(m.Flows, &Flow{
+				ID:        el.ID,
+				Name:      el.Name,
+				Source:    el.SourceRef,
+				Target:    el.TargetRef,
+				Condition: strings.TrimSpace(el.ConditionExpression),
+			})
+			continue
+		}
+		if !nodeKinds[kind] || el.ID == "" {
+			continue
+		}
+
+		node := &Node{
+			ID:         el.ID,
+			Name:       el.Name,
+			Kind:       kind,
+			Parent:     parent,
+			AttachedTo: el.AttachedToRef,
+			Interrupt:  el.CancelActivity != "false",
+			Default:    el.Default,
+		}
+		switch {
+		case el.MessageDefinition != nil:
+			node.EventType, node.EventRef = "message", el.MessageDefinition.MessageRef
+		case el.SignalDefinition != nil:
+			node.EventType, node.EventRef = "signal", el.SignalDefinition.SignalRef
+		case el.TimerDefinition != nil:
+			node.EventType = "timer"
+		case el.ErrorDefinition != nil:
+			node.EventType, node.EventRef = "error", el.ErrorDefinition.ErrorRef
+		case kind == "receiveTask" && el.MessageRef != "":
+			node.EventType, node.EventRef = "message", el.MessageRef
+		}
+
+		for _, child := range el.Children {
+			switch child.XMLName.Local {
+			case "multiInstanceLoopCharacteristics", "standardLoopCharacteristics":
+				node.Loop = true
+			}
+		}
+
+		m.Nodes[node.ID] = node
+		m.Order = append(m.Order, node.ID)
+
+		if node.IsSubProcess() {
+			m.addElements(el.Children, node.ID)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------
+func (m *Model) applyDiagram(diagrams []models.BPMNDiagram) {
+	flows := make(map[string]*Flow, len(m.Flows))
+	for _, flow := range m.Flows {
+		flows[flow.ID] = flow
+	}
+	for _, diagram := range diagrams {
+		for _, shape := range diagram.Plane.Shapes {
+			if node, ok := m.Nodes[shape.BPMNElement]; ok {
+				bounds := shape.Bounds
+				node.Shape = &bounds
+			}
+		}
+		for _, edge := range diagram.Plane.Edges {
+			if flow, ok := flows[edge.BPMNElement]; ok {
+				flow.Waypoints = edge.Waypoints
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------
+// Node returns the node with the given id or nil
+func (m *Model) Node(id string) *Node {
+	return m.Nodes[id]
+}
+
+// -----------------------------------------------------------------------
+// HasDiagram reports whether every node has BPMNDI coordinates
+func (m *Model) HasDiagram() bool {
+	if len(m.Nodes) == 0 {
+		return false
+	}
+	for _, node := range m.Nodes {
+		if node.Shape == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------
+// StartNodes returns the process-level start events in document order
+func (m *Model) StartNodes() []*Node {
+	var starts []*Node
+	for _, id := range m.Order {
+		node := m.Nodes[id]
+		if node.Kind == "startEvent" && node.Parent == "" {
+			starts = append(starts, node)
+		}
+	}
+	return starts
+}
+
+// -----------------------------------------------------------------------
+// Label returns the node name, falling back to its id
+func (n *Node) Label() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.ID
+}
+
+// -----------------------------------------------------------------------
+func (n *Node) IsGateway() bool {
+	return strings.HasSuffix(n.Kind, "Gateway")
+}
+
+// -----------------------------------------------------------------------
+func (n *Node) IsEvent() bool {
+	return strings.HasSuffix(n.Kind, "Event")
+}
+
+// -----------------------------------------------------------------------
+func (n *Node) IsSubProcess() bool {
+	return n.Kind == "subProcess" || n.Kind == "adHocSubProcess" || n.Kind == "transaction"
+}
+
+// -----------------------------------------------------------------------
+func (n *Node) IsActivity() bool {
+	return !n.IsGateway() && !n.IsEvent()
+}
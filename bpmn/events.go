@@ -0,0 +1,75 @@
+package bpmn
+
+// Trigger is a message or signal an active instance is waiting for
+type Trigger struct {
+	EventType string // message or signal
+	Name      string // message/signal name used for correlation
+	Node      *Node  // the catching event or receive task
+	Via       string // how the wait arises: catch event, receive task, boundary or event gateway
+	ActiveID  string // the active activity that causes the wait
+}
+
+// -----------------------------------------------------------------------
+// WaitingTriggers returns the message and signal events that can fire
+// while the given activities are active: the activities themselves when
+// they are catch events or receive tasks, their boundary events, and the
+// events following an active event-based gateway
+func (m *Model) WaitingTriggers(active []string) []Trigger {
+	var triggers []Trigger
+	seen := make(map[string]bool)
+
+	add := func(node *Node, via, activeID string) {
+		if node == nil || seen[node.ID] {
+			return
+		}
+		if node.EventType != "message" && node.EventType != "signal" {
+			return
+		}
+		seen[node.ID] = true
+		triggers = append(triggers, Trigger{
+			EventType: node.EventType,
+			Name:      m.EventName(node),
+			Node:      node,
+			Via:       via,
+			ActiveID:  activeID,
+		})
+	}
+
+	for _, id := range active {
+		node := m.Nodes[id]
+		if node == nil {
+			continue
+		}
+		switch {
+		case node.Kind == "intermediateCatchEvent":
+			add(node, "catch event", id)
+		case node.Kind == "receiveTask":
+			add(node, "receive task", id)
+		case node.Kind == "eventBasedGateway":
+			for _, flow := range node.Outgoing {
+				add(m.Nodes[flow.Target], "event gateway", id)
+			}
+		}
+		for _, boundary := range node.Boundary {
+			add(boundary, "boundary", id)
+		}
+	}
+	return triggers
+}
+
+// -----------------------------------------------------------------------
+// EventName resolves the message or signal name a node listens for,
+// falling back to the reference id when the definition is missing
+func (m *Model) EventName(node *Node) string {
+	var name string
+	switch node.EventType {
+	case "message":
+		name = m.Messages[node.EventRef]
+	case "signal":
+		name = m.Signals[node.EventRef]
+	}
+	if name == "" {
+		return node.EventRef
+	}
+	return name
+}
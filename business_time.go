@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"time"
+
+	"bpmn-manager/calendar"
+)
+
+// -----------------------------------------------------------------------
+// calendarPath returns where the working calendar is configured
+func calendarPath() string {
+	return filepath.Join(viewsDir(), "calendar.json")
+}
+
+// -----------------------------------------------------------------------
+// loadCalendar reads the working calendar. A broken file falls back to
+// the default working week; the error is shown on the dashboard.
+func loadCalendar() (*calendar.Calendar, error) {
+	c, err := calendar.Load(calendarPath())
+	if err != nil {
+		return calendar.Default(), err
+	}
+	return c, nil
+}
+
+// -----------------------------------------------------------------------
+// clock returns the clock durations are measured with: the working
+// calendar in business-time mode, wall-clock time otherwise
+func (m *BPMNManager) clock() calendar.Clock {
+	if m.businessTime && m.calendar != nil {
+		return m.calendar
+	}
+	return calendar.WallClock{}
+}
+
+// -----------------------------------------------------------------------
+// setBusinessTime switches all duration and SLA computations between
+// business and wall-clock time
+func (m *BPMNManager) setBusinessTime(on bool) {
+	m.businessTime = on
+	m.sla.Clock = m.clock()
+	m.resetPredictor()
+	if m.dashboardData != nil {
+		m.drawDashboard()
+	}
+}
+
+// -----------------------------------------------------------------------
+// timeMode names the current clock for titles and messages
+func (m *BPMNManager) timeMode() string {
+	if m.businessTime {
+		return "business time"
+	}
+	return "wall-clock time"
+}
+
+// -----------------------------------------------------------------------
+// formatElapsed formats the time between start and end. Without both
+// timestamps it falls back to the engine's duration in seconds.
+func (m *BPMNManager) formatElapsed(start, end time.Time, fallbackSeconds int) string {
+	if start.IsZero() || end.IsZero() {
+		return formatDuration(fallbackSeconds)
+	}
+	return formatDuration(int(m.clock().Between(start, end).Seconds()))
+}
+
+// -----------------------------------------------------------------------
+// formatSince formats the time elapsed since t, empty for a zero time
+func (m *BPMNManager) formatSince(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatDuration(int(m.clock().Between(t, time.Now()).Seconds()))
+}
+
+// -----------------------------------------------------------------------
+// parseEngineTime reads a timestamp as the history API reports it, zero
+// when it is empty or malformed
+func parseEngineTime(text string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// -----------------------------------------------------------------------
+// addClockFlags adds -business and -calendar to a command; the returned
+// function picks the clock after the flags are parsed
+func addClockFlags(fs *flag.FlagSet) func() (calendar.Clock, error) {
+	business := fs.Bool("business", false, "measure durations in working hours")
+	path := fs.String("calendar", calendarPath(), "working hours and holidays for -business")
+	return func() (calendar.Clock, error) {
+		if !*business {
+			return calendar.WallClock{}, nil
+		}
+		c, err := calendar.Load(*path)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
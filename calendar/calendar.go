@@ -0,0 +1,382 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDays bounds the day-by-day walks, so a calendar without working
+// hours cannot loop forever
+const maxDays = 20 * 366
+
+// Clock measures working time. WallClock counts every second; a
+// Calendar only counts its working hours.
+type Clock interface {
+	// Add returns the time d of working time after from
+	Add(from time.Time, d time.Duration) time.Time
+	// Between returns the working time from a to b, negative if b is
+	// before a
+	Between(a, b time.Time) time.Duration
+}
+
+// WallClock counts every second
+type WallClock struct{}
+
+// -----------------------------------------------------------------------
+func (WallClock) Add(from time.Time, d time.Duration) time.Time { return from.Add(d) }
+
+// -----------------------------------------------------------------------
+func (WallClock) Between(a, b time.Time) time.Duration { return b.Sub(a) }
+
+// Span is a working period of a day, as offsets from midnight
+type Span struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Holiday is a non-working day. Date is "YYYY-MM-DD" for a single day or
+// "MM-DD" for every year, in the Gregorian or the Jalali calendar.
+type Holiday struct {
+	Date     string `json:"date"`
+	Calendar string `json:"calendar,omitempty"` // "gregorian" (default) or "jalali"
+	Name     string `json:"name,omitempty"`
+}
+
+// Calendar is a weekly working schedule with holidays
+type Calendar struct {
+	Location *time.Location
+	Hours    [7][]Span // indexed by time.Weekday, empty on weekends
+
+	dates     map[string]string // Gregorian "2006-01-02" → name
+	gregorian map[[2]int]string // yearly month/day → name
+	jalali    map[[2]int]string // yearly Jalali month/day → name
+}
+
+// file is the JSON form of a calendar
+type file struct {
+	Timezone     string              `json:"timezone"`
+	Workday      []string            `json:"workday"` // hours of days not listed in Hours
+	Hours        map[string][]string `json:"hours"`
+	Weekend      []string            `json:"weekend"`
+	Holidays     []Holiday           `json:"holidays"`
+	HolidayFiles []string            `json:"holidayFiles"` // relative to the calendar file
+}
+
+// -----------------------------------------------------------------------
+// Default is the Iranian working week: Saturday to Wednesday 08:00–16:00,
+// Thursday 08:00–13:00 and Friday off, in Tehran time
+func Default() *Calendar {
+	c := newCalendar(time.Local)
+	if location, err := time.LoadLocation("Asia/Tehran"); err == nil {
+		c.Location = location
+	}
+	full := []Span{{Start: 8 * time.Hour, End: 16 * time.Hour}}
+	for _, day := range []time.Weekday{time.Saturday, time.Sunday, time.Monday, time.Tuesday, time.Wednesday} {
+		c.Hours[day] = full
+	}
+	c.Hours[time.Thursday] = []Span{{Start: 8 * time.Hour, End: 13 * time.Hour}}
+	return c
+}
+
+// -----------------------------------------------------------------------
+func newCalendar(location *time.Location) *Calendar {
+	return &Calendar{
+		Location:  location,
+		dates:     map[string]string{},
+		gregorian: map[[2]int]string{},
+		jalali:    map[[2]int]string{},
+	}
+}
+
+// -----------------------------------------------------------------------
+// Load reads a calendar file. A missing file yields Default.
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid calendar %s: %w", path, err)
+	}
+	c, err := f.build(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// -----------------------------------------------------------------------
+func (f *file) build(dir string) (*Calendar, error) {
+	c := Default()
+	if f.Timezone != "" {
+		location, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		c.Location = location
+	}
+
+	// without a workday the days the config does not name keep their
+	// default hours, and a weekend replaces the default one
+	if f.Workday != nil {
+		workday, err := parseSpans(f.Workday)
+		if err != nil {
+			return nil, err
+		}
+		for day := range c.Hours {
+			c.Hours[day] = workday
+		}
+	} else if f.Weekend != nil {
+		for day := range c.Hours {
+			if len(c.Hours[day]) == 0 {
+				c.Hours[day] = c.Hours[time.Saturday]
+			}
+		}
+	}
+	if f.Hours != nil || f.Weekend != nil {
+		for name, hours := range f.Hours {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			if c.Hours[day], err = parseSpans(hours); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range f.Weekend {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			c.Hours[day] = nil
+		}
+	}
+
+	working := false
+	for _, hours := range c.Hours {
+		working = working || len(hours) > 0
+	}
+	if !working {
+		return nil, errors.New("no working hours on any day")
+	}
+
+	for _, holiday := range f.Holidays {
+		if err := c.AddHoliday(holiday); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range f.HolidayFiles {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		if err := c.LoadHolidays(name); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// -----------------------------------------------------------------------
+// LoadHolidays adds the holidays of a JSON file holding a list of
+// Holiday objects
+func (c *Calendar) LoadHolidays(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var holidays []Holiday
+	if err := json.Unmarshal(data, &holidays); err != nil {
+		return fmt.Errorf("invalid holiday list %s: %w", path, err)
+	}
+	for _, holiday := range holidays {
+		if err := c.AddHoliday(holiday); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// AddHoliday marks a date, or a date of every year, as non-working
+func (c *Calendar) AddHoliday(holiday Holiday) error {
+	parts := strings.Split(holiday.Date, "-")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid holiday date %q", holiday.Date)
+		}
+		numbers[i] = n
+	}
+
+	jalali := false
+	switch strings.ToLower(holiday.Calendar) {
+	case "", "gregorian":
+	case "jalali", "persian", "shamsi":
+		jalali = true
+	default:
+		return fmt.Errorf("unknown calendar %q for holiday %s", holiday.Calendar, holiday.Date)
+	}
+
+	switch {
+	case len(numbers) == 2 && jalali:
+		c.jalali[[2]int{numbers[0], numbers[1]}] = holiday.Name
+	case len(numbers) == 2:
+		c.gregorian[[2]int{numbers[0], numbers[1]}] = holiday.Name
+	case len(numbers) == 3 && jalali:
+		y, m, d := JalaliToGregorian(numbers[0], numbers[1], numbers[2])
+		c.dates[fmt.Sprintf("%04d-%02d-%02d", y, m, d)] = holiday.Name
+	case len(numbers) == 3:
+		c.dates[fmt.Sprintf("%04d-%02d-%02d", numbers[0], numbers[1], numbers[2])] = holiday.Name
+	default:
+		return fmt.Errorf("invalid holiday date %q", holiday.Date)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// Holiday returns the name of the holiday on the day of t, if it is one
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	t = t.In(c.Location)
+	y, m, d := t.Date()
+	if name, ok := c.dates[t.Format("2006-01-02")]; ok {
+		return name, true
+	}
+	if name, ok := c.gregorian[[2]int{int(m), d}]; ok {
+		return name, true
+	}
+	_, jm, jd := GregorianToJalali(y, int(m), d)
+	name, ok := c.jalali[[2]int{jm, jd}]
+	return name, ok
+}
+
+// -----------------------------------------------------------------------
+// WorkingSpans returns the working periods of the day of t as absolute
+// times, none on weekends and holidays
+func (c *Calendar) WorkingSpans(t time.Time) [][2]time.Time {
+	t = t.In(c.Location)
+	if _, holiday := c.Holiday(t); holiday {
+		return nil
+	}
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, c.Location)
+	spans := make([][2]time.Time, 0, len(c.Hours[t.Weekday()]))
+	for _, span := range c.Hours[t.Weekday()] {
+		spans = append(spans, [2]time.Time{midnight.Add(span.Start), midnight.Add(span.End)})
+	}
+	return spans
+}
+
+// -----------------------------------------------------------------------
+// nextDay returns midnight of the day after t
+func (c *Calendar) nextDay(t time.Time) time.Time {
+	y, m, d := t.In(c.Location).Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, c.Location)
+}
+
+// -----------------------------------------------------------------------
+// Between returns the working time from a to b
+func (c *Calendar) Between(a, b time.Time) time.Duration {
+	if b.Before(a) {
+		return -c.Between(b, a)
+	}
+	var total time.Duration
+	for day, n := a, 0; day.Before(b) && n < maxDays; day, n = c.nextDay(day), n+1 {
+		for _, span := range c.WorkingSpans(day) {
+			start, end := span[0], span[1]
+			if start.Before(a) {
+				start = a
+			}
+			if end.After(b) {
+				end = b
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+// -----------------------------------------------------------------------
+// Add returns the end of d working time starting at from. Negative
+// durations are applied as wall-clock time.
+func (c *Calendar) Add(from time.Time, d time.Duration) time.Time {
+	if d <= 0 {
+		return from.Add(d)
+	}
+	for day, n := from, 0; n < maxDays; day, n = c.nextDay(day), n+1 {
+		for _, span := range c.WorkingSpans(day) {
+			start, end := span[0], span[1]
+			if start.Before(from) {
+				start = from
+			}
+			if !end.After(start) {
+				continue
+			}
+			available := end.Sub(start)
+			if d <= available {
+				return start.Add(d)
+			}
+			d -= available
+		}
+	}
+	return from.Add(d)
+}
+
+// -----------------------------------------------------------------------
+func parseSpans(texts []string) ([]Span, error) {
+	spans := make([]Span, 0, len(texts))
+	for _, text := range texts {
+		start, end, ok := strings.Cut(text, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid hours %q, expected HH:MM-HH:MM", text)
+		}
+		from, err := parseClock(start)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseClock(end)
+		if err != nil {
+			return nil, err
+		}
+		if to <= from {
+			return nil, fmt.Errorf("invalid hours %q, end before start", text)
+		}
+		spans = append(spans, Span{Start: from, End: to})
+	}
+	return spans, nil
+}
+
+// -----------------------------------------------------------------------
+func parseClock(text string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		if strings.TrimSpace(text) == "24:00" {
+			return 24 * time.Hour, nil
+		}
+		return 0, fmt.Errorf("invalid time %q", text)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// -----------------------------------------------------------------------
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) || strings.EqualFold(day.String()[:3], name) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
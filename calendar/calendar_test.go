@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCalendar works Monday to Friday 09:00–12:00 and 13:00–17:00 in UTC,
+// with Wednesday 2024-05-08 and Nowruz off
+func testCalendar(t *testing.T) *Calendar {
+	c := newCalendar(time.UTC)
+	day := []Span{{Start: 9 * time.Hour, End: 12 * time.Hour}, {Start: 13 * time.Hour, End: 17 * time.Hour}}
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		c.Hours[weekday] = day
+	}
+	for _, holiday := range []Holiday{
+		{Date: "2024-05-08", Name: "company day"},
+		{Date: "01-01", Calendar: "jalali", Name: "Nowruz"},
+	} {
+		if err := c.AddHoliday(holiday); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+// at returns the time of the day of May 2024 in UTC
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestBetween(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		name string
+		a, b time.Time
+		want time.Duration
+	}{
+		{"within a span", at(6, 9, 30), at(6, 11, 0), 90 * time.Minute},
+		{"across lunch", at(6, 11, 0), at(6, 14, 0), 2 * time.Hour},
+		{"whole day", at(6, 0, 0), at(7, 0, 0), 7 * time.Hour},
+		{"evening to morning", at(6, 18, 0), at(7, 10, 0), time.Hour},
+		{"over a holiday", at(7, 16, 0), at(9, 10, 0), 2 * time.Hour},
+		{"over a weekend", at(10, 16, 0), at(13, 10, 0), 2 * time.Hour},
+		{"weekend only", at(11, 9, 0), at(12, 17, 0), 0},
+		{"reversed", at(6, 14, 0), at(6, 11, 0), -2 * time.Hour},
+		{"empty", at(6, 10, 0), at(6, 10, 0), 0},
+		{"working week", at(6, 0, 0), at(13, 0, 0), 4 * 7 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := c.Between(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Between = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		name string
+		from time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{"within a span", at(6, 9, 0), 2 * time.Hour, at(6, 11, 0)},
+		{"up to the end of a span", at(6, 9, 0), 3 * time.Hour, at(6, 12, 0)},
+		{"across lunch", at(6, 11, 0), 2 * time.Hour, at(6, 14, 0)},
+		{"before opening", at(6, 6, 0), time.Hour, at(6, 10, 0)},
+		{"to the next day", at(6, 16, 0), 2 * time.Hour, at(7, 10, 0)},
+		{"over a holiday", at(7, 16, 0), 2 * time.Hour, at(9, 10, 0)},
+		{"over a weekend", at(10, 16, 0), 2 * time.Hour, at(13, 10, 0)},
+		{"from the weekend", at(11, 12, 0), time.Hour, at(13, 10, 0)},
+		{"zero", at(11, 12, 0), 0, at(11, 12, 0)},
+		{"negative is wall clock", at(6, 10, 0), -time.Hour, at(6, 9, 0)},
+	}
+	for _, tt := range tests {
+		got := c.Add(tt.from, tt.d)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: Add = %v, want %v", tt.name, got, tt.want)
+		}
+		if tt.d > 0 {
+			if back := c.Between(tt.from, got); back != tt.d {
+				t.Errorf("%s: Between(from, Add(from, d)) = %v, want %v", tt.name, back, tt.d)
+			}
+		}
+	}
+}
+
+func TestHoliday(t *testing.T) {
+	c := testCalendar(t)
+	tests := []struct {
+		day  time.Time
+		name string
+	}{
+		{time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC), "company day"},
+		{time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), "Nowruz"},
+		{time.Date(2025, 3, 21, 10, 0, 0, 0, time.UTC), "Nowruz"},
+		{time.Date(2025, 5, 8, 10, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		name, ok := c.Holiday(tt.day)
+		if name != tt.name || ok != (tt.name != "") {
+			t.Errorf("Holiday(%s) = %q, %v; want %q", tt.day.Format("2006-01-02"), name, ok, tt.name)
+		}
+	}
+	if spans := c.WorkingSpans(at(8, 10, 0)); len(spans) != 0 {
+		t.Errorf("WorkingSpans on a holiday = %v", spans)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	holidays := `[{"date": "1403-01-13", "calendar": "jalali", "name": "Sizdah Bedar"}]`
+	if err := os.WriteFile(filepath.Join(dir, "holidays.json"), []byte(holidays), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := `{
+		"timezone": "UTC",
+		"workday": ["09:00-17:00"],
+		"hours": {"friday": ["09:00-13:00"]},
+		"weekend": ["saturday", "sunday"],
+		"holidayFiles": ["holidays.json"]
+	}`
+	path := filepath.Join(dir, "calendar.json")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Between(at(6, 0, 0), at(13, 0, 0)); got != 36*time.Hour {
+		t.Errorf("working week = %v, want 36h", got)
+	}
+	if name, ok := c.Holiday(time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)); !ok || name != "Sizdah Bedar" {
+		t.Errorf("1403-01-13 is not a holiday: %q", name)
+	}
+
+	// without a workday the other days keep their default hours
+	path = filepath.Join(dir, "weekend.json")
+	if err := os.WriteFile(path, []byte(`{"timezone": "UTC", "weekend": ["sunday"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Between(at(4, 9, 0), at(4, 12, 0)); got != 3*time.Hour {
+		t.Errorf("Saturday morning = %v, want 3h", got)
+	}
+	if got := c.Between(at(9, 0, 0), at(11, 0, 0)); got != 13*time.Hour {
+		t.Errorf("Thursday and Friday = %v, want 13h", got)
+	}
+	if got := c.Between(at(5, 0, 0), at(6, 0, 0)); got != 0 {
+		t.Errorf("Sunday = %v, want 0", got)
+	}
+
+	if c, err := Load(filepath.Join(dir, "missing.json")); err != nil || c == nil {
+		t.Errorf("missing calendar: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package calendar
+
+// The conversions below use the 33-year cycle arithmetic of the Solar
+// Hijri calendar, exact for the years 1178–1633 (1799–2254).
+
+// -----------------------------------------------------------------------
+// JalaliToGregorian converts a Solar Hijri date to the Gregorian calendar
+func JalaliToGregorian(jy, jm, jd int) (gy, gm, gd int) {
+	jy += 1595
+	days := -355668 + 365*jy + (jy/33)*8 + ((jy%33)+3)/4 + jd
+	if jm < 7 {
+		days += (jm - 1) * 31
+	} else {
+		days += (jm-7)*30 + 186
+	}
+
+	gy = 400 * (days / 146097)
+	days %= 146097
+	if days > 36524 {
+		days--
+		gy += 100 * (days / 36524)
+		days %= 36524
+		if days >= 365 {
+			days++
+		}
+	}
+	gy += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		gy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+
+	gd = days + 1
+	monthDays := [13]int{0, 31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if (gy%4 == 0 && gy%100 != 0) || gy%400 == 0 {
+		monthDays[2] = 29
+	}
+	for gm = 1; gm <= 12 && gd > monthDays[gm]; gm++ {
+		gd -= monthDays[gm]
+	}
+	return gy, gm, gd
+}
+
+// -----------------------------------------------------------------------
+// GregorianToJalali converts a Gregorian date to the Solar Hijri calendar
+func GregorianToJalali(gy, gm, gd int) (jy, jm, jd int) {
+	daysBefore := [12]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+	days := 355666 + 365*gy + (gy2+3)/4 - (gy2+99)/100 + (gy2+399)/400 + gd + daysBefore[gm-1]
+
+	jy = -1595 + 33*(days/12053)
+	days %= 12053
+	jy += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		jy += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+	if days < 186 {
+		return jy, 1 + days/31, 1 + days%31
+	}
+	return jy, 7 + (days-186)/30, 1 + (days-186)%30
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJalaliConversion(t *testing.T) {
+	tests := []struct {
+		jy, jm, jd int
+		gy, gm, gd int
+	}{
+		{1400, 1, 1, 2021, 3, 21},
+		{1402, 1, 1, 2023, 3, 21},
+		{1403, 1, 1, 2024, 3, 20},
+		{1403, 7, 1, 2024, 9, 22},
+		{1403, 12, 30, 2025, 3, 20}, // leap year
+		{1404, 1, 1, 2025, 3, 21},
+		{1399, 12, 30, 2021, 3, 20}, // leap year
+		{1402, 12, 29, 2024, 3, 19},
+		{1378, 10, 11, 2000, 1, 1},
+		{1357, 11, 22, 1979, 2, 11},
+	}
+	for _, tt := range tests {
+		if gy, gm, gd := JalaliToGregorian(tt.jy, tt.jm, tt.jd); gy != tt.gy || gm != tt.gm || gd != tt.gd {
+			t.Errorf("JalaliToGregorian(%d-%d-%d) = %d-%d-%d, want %d-%d-%d", tt.jy, tt.jm, tt.jd, gy, gm, gd, tt.gy, tt.gm, tt.gd)
+		}
+		if jy, jm, jd := GregorianToJalali(tt.gy, tt.gm, tt.gd); jy != tt.jy || jm != tt.jm || jd != tt.jd {
+			t.Errorf("GregorianToJalali(%d-%d-%d) = %d-%d-%d, want %d-%d-%d", tt.gy, tt.gm, tt.gd, jy, jm, jd, tt.jy, tt.jm, tt.jd)
+		}
+	}
+}
+
+func TestJalaliRoundTrip(t *testing.T) {
+	day := time.Date(1990, 1, 1, 12, 0, 0, 0, time.UTC)
+	prev := [3]int{}
+	for i := 0; i < 60*366; i++ {
+		y, m, d := day.Date()
+		jy, jm, jd := GregorianToJalali(y, int(m), d)
+		if gy, gm, gd := JalaliToGregorian(jy, jm, jd); gy != y || gm != int(m) || gd != d {
+			t.Fatalf("%s → %d-%d-%d → %d-%d-%d", day.Format("2006-01-02"), jy, jm, jd, gy, gm, gd)
+		}
+		// consecutive days are consecutive Jalali dates
+		next := [3]int{jy, jm, jd}
+		if i > 0 && jd != prev[2]+1 && !(jd == 1 && (jm == prev[1]+1 || (jm == 1 && prev[1] == 12 && jy == prev[0]+1))) {
+			t.Fatalf("%s: %v follows %v", day.Format("2006-01-02"), next, prev)
+		}
+		prev = next
+		day = day.AddDate(0, 0, 1)
+	}
+}
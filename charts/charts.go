@@ -0,0 +1,152 @@
+package charts
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// sparks are the block characters of a sparkline, lowest first
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// eighths are the partial blocks that end a bar, one eighth to seven
+var eighths = []rune("▏▎▍▌▋▊▉")
+
+// -----------------------------------------------------------------------
+// Sparkline draws values as a row of block characters scaled between
+// zero and the largest value. Values beyond width are averaged in
+// consecutive groups; NaN marks a gap and is drawn as a space.
+func Sparkline(values []float64, width int) string {
+	values = Compress(values, width)
+	top := 0.0
+	for _, v := range values {
+		if !math.IsNaN(v) && v > top {
+			top = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case top == 0 || v <= 0:
+			b.WriteRune(sparks[0])
+		default:
+			i := int(math.Ceil(v/top*float64(len(sparks)))) - 1
+			if i < 0 {
+				i = 0
+			}
+			b.WriteRune(sparks[i])
+		}
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+// Compress averages consecutive groups of values so that at most width
+// remain, ignoring NaN; a group of NaN only stays NaN
+func Compress(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+	size := (len(values) + width - 1) / width
+	out := make([]float64, 0, width)
+	for i := 0; i < len(values); i += size {
+		end := i + size
+		if end > len(values) {
+			end = len(values)
+		}
+		out = append(out, Mean(values[i:end]))
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------
+// Mean averages values, ignoring NaN; NaN when there are none
+func Mean(values []float64) float64 {
+	total, n := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			total += v
+			n++
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return total / float64(n)
+}
+
+// -----------------------------------------------------------------------
+// Bar draws value as a horizontal bar of full and eighth blocks, width
+// columns standing for max. Positive values get at least an eighth.
+func Bar(value, max float64, width int) string {
+	if max <= 0 || value <= 0 || width <= 0 {
+		return ""
+	}
+	units := int(math.Round(math.Min(value/max, 1) * float64(width*8)))
+	if units == 0 {
+		units = 1
+	}
+	bar := strings.Repeat("█", units/8)
+	if units%8 > 0 {
+		bar += string(eighths[units%8-1])
+	}
+	return bar
+}
+
+// -----------------------------------------------------------------------
+// Day returns local midnight of the day t falls on
+func Day(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// -----------------------------------------------------------------------
+// dayIndex returns the day of t counted from start, false outside the
+// days days from start
+func dayIndex(t, start time.Time, days int) (int, bool) {
+	if t.IsZero() || t.Before(start) {
+		return 0, false
+	}
+	// counting calendar days keeps daylight saving days in place
+	y, m, d := t.Local().Date()
+	i := int(time.Date(y, m, d, 12, 0, 0, 0, time.UTC).Sub(
+		time.Date(start.Year(), start.Month(), start.Day(), 12, 0, 0, 0, time.UTC)).Hours() / 24)
+	return i, i < days
+}
+
+// -----------------------------------------------------------------------
+// CountByDay counts the times on each of the days days from start
+func CountByDay(times []time.Time, start time.Time, days int) []float64 {
+	counts := make([]float64, days)
+	for _, t := range times {
+		if i, ok := dayIndex(t, start, days); ok {
+			counts[i]++
+		}
+	}
+	return counts
+}
+
+// -----------------------------------------------------------------------
+// MeanByDay averages the values whose times fall on each of the days
+// days from start; days without any are NaN
+func MeanByDay(times []time.Time, values []float64, start time.Time, days int) []float64 {
+	totals := make([]float64, days)
+	counts := make([]int, days)
+	for j, t := range times {
+		if i, ok := dayIndex(t, start, days); ok {
+			totals[i] += values[j]
+			counts[i]++
+		}
+	}
+	means := make([]float64, days)
+	for i := range means {
+		means[i] = math.NaN()
+		if counts[i] > 0 {
+			means[i] = totals[i] / float64(counts[i])
+		}
+	}
+	return means
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"bpmn-manager/api"
+	"bpmn-manager/bpmn"
+	"bpmn-manager/export"
+)
+
+const defaultBaseURL = "http://192.168.164.150:8086"
+
+// cliCommand is a non-interactive subcommand run instead of the TUI
+type cliCommand struct {
+	summary string
+	run     func(args []string) error
+}
+
+var cliCommands = map[string]cliCommand{
+	"export":      {"Export a process diagram as Mermaid, Graphviz DOT or SVG", runExportCommand},
+	"tasks":       {"List user tasks, optionally with -filter", runTasksCommand},
+	"instances":   {"List running process instances, optionally with -filter", runInstancesCommand},
+	"sla":         {"Report tasks past their SLA deadline (sla report)", runSLACommand},
+	"bottlenecks": {"Per-activity duration and waiting statistics as a table, CSV or JSON", runBottlenecksCommand},
+	"eventlog":    {"Export process histories as an XES or CSV event log", runEventLogCommand},
+	"anomalies":   {"Report stuck and abnormal running instances; exits 2 when any are found", runAnomaliesCommand},
+}
+
+// exitStatus is returned by a command that ran fine but must exit with a
+// non-zero status, e.g. because it found problems
+type exitStatus int
+
+func (s exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(s)) }
+
+// -----------------------------------------------------------------------
+// runCLI dispatches os.Args to a subcommand. It reports false when the
+// arguments do not name a subcommand and the TUI should start instead.
+func runCLI(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(os.Stdout)
+		return true
+	}
+	cmd, ok := cliCommands[args[0]]
+	if !ok {
+		return false
+	}
+	if err := cmd.run(args[1:]); err != nil {
+		var status exitStatus
+		if errors.As(err, &status) {
+			os.Exit(int(status))
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  bpmn-manager [API_BASE_URL]        start the interactive manager")
+	fmt.Fprintln(w, "  bpmn-manager <command> [flags]     run a command (use -h for its flags)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, cliCommands[name].summary)
+	}
+}
+
+// -----------------------------------------------------------------------
+// newFlagSet creates a flag set with the shared -url flag
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	baseURL := defaultBaseURL
+	if env := os.Getenv("BPMN_MANAGER_URL"); env != "" {
+		baseURL = env
+	}
+	urlFlag := fs.String("url", baseURL, "API base URL (or set BPMN_MANAGER_URL)")
+	return fs, urlFlag
+}
+
+// -----------------------------------------------------------------------
+// createOutput opens the named file, or stdout for "" and "-"
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// -----------------------------------------------------------------------
+func runExportCommand(args []string) error {
+	fs, baseURL := newFlagSet("export")
+	format := fs.String("format", "", "output format: mermaid, dot or svg (default from -o extension, else mermaid)")
+	definitionID := fs.String("definition", "", "process definition id to export")
+	instanceID := fs.String("instance", "", "process instance id; exports its definition with the executed path")
+	file := fs.String("file", "", "read the BPMN model from a local .bpmn file instead of the API")
+	stats := fs.Bool("stats", false, "overlay per-activity statistics from completed instances")
+	output := fs.String("o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	formatName := *format
+	if formatName == "" {
+		formatName = string(export.FormatMermaid)
+		if ext := filepath.Ext(*output); ext != "" {
+			formatName = ext
+		}
+	}
+	exportFormat, err := export.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewAPIClient(*baseURL)
+	var model *bpmn.Model
+	var overlay *export.Overlay
+
+	switch {
+	case *file != "":
+		model, err = bpmn.ParseFile(*file)
+	case *instanceID != "":
+		var diagram *processDiagram
+		diagram, err = loadInstanceDiagram(client, *instanceID)
+		if err == nil {
+			model = diagram.model
+			*definitionID = diagram.details.ProcessDefinitionId
+			overlay = export.InstancePath(model, diagram.details)
+		}
+	case *definitionID != "":
+		model, err = loadDefinitionModel(client, *definitionID)
+	default:
+		return fmt.Errorf("one of -definition, -instance or -file is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	if *stats {
+		if *definitionID == "" {
+			return fmt.Errorf("-stats needs -definition or -instance")
+		}
+		overlay, err = loadStatisticsOverlay(client, *definitionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(out, model, exportFormat, overlay); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bpmn-manager/anomaly"
+	"bpmn-manager/api"
+)
+
+// anomalyRow is one finding in the JSON output of anomalies
+type anomalyRow struct {
+	Kind             string  `json:"kind"`
+	InstanceID       string  `json:"processInstanceId"`
+	ProcessKey       string  `json:"processDefinitionKey"`
+	ActivityID       string  `json:"activityId,omitempty"`
+	Activity         string  `json:"activityName,omitempty"`
+	ElapsedSeconds   float64 `json:"elapsedSeconds,omitempty"`
+	ThresholdSeconds float64 `json:"thresholdSeconds,omitempty"`
+	Count            int     `json:"count,omitempty"`
+	Limit            int     `json:"limit,omitempty"`
+	Learned          bool    `json:"learned"`
+	Severity         float64 `json:"severity"`
+}
+
+// -----------------------------------------------------------------------
+// runAnomaliesCommand reports stuck and abnormal running instances. It
+// exits with status 2 when it finds any, so it can drive alerts.
+func runAnomaliesCommand(args []string) error {
+	fs, baseURL := newFlagSet("anomalies")
+	configPath := fs.String("config", anomalyConfigPath(), "fixed activity limits and detector settings")
+	key := fs.String("key", "", "only check instances of this process definition key")
+	clock := addClockFlags(fs)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	config, err := anomaly.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if config.Clock, err = clock(); err != nil {
+		return err
+	}
+
+	found, checked, err := detectAnomalies(api.NewAPIClient(*baseURL), config, *key, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		rows := make([]anomalyRow, len(found))
+		for i, a := range found {
+			rows[i] = anomalyRow{
+				Kind:             a.Kind.String(),
+				InstanceID:       a.InstanceID,
+				ProcessKey:       a.ProcessKey,
+				ActivityID:       a.ActivityID,
+				Activity:         a.Activity,
+				ElapsedSeconds:   a.Elapsed.Seconds(),
+				ThresholdSeconds: a.Threshold.Seconds(),
+				Count:            a.Count,
+				Limit:            a.Limit,
+				Learned:          a.Learned,
+				Severity:         a.Severity(),
+			}
+		}
+		if err := writeJSON(os.Stdout, rows); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tINSTANCE\tPROCESS\tFINDING")
+		for _, a := range found {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Kind, a.InstanceID, a.ProcessKey, anomalyText(a))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("%d findings in %d running instances\n", len(found), checked)
+	}
+
+	if len(found) > 0 {
+		return exitStatus(2)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"bpmn-manager/api"
+	"bpmn-manager/export"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+)
+
+// bottleneckRow is one activity in the JSON and CSV output of
+// bottlenecks; durations are in seconds
+type bottleneckRow struct {
+	ActivityID    string  `json:"activityId"`
+	Name          string  `json:"activityName"`
+	Type          string  `json:"activityType"`
+	Count         int     `json:"count"`
+	MeanSeconds   float64 `json:"meanSeconds"`
+	MedianSeconds float64 `json:"medianSeconds"`
+	P90Seconds    float64 `json:"p90Seconds"`
+	P99Seconds    float64 `json:"p99Seconds"`
+	MaxSeconds    float64 `json:"maxSeconds"`
+	WaitSeconds   float64 `json:"meanWaitSeconds"`
+	WaitP90       float64 `json:"p90WaitSeconds"`
+}
+
+// -----------------------------------------------------------------------
+func runBottlenecksCommand(args []string) error {
+	fs, baseURL := newFlagSet("bottlenecks")
+	definitionID := fs.String("definition", "", "process definition id to analyse")
+	key := fs.String("key", "", "process definition key to analyse, across versions")
+	format := fs.String("format", "table", "output format: table, csv or json")
+	output := fs.String("o", "", "output file (default stdout)")
+	diagram := fs.String("diagram", "", "also write the heat-coloured diagram to this .svg, .mmd or .dot file")
+	clock := addClockFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*definitionID == "") == (*key == "") {
+		return fmt.Errorf("exactly one of -definition or -key is required")
+	}
+	c, err := clock()
+	if err != nil {
+		return err
+	}
+
+	client := api.NewAPIClient(*baseURL)
+	completed, err := client.GetCompletedProcesses()
+	if err != nil {
+		return err
+	}
+	var histories []models.ProcessDetails
+	for _, process := range completed {
+		if (*definitionID != "" && process.ProcessDefinitionId == *definitionID) ||
+			(*key != "" && process.ProcessDefinitionKey == *key) {
+			histories = append(histories, process)
+		}
+	}
+	if len(histories) == 0 {
+		return fmt.Errorf("no completed instances found")
+	}
+	stats := mining.Bottlenecks(histories, c)
+
+	if *diagram != "" {
+		diagramFormat, err := export.ParseFormat(filepath.Ext(*diagram))
+		if err != nil {
+			return err
+		}
+		id := *definitionID
+		if id == "" {
+			id = mainDefinitionID(histories)
+		}
+		model, err := loadDefinitionModel(client, id)
+		if err != nil {
+			return err
+		}
+		out, err := createOutput(*diagram)
+		if err != nil {
+			return err
+		}
+		if err := export.Write(out, model, diagramFormat, export.Bottlenecks(stats)); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeBottlenecks(out, *format, stats); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// -----------------------------------------------------------------------
+func writeBottlenecks(w io.Writer, format string, stats []mining.ActivityStats) error {
+	rows := make([]bottleneckRow, len(stats))
+	for i, s := range stats {
+		rows[i] = bottleneckRow{
+			ActivityID:    s.ActivityID,
+			Name:          s.Name,
+			Type:          s.Type,
+			Count:         s.Count,
+			MeanSeconds:   s.Mean.Seconds(),
+			MedianSeconds: s.Median.Seconds(),
+			P90Seconds:    s.P90.Seconds(),
+			P99Seconds:    s.P99.Seconds(),
+			MaxSeconds:    s.Max.Seconds(),
+			WaitSeconds:   s.MeanWait.Seconds(),
+			WaitP90:       s.P90Wait.Seconds(),
+		}
+	}
+
+	switch format {
+	case "json":
+		return writeJSON(w, rows)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"activityId", "activityName", "activityType", "count", "meanSeconds", "medianSeconds",
+			"p90Seconds", "p99Seconds", "maxSeconds", "meanWaitSeconds", "p90WaitSeconds"})
+		seconds := func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+		for _, row := range rows {
+			cw.Write([]string{row.ActivityID, row.Name, row.Type, strconv.Itoa(row.Count),
+				seconds(row.MeanSeconds), seconds(row.MedianSeconds), seconds(row.P90Seconds), seconds(row.P99Seconds),
+				seconds(row.MaxSeconds), seconds(row.WaitSeconds), seconds(row.WaitP90)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACTIVITY\tNAME\tCOUNT\tMEAN\tMEDIAN\tP90\tP99\tWAIT\tWAIT P90")
+		round := func(d time.Duration) time.Duration { return d.Round(time.Second) }
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ActivityID, s.Name, s.Count,
+				round(s.Mean), round(s.Median), round(s.P90), round(s.P99), round(s.MeanWait), round(s.P90Wait))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q, expected table, csv or json", format)
+}
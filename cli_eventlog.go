@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bpmn-manager/api"
+	"bpmn-manager/eventlog"
+)
+
+// -----------------------------------------------------------------------
+func runEventLogCommand(args []string) error {
+	fs, baseURL := newFlagSet("eventlog")
+	format := fs.String("format", "", "xes or csv (default from -o extension, else xes)")
+	output := fs.String("o", "", "output file (default stdout)")
+	key := fs.String("key", "", "only instances of this process definition key")
+	completed := fs.Bool("completed", true, "include completed instances")
+	running := fs.Bool("running", false, "include running instances")
+	names := fs.String("variables", "", "comma-separated process variables added as CSV columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*completed && !*running {
+		return fmt.Errorf("nothing to export: both -completed and -running are off")
+	}
+
+	formatName := *format
+	if formatName == "" {
+		formatName = string(eventlog.FormatXES)
+		if ext := filepath.Ext(*output); ext != "" {
+			formatName = ext
+		}
+	}
+	logFormat, err := eventlog.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+	var variableNames []string
+	for _, name := range strings.Split(*names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			variableNames = append(variableNames, name)
+		}
+	}
+
+	source := eventLogSource{completed: *completed, running: *running, key: *key}
+	cases, err := writeEventLog(api.NewAPIClient(*baseURL), source, logFormat, variableNames, *output, func(int) {})
+	if err != nil {
+		return err
+	}
+	if *output != "" && *output != "-" {
+		fmt.Fprintf(os.Stderr, "%d cases written to %s\n", cases, *output)
+	}
+	return nil
+}
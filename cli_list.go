@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"bpmn-manager/api"
+	"bpmn-manager/query"
+)
+
+// -----------------------------------------------------------------------
+// parseFilter compiles a -filter flag, pointing at syntax errors
+func parseFilter(text string, schema *query.Schema) (*query.Query, error) {
+	q, err := query.Parse(text, schema)
+	if syntaxErr, ok := err.(*query.SyntaxError); ok {
+		return nil, fmt.Errorf("invalid filter:\n%s", syntaxErr.Pointer(text))
+	}
+	return q, err
+}
+
+// -----------------------------------------------------------------------
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// -----------------------------------------------------------------------
+func runTasksCommand(args []string) error {
+	fs, baseURL := newFlagSet("tasks")
+	filter := fs.String("filter", "", `filter expression, e.g. assignee = "ali" and created < -2d`)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	q, err := parseFilter(*filter, query.TaskSchema)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := api.NewAPIClient(*baseURL).GetUserTasksWhere(q.Params())
+	if err != nil {
+		return err
+	}
+	tasks = query.FilterTasks(q, tasks)
+
+	if *asJSON {
+		return writeJSON(os.Stdout, tasks)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tKEY\tPROCESS\tASSIGNEE\tCREATED")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", task.ID, task.Name, task.TaskDefinitionKey,
+			task.ProcessID, task.Assignee, task.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+// -----------------------------------------------------------------------
+func runInstancesCommand(args []string) error {
+	fs, baseURL := newFlagSet("instances")
+	filter := fs.String("filter", "", `filter expression, e.g. key = "invoice" and started < -7d`)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	q, err := parseFilter(*filter, query.ProcessSchema)
+	if err != nil {
+		return err
+	}
+
+	processes, err := api.NewAPIClient(*baseURL).GetRunningProcessesWhere(q.Params())
+	if err != nil {
+		return err
+	}
+	processes = query.FilterProcesses(q, processes)
+
+	if *asJSON {
+		return writeJSON(os.Stdout, processes)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tKEY\tACTIVITY\tSTARTED")
+	for _, process := range processes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", process.ProcessID, process.Status, process.ProcessDefinitionKey,
+			process.CurrentActivity, process.StartTime.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
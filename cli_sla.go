@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bpmn-manager/api"
+	"bpmn-manager/query"
+	"bpmn-manager/sla"
+)
+
+// slaReportRow is one breach in the JSON output of sla report
+type slaReportRow struct {
+	TaskID            string    `json:"taskId"`
+	Name              string    `json:"name"`
+	TaskDefinitionKey string    `json:"taskDefinitionKey"`
+	ProcessInstanceID string    `json:"processInstanceId"`
+	Assignee          string    `json:"assignee"`
+	Status            string    `json:"status"`
+	Deadline          time.Time `json:"deadline"`
+	RemainingSeconds  int64     `json:"remainingSeconds"`
+}
+
+// -----------------------------------------------------------------------
+func runSLACommand(args []string) error {
+	if len(args) == 0 || args[0] != "report" {
+		return fmt.Errorf("usage: bpmn-manager sla report [-config FILE] [-business] [-calendar FILE] [-warnings] [-filter EXPR] [-json]")
+	}
+
+	fs, baseURL := newFlagSet("sla report")
+	configPath := fs.String("config", slaConfigPath(), "SLA targets per task definition key")
+	clock := addClockFlags(fs)
+	warnings := fs.Bool("warnings", false, "also list tasks close to their deadline")
+	filter := fs.String("filter", "", `only check tasks matching a filter expression`)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	config, err := sla.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if config.Clock, err = clock(); err != nil {
+		return err
+	}
+	q, err := parseFilter(*filter, query.TaskSchema)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := api.NewAPIClient(*baseURL).GetUserTasksWhere(q.Params())
+	if err != nil {
+		return err
+	}
+	tasks = query.FilterTasks(q, tasks)
+	breaches := config.Breaches(tasks, time.Now(), *warnings)
+
+	if *asJSON {
+		rows := make([]slaReportRow, len(breaches))
+		for i, e := range breaches {
+			rows[i] = slaReportRow{
+				TaskID:            e.Task.ID,
+				Name:              e.Task.Name,
+				TaskDefinitionKey: e.Task.TaskDefinitionKey,
+				ProcessInstanceID: e.Task.ProcessID,
+				Assignee:          e.Task.Assignee,
+				Status:            e.Status.String(),
+				Deadline:          e.Deadline,
+				RemainingSeconds:  int64(e.Remaining / time.Second),
+			}
+		}
+		return writeJSON(os.Stdout, rows)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tKEY\tASSIGNEE\tDEADLINE\tSTATUS")
+	for _, e := range breaches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Task.ID, e.Task.Name, e.Task.TaskDefinitionKey,
+			e.Task.Assignee, e.Deadline.Format("2006-01-02 15:04"), slaText(e))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d of %d tasks breached\n", len(breaches), len(tasks))
+	return nil
+}
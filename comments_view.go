@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// downloadsDir returns where attachments are saved by default
+func downloadsDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		dir := filepath.Join(home, "Downloads")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		return home
+	}
+	return "."
+}
+
+// -----------------------------------------------------------------------
+// showCommentForm asks for a multiline comment and posts it with add.
+// done is called on the UI thread once the comment is saved.
+func (m *BPMNManager) showCommentForm(title string, add func(message string) error, done func()) {
+	text := tview.NewTextArea().SetLabel("Comment").SetSize(8, 0)
+	form := tview.NewForm().AddFormItem(text)
+
+	form.AddButton("Save", func() {
+		message := strings.TrimSpace(text.GetText())
+		if message == "" {
+			m.showError("Comment is empty")
+			return
+		}
+		go func() {
+			err := add(message)
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Failed to save comment: " + err.Error())
+					return
+				}
+				done()
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			done()
+		})
+
+	form.SetCancelFunc(done)
+	form.SetBorder(true).SetTitle(" " + tview.Escape(title) + " ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("comment_form", form, true, true)
+	m.pages.SwitchToPage("comment_form")
+}
+
+// -----------------------------------------------------------------------
+// showInstanceComments lists the comments of a process instance
+func (m *BPMNManager) showInstanceComments(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	go func() {
+		comments, err := m.apiClient.GetProcessInstanceComments(instanceID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load comments: " + err.Error())
+				return
+			}
+
+			view := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true).SetScrollable(true)
+			view.SetText(describeComments(comments, nil))
+			view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				switch event.Rune() {
+				case 'a':
+					m.showCommentForm("Comment on "+instanceID, func(message string) error {
+						_, err := m.apiClient.AddProcessInstanceComment(instanceID, message)
+						return err
+					}, func() {
+						m.showInstanceComments(instanceID)
+					})
+					return nil
+				case 'r':
+					m.showInstanceComments(instanceID)
+					return nil
+				}
+				return event
+			})
+
+			footer := tview.NewTextView().SetText("a add comment • r reload • Esc back")
+			layout := tview.NewFlex().SetDirection(tview.FlexRow).
+				AddItem(view, 0, 1, true).
+				AddItem(footer, 1, 0, false)
+			layout.SetBorder(true).SetTitle(fmt.Sprintf(" Comments • %s (%d) ", instanceID, len(comments))).SetBorderColor(tcell.Color102)
+			m.backOnEsc(layout, "main")
+
+			m.pages.AddPage("instance_comments", layout, true, true)
+			m.pages.SwitchToPage("instance_comments")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// showUploadAttachment picks a local file and attaches it to a task
+func (m *BPMNManager) showUploadAttachment(task models.UserTask) {
+	m.showFilePicker("Attach File", ".", func(path string) {
+		form := tview.NewForm().
+			AddTextView("File", tview.Escape(path), 60, 1, true, false).
+			AddInputField("Description", "", 60, nil, nil)
+
+		form.AddButton("Upload", func() {
+			description := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+			form.SetTitle(" Uploading... ")
+			go func() {
+				_, err := m.apiClient.UploadTaskAttachment(task.ID, path, description)
+				m.app.QueueUpdateDraw(func() {
+					if err != nil {
+						m.showError("Failed to upload attachment: " + err.Error())
+						return
+					}
+					m.showTaskDetail(task)
+				})
+			}()
+		}).
+			AddButton("Cancel", func() {
+				m.pages.SwitchToPage("task_detail")
+			})
+
+		form.SetCancelFunc(func() {
+			m.pages.SwitchToPage("task_detail")
+		})
+		form.SetBorder(true).SetTitle(" Attach File ").SetBorderColor(tcell.Color102)
+		m.pages.AddPage("attachment_upload", form, true, true)
+		m.pages.SwitchToPage("attachment_upload")
+	})
+}
+
+// -----------------------------------------------------------------------
+// showDownloadAttachment saves one of the task's attachments to disk
+func (m *BPMNManager) showDownloadAttachment(task models.UserTask, attachments []models.Attachment) {
+	if len(attachments) == 0 {
+		m.showError("The task has no attachments")
+		return
+	}
+
+	list := tview.NewList()
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	for _, attachment := range attachments {
+		attachment := attachment
+		list.AddItem("📎 "+tview.Escape(attachment.Name), formatSize(attachment.Size)+" "+tview.Escape(attachment.Description), 0, func() {
+			m.showSaveAttachmentForm(task, attachment)
+		})
+	}
+
+	list.SetDoneFunc(func() {
+		m.pages.SwitchToPage("task_detail")
+	})
+	list.SetBorder(true).SetTitle(" Download Attachment ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("attachment_download", list, true, true)
+	m.pages.SwitchToPage("attachment_download")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showSaveAttachmentForm(task models.UserTask, attachment models.Attachment) {
+	form := tview.NewForm().
+		AddInputField("Save as", filepath.Join(downloadsDir(), filepath.Base(attachment.Name)), 70, nil, nil)
+
+	form.AddButton("Save", func() {
+		path := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		form.SetTitle(" Downloading... ")
+		go func() {
+			n, err := m.saveAttachment(task.ID, attachment.ID, path)
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Failed to download attachment: " + err.Error())
+					return
+				}
+				m.showMessage(fmt.Sprintf("Saved %s (%s)", path, formatSize(n)))
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("task_detail")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("task_detail")
+	})
+	form.SetBorder(true).SetTitle(" Save Attachment ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("attachment_save", form, true, true)
+	m.pages.SwitchToPage("attachment_save")
+}
+
+// -----------------------------------------------------------------------
+// saveAttachment streams an attachment into a new file, removing it
+// again if the download fails
+func (m *BPMNManager) saveAttachment(taskID, attachmentID, path string) (int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := m.apiClient.DownloadTaskAttachment(taskID, attachmentID, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showConformance replays completed instances of the chosen process on
+// its BPMN model to find the ones that deviated from the modelled flow
+func (m *BPMNManager) showConformance() {
+	go func() {
+		processes, err := m.apiClient.GetCompletedProcesses()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load completed processes: " + err.Error())
+				return
+			}
+			if len(processes) == 0 {
+				m.showError("There are no completed process instances to check")
+				return
+			}
+			m.chooseDefinition("conformance", processes, m.showConformanceReport)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// showConformanceReport loads the model of every definition version the
+// instances ran on and replays them
+func (m *BPMNManager) showConformanceReport(key string, processes []models.ProcessDetails) {
+	go func() {
+		byDefinition := make(map[string]*bpmn.Model)
+		var err error
+		for _, process := range processes {
+			id := process.ProcessDefinitionId
+			if _, loaded := byDefinition[id]; loaded || id == "" {
+				continue
+			}
+			if byDefinition[id], err = loadDefinitionModel(m.apiClient, id); err != nil {
+				err = fmt.Errorf("%s: %v", id, err)
+				break
+			}
+		}
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load the process model: " + err.Error())
+				return
+			}
+			m.createConformanceView(key, mining.Check(byDefinition, processes))
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createConformanceView(key string, replays []mining.Replay) {
+	conforming := 0
+	for _, r := range replays {
+		if r.Conforms() {
+			conforming++
+		}
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	showAll := false
+	var shown []mining.Replay
+	fill := func() {
+		shown = shown[:0]
+		for _, r := range replays {
+			if showAll || !r.Conforms() {
+				shown = append(shown, r)
+			}
+		}
+		table.Clear()
+		table.SetTitle(fmt.Sprintf(" Conformance • %s • %d of %d instances conform • mean fitness %.2f ",
+			key, conforming, len(replays), mining.MeanFitness(replays)))
+		headers := []string{"Instance", "|Fitness", "|Missing", "|Remaining", "|Unexpected", "|Skipped"}
+		for i, header := range headers {
+			table.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		for row, r := range shown {
+			color := tcell.ColorWhite
+			switch {
+			case r.Fitness < 0.8:
+				color = tcell.ColorRed
+			case !r.Conforms():
+				color = tcell.ColorOrange
+			}
+			unexpected := append(append([]string(nil), r.Unexpected...), r.Unknown...)
+			table.SetCell(row+1, 0, tview.NewTableCell(r.Instance.ID).SetTextColor(color))
+			table.SetCell(row+1, 1, tview.NewTableCell(fmt.Sprintf("| %.2f", r.Fitness)).SetTextColor(color))
+			table.SetCell(row+1, 2, tview.NewTableCell(fmt.Sprintf("| %d", r.Missing)))
+			table.SetCell(row+1, 3, tview.NewTableCell(fmt.Sprintf("| %d", r.Remaining)))
+			table.SetCell(row+1, 4, tview.NewTableCell("| "+tview.Escape(rtl(strings.Join(unexpected, ", ")))))
+			table.SetCell(row+1, 5, tview.NewTableCell("| "+tview.Escape(rtl(strings.Join(r.Skipped, ", ")))).SetExpansion(1))
+		}
+		table.Select(1, 0).ScrollToBeginning()
+	}
+	fill()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'a' {
+			showAll = !showAll
+			fill()
+			return nil
+		}
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(shown) {
+			return event
+		}
+		if event.Key() == tcell.KeyEnter {
+			m.showReplay(shown[row-1])
+			return nil
+		}
+		return m.handleProcessKey(shown[row-1].Instance.ID, event)
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Enter replay details • a show conforming instances too • t timeline • e export path • Esc back")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	m.pages.AddPage("conformance", layout, true, true)
+	m.pages.SwitchToPage("conformance")
+	m.app.SetFocus(table)
+}
+
+// -----------------------------------------------------------------------
+// showReplay explains how an instance deviated from the model
+func (m *BPMNManager) showReplay(r mining.Replay) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Instance:[white] %s\n", r.Instance.ID)
+	fmt.Fprintf(&b, "[yellow]Definition:[white] %s\n", r.Instance.ProcessDefinitionId)
+	fmt.Fprintf(&b, "[yellow]Fitness:[white] %.2f  (produced %d, consumed %d, missing %d, remaining %d)\n\n",
+		r.Fitness, r.Produced, r.Consumed, r.Missing, r.Remaining)
+
+	list := func(title, color string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "[%s]%s:[white]\n", color, title)
+		for _, name := range names {
+			fmt.Fprintf(&b, "  • %s\n", tview.Escape(rtl(name)))
+		}
+		b.WriteString("\n")
+	}
+	list("Ran without being enabled by the model", "red", r.Unexpected)
+	list("Not part of the model", "red", r.Unknown)
+	list("Enabled but never reached", "orange", r.Skipped)
+
+	b.WriteString("[yellow]Executed activities:[white]\n")
+	unexpected := make(map[string]bool)
+	for _, name := range append(append([]string(nil), r.Unexpected...), r.Unknown...) {
+		unexpected[name] = true
+	}
+	for i, activity := range mining.Trace(r.Instance) {
+		label := activity.Name
+		if label == "" {
+			label = activity.ID
+		}
+		marker := ""
+		if unexpected[label] || unexpected[activity.ID] {
+			marker = " [red]← deviation[white]"
+		}
+		fmt.Fprintf(&b, "  %2d. %s [gray](%s)[white]%s\n", i+1, tview.Escape(rtl(label)), activity.Type, marker)
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(b.String())
+	view.SetBorder(true).SetTitle(" Replay • Enter back ").SetBorderColor(tcell.Color102)
+	view.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter || key == tcell.KeyEscape {
+			m.pages.SwitchToPage("conformance")
+		}
+	})
+	m.pages.AddPage("replay", view, true, true)
+	m.pages.SwitchToPage("replay")
+}
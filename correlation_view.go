@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showCorrelation lists the message and signal events an instance is
+// currently waiting on and lets the operator send one of them
+func (m *BPMNManager) showCorrelation(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	go func() {
+		diagram, err := loadInstanceDiagram(m.apiClient, instanceID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load process model: " + err.Error())
+				return
+			}
+			triggers := diagram.model.WaitingTriggers(activeActivityIDs(diagram.details))
+			m.createCorrelationList(diagram, triggers)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createCorrelationList(diagram *processDiagram, triggers []bpmn.Trigger) {
+	list := tview.NewList()
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	for _, trigger := range triggers {
+		trigger := trigger
+		icon := "✉️"
+		if trigger.EventType == "signal" {
+			icon = "📡"
+		}
+		title := fmt.Sprintf("%s %s %s", icon, trigger.EventType, trigger.Name)
+		secondary := fmt.Sprintf("%s %s on %s", trigger.Via, rtl(trigger.Node.Label()), trigger.ActiveID)
+		list.AddItem(title, secondary, 0, func() {
+			m.showCorrelationForm(diagram, trigger)
+		})
+	}
+	list.AddItem("✳️ Other message or signal...", "send an event that is not derived from the model", 0, func() {
+		m.showCorrelationForm(diagram, bpmn.Trigger{EventType: "message"})
+	})
+
+	list.SetDoneFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Waiting events • %s (%d) • Esc back ", diagram.details.ID, len(triggers))).
+		SetBorderColor(tcell.Color102)
+	m.pages.AddPage("correlation", list, true, true)
+	m.pages.SwitchToPage("correlation")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showCorrelationForm(diagram *processDiagram, trigger bpmn.Trigger) {
+	kinds := []string{"message", "signal"}
+	kindIndex := 0
+	if trigger.EventType == "signal" {
+		kindIndex = 1
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Type", kinds, kindIndex, nil).
+		AddInputField("Name", trigger.Name, 40, nil, nil).
+		AddInputField("Business key", "", 40, nil, nil).
+		AddCheckbox("Only this instance", true, nil).
+		AddTextArea("Correlation keys", "", 60, 3, 0, nil).
+		AddTextArea("Payload variables", "", 60, 6, 0, nil)
+
+	form.AddButton("Send", func() {
+		_, kind := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		name := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		businessKey := strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText())
+		onlyThis := form.GetFormItem(3).(*tview.Checkbox).IsChecked()
+		keys, err := variables.ParseAssignments(form.GetFormItem(4).(*tview.TextArea).GetText())
+		if err != nil {
+			m.showError("Correlation keys: " + err.Error())
+			return
+		}
+		payload, err := variables.ParseAssignments(form.GetFormItem(5).(*tview.TextArea).GetText())
+		if err != nil {
+			m.showError("Payload: " + err.Error())
+			return
+		}
+		if name == "" {
+			m.showError("A message or signal name is required")
+			return
+		}
+
+		instanceID := diagram.details.ID
+		go func() {
+			var err error
+			if kind == "signal" {
+				signal := models.SignalBroadcast{Name: name, Variables: variables.ToEngineMap(payload)}
+				if onlyThis {
+					err = m.signalInstance(instanceID, signal)
+				} else {
+					err = m.apiClient.BroadcastSignal(signal)
+				}
+			} else {
+				message := models.MessageCorrelation{
+					MessageName:      name,
+					BusinessKey:      businessKey,
+					CorrelationKeys:  variables.ToEngineMap(keys),
+					ProcessVariables: variables.ToEngineMap(payload),
+				}
+				if onlyThis {
+					message.ProcessInstanceID = instanceID
+				}
+				err = m.apiClient.CorrelateMessage(message)
+			}
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError(err.Error())
+					return
+				}
+				m.showMessage(fmt.Sprintf("%s %q sent", kind, name))
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("correlation")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("correlation")
+	})
+	form.SetBorder(true).
+		SetTitle(" Send Event • one \"name = value\" per line, optional name:Type ").
+		SetBorderColor(tcell.Color102)
+	m.pages.AddPage("correlation_send", form, true, true)
+	m.pages.SwitchToPage("correlation_send")
+}
+
+// -----------------------------------------------------------------------
+// signalInstance delivers a signal only to the executions of an instance
+// that wait for it; signals are addressed to executions, not instances
+func (m *BPMNManager) signalInstance(instanceID string, signal models.SignalBroadcast) error {
+	executions, err := m.apiClient.GetSignalExecutions(instanceID, signal.Name)
+	if err != nil {
+		return err
+	}
+	if len(executions) == 0 {
+		return fmt.Errorf("instance %s has no execution waiting for signal %q", instanceID, signal.Name)
+	}
+	for _, execution := range executions {
+		signal.ExecutionID = execution.ID
+		if err := m.apiClient.BroadcastSignal(signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
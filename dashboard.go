@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"bpmn-manager/api"
+	"bpmn-manager/charts"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+	"bpmn-manager/sla"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// dashboardWindows are the number of days the dashboard charts can cover
+var dashboardWindows = []int{7, 30, 90}
+
+const (
+	sparklineWidth = 45 // longer series are averaged down to this
+	barWidth       = 20
+	barLabelWidth  = 18
+	barRows        = 8 // bars per chart, the rest is summed up as "other"
+)
+
+// dashboardPanels are the boxes of the dashboard next to the navigation
+type dashboardPanels struct {
+	kpis       *tview.TextView
+	trends     *tview.TextView
+	processes  *tview.TextView
+	taskStates *tview.TextView
+	assignees  *tview.TextView
+	layout     *tview.Flex
+}
+
+// dashboardData is everything the dashboard is drawn from, fetched in
+// one go so changing the window does not hit the API again
+type dashboardData struct {
+	tasks          []models.UserTask
+	completedTasks []models.UserTask
+	running        []models.RunningProcess
+	completed      []models.ProcessDetails
+	incidents      []models.Incident
+	processesErr   error    // why running or completed instances are missing
+	failed         []string // what could not be loaded
+	loadedAt       time.Time
+}
+
+// -----------------------------------------------------------------------
+// newDashboardPanels creates the dashboard boxes around the attention
+// panel
+func newDashboardPanels(attention *tview.TextView) *dashboardPanels {
+	panel := func(title string) *tview.TextView {
+		view := tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+		view.SetBorder(true).SetTitle(title).SetBorderColor(tcell.Color102)
+		return view
+	}
+	d := &dashboardPanels{
+		kpis:       panel(" Dashboard "),
+		trends:     panel(" Trends "),
+		processes:  panel(" Top Processes "),
+		taskStates: panel(" Tasks by State "),
+		assignees:  panel(" Open Tasks by Assignee "),
+	}
+	d.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.kpis, 9, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(d.trends, 0, 3, false).
+			AddItem(d.processes, 0, 2, false), 0, 2, false).
+		AddItem(tview.NewFlex().
+			AddItem(d.taskStates, 0, 1, false).
+			AddItem(d.assignees, 0, 1, false), 0, 2, false).
+		AddItem(attention, 0, 2, false)
+	return d
+}
+
+// -----------------------------------------------------------------------
+// loadDashboardData fetches the dashboard's data; sources that fail are
+// listed instead of failing the whole dashboard
+func loadDashboardData(client *api.APIClient) *dashboardData {
+	data := &dashboardData{loadedAt: time.Now()}
+	note := func(what string, err error) {
+		if err != nil {
+			data.failed = append(data.failed, what+": "+err.Error())
+		}
+	}
+	var err error
+	data.tasks, err = client.GetUserTasks()
+	note("tasks", err)
+	data.completedTasks, err = client.GetCompletedTasks()
+	note("completed tasks", err)
+	data.running, err = client.GetRunningProcesses()
+	note("running processes", err)
+	if err != nil {
+		data.processesErr = err
+	}
+	data.completed, err = client.GetCompletedProcesses()
+	note("completed processes", err)
+	if err != nil {
+		data.processesErr = err
+	}
+	data.incidents, err = client.GetIncidents()
+	note("incidents", err)
+	return data
+}
+
+// -----------------------------------------------------------------------
+// updateDashboardPanel shows the dashboard and reloads its data in the
+// background
+func (m *BPMNManager) updateDashboardPanel() {
+	m.mainContent.Clear()
+	m.mainContent.AddItem(m.nav, 35, 1, true)
+	m.mainContent.AddItem(m.dashboard.layout, 0, 3, false)
+	m.app.SetFocus(m.nav)
+
+	m.dashboard.kpis.SetText("\n  [gray]🔄 Loading dashboard data...")
+	m.drawAttentionPanel(m.freshAnomalyScan())
+	go func() {
+		data := loadDashboardData(m.apiClient)
+		m.app.QueueUpdateDraw(func() {
+			m.dashboardData = data
+			m.drawDashboard()
+			m.refreshAttentionPanel(data)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// cycleDashboardWindow switches the charts to the next time window
+func (m *BPMNManager) cycleDashboardWindow() {
+	m.setDashboardWindow((m.dashboardWindow + 1) % len(dashboardWindows))
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) setDashboardWindow(index int) {
+	m.dashboardWindow = index
+	if m.dashboardData != nil {
+		m.drawDashboard()
+	}
+}
+
+// -----------------------------------------------------------------------
+// drawDashboard renders the loaded data for the selected window
+func (m *BPMNManager) drawDashboard() {
+	data := m.dashboardData
+	days := dashboardWindows[m.dashboardWindow]
+	now := time.Now()
+	start := charts.Day(now).AddDate(0, 0, 1-days)
+	previous := start.AddDate(0, 0, -days)
+	clock := m.clock()
+
+	// instances started and completed, with cycle times by completion day
+	var started, ended, endedBefore []time.Time
+	var cycleTimes, cycleTimesBefore []float64
+	volume := make(map[string]int)
+	for _, process := range data.running {
+		started = append(started, process.StartTime)
+		if !process.StartTime.Before(start) {
+			volume[processLabel(process.ProcessDefinitionKey, process.ProcessName)]++
+		}
+	}
+	for _, process := range data.completed {
+		from, to := instanceSpan(process)
+		started = append(started, from)
+		if !from.Before(start) {
+			volume[processLabel(process.ProcessDefinitionKey, process.Name)]++
+		}
+		if to.IsZero() {
+			continue
+		}
+		cycle := mining.Duration(process, clock).Seconds()
+		switch {
+		case !to.Before(start):
+			ended = append(ended, to)
+			cycleTimes = append(cycleTimes, cycle)
+		case !to.Before(previous):
+			endedBefore = append(endedBefore, to)
+			cycleTimesBefore = append(cycleTimesBefore, cycle)
+		}
+	}
+	startedPerDay := charts.CountByDay(started, start, days)
+	endedPerDay := charts.CountByDay(ended, start, days)
+	cyclePerDay := charts.MeanByDay(ended, cycleTimes, start, days)
+	meanCycle, meanCycleBefore := charts.Mean(cycleTimes), charts.Mean(cycleTimesBefore)
+
+	// open tasks by SLA state and assignee
+	evaluations := m.sla.EvaluateAll(data.tasks, now)
+	unassigned, overdue, atRisk := 0, 0, 0
+	byAssignee := make(map[string]int)
+	overdueBy := make(map[string]int)
+	for _, e := range evaluations {
+		if e.Task.Assignee == "" {
+			unassigned++
+		} else {
+			byAssignee[e.Task.Assignee]++
+		}
+		switch e.Status {
+		case sla.StatusOverdue:
+			overdue++
+			overdueBy[e.Task.Assignee]++
+		case sla.StatusWarning:
+			atRisk++
+		}
+	}
+	// completed tasks carry no end time, only when they were created
+	createdDone := 0
+	for _, task := range data.completedTasks {
+		if !task.CreatedAt.Before(start) {
+			createdDone++
+		}
+	}
+
+	window := fmt.Sprintf("last %d days", days)
+	var kpis strings.Builder
+	fmt.Fprintf(&kpis, " [yellow]Running processes[white] %-8d [yellow]Open tasks[white] %d [gray](%d unassigned)\n",
+		len(data.running), len(data.tasks), unassigned)
+	fmt.Fprintf(&kpis, " [yellow]Open incidents   [white] %s [yellow]Overdue   [white] %s [gray](%d at risk)\n",
+		countText(len(data.incidents), 8), countText(overdue, 0), atRisk)
+	fmt.Fprintf(&kpis, " [yellow]Completed        [white] %-8d [yellow]Throughput[white] %.1f/day %s\n",
+		len(ended), float64(len(ended))/float64(days), changeText(float64(len(ended)), float64(len(endedBefore)), true))
+	fmt.Fprintf(&kpis, " [yellow]Mean cycle time  [white] %-8s %s\n",
+		formatSeconds(meanCycle), changeText(meanCycle, meanCycleBefore, false))
+	fmt.Fprintf(&kpis, " [gray]Window: %s (F7), changes vs the %d days before • durations in %s (F6) • loaded %s (F5)\n",
+		window, days, m.timeMode(), data.loadedAt.Format("15:04"))
+	if m.slaErr != nil {
+		fmt.Fprintf(&kpis, " [gray]SLA targets ignored: %s\n", tview.Escape(m.slaErr.Error()))
+	}
+	if m.calendarErr != nil {
+		fmt.Fprintf(&kpis, " [gray]Default calendar used: %s\n", tview.Escape(m.calendarErr.Error()))
+	}
+	if len(data.failed) > 0 {
+		fmt.Fprintf(&kpis, " [red]Not loaded: %s\n", tview.Escape(strings.Join(data.failed, "; ")))
+	}
+	m.dashboard.kpis.SetText(kpis.String())
+
+	var trends strings.Builder
+	fmt.Fprintf(&trends, " [yellow]Started   [green]%s[white] %d, peak %s/day\n",
+		charts.Sparkline(startedPerDay, sparklineWidth), sum(startedPerDay), peak(startedPerDay))
+	fmt.Fprintf(&trends, " [yellow]Completed [green]%s[white] %d, peak %s/day\n",
+		charts.Sparkline(endedPerDay, sparklineWidth), len(ended), peak(endedPerDay))
+	fmt.Fprintf(&trends, " [yellow]Cycle time [orange]%s[white] mean %s\n",
+		charts.Sparkline(cyclePerDay, sparklineWidth), formatSeconds(meanCycle))
+	fmt.Fprintf(&trends, " [gray]%s … today, one column per %s", start.Format("Jan 2"), columnUnit(days))
+	m.dashboard.trends.SetText(trends.String()).
+		SetTitle(fmt.Sprintf(" Trends • %s ", window))
+
+	m.dashboard.processes.SetText(barChart(topCounts(volume), nil, "green")).
+		SetTitle(fmt.Sprintf(" Top Processes • started, %s ", window))
+	m.dashboard.taskStates.SetText(barChart([]barValue{
+		{"Unassigned", unassigned},
+		{"Assigned", len(data.tasks) - unassigned},
+		{"At risk", atRisk},
+		{"Overdue", overdue},
+		{"Created & done", createdDone},
+	}, nil, "yellow") + fmt.Sprintf("\n [gray]created & done: created in the %s, completed since", window))
+	m.dashboard.assignees.SetText(barChart(topCounts(byAssignee), overdueBy, "green"))
+}
+
+// barValue is one labelled bar of a chart
+type barValue struct {
+	label string
+	value int
+}
+
+// -----------------------------------------------------------------------
+// topCounts orders counts by size and keeps the largest barRows, adding
+// up the rest as "other"
+func topCounts(counts map[string]int) []barValue {
+	values := make([]barValue, 0, len(counts))
+	for label, n := range counts {
+		values = append(values, barValue{label, n})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].value != values[j].value {
+			return values[i].value > values[j].value
+		}
+		return values[i].label < values[j].label
+	})
+	if len(values) > barRows {
+		other := barValue{label: "other"}
+		for _, v := range values[barRows-1:] {
+			other.value += v.value
+		}
+		values = append(values[:barRows-1], other)
+	}
+	return values
+}
+
+// -----------------------------------------------------------------------
+// barChart draws one labelled bar per value; overdue, when given, adds a
+// red count of overdue items behind each bar
+func barChart(values []barValue, overdue map[string]int, color string) string {
+	if len(values) == 0 {
+		return " [gray]No data"
+	}
+	top := 0
+	for _, v := range values {
+		if v.value > top {
+			top = v.value
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		label := tview.Escape(rtl(truncate(v.label, barLabelWidth)))
+		pad := barLabelWidth - tview.TaggedStringWidth(label)
+		if pad < 0 {
+			pad = 0
+		}
+		fmt.Fprintf(&b, " %s%s [%s]%s[white] %d", label, strings.Repeat(" ", pad), color,
+			charts.Bar(float64(v.value), float64(top), barWidth), v.value)
+		if n := overdue[v.label]; n > 0 {
+			fmt.Fprintf(&b, " [red](%d overdue)[white]", n)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+// instanceSpan returns when a completed instance started and ended, from
+// the engine's times or else its activities
+func instanceSpan(process models.ProcessDetails) (time.Time, time.Time) {
+	start, end := parseEngineTime(process.StartTime), parseEngineTime(process.EndTime)
+	for _, activity := range process.Activities {
+		if process.StartTime == "" && !activity.StartTime.IsZero() && (start.IsZero() || activity.StartTime.Before(start)) {
+			start = activity.StartTime
+		}
+		if process.EndTime == "" && activity.EndTime.After(end) {
+			end = activity.EndTime
+		}
+	}
+	return start, end
+}
+
+// -----------------------------------------------------------------------
+func processLabel(key, name string) string {
+	if key != "" {
+		return key
+	}
+	if name != "" {
+		return name
+	}
+	return "(unknown)"
+}
+
+// -----------------------------------------------------------------------
+// countText pads a count to width and shows it red unless it is zero
+func countText(n, width int) string {
+	text := fmt.Sprintf("%-*d", width, n)
+	if n > 0 {
+		return "[red]" + text + "[white]"
+	}
+	return text
+}
+
+// -----------------------------------------------------------------------
+// changeText compares a value with the previous window as a coloured
+// percentage; goodWhenUp tells which direction is an improvement
+func changeText(current, previous float64, goodWhenUp bool) string {
+	if math.IsNaN(current) || math.IsNaN(previous) || previous == 0 {
+		return ""
+	}
+	change := (current - previous) / previous * 100
+	if math.Round(change) == 0 {
+		return "[gray]±0%[white]"
+	}
+	arrow, color := "▲", "green"
+	if change < 0 {
+		arrow = "▼"
+	}
+	if (change < 0) == goodWhenUp {
+		color = "red"
+	}
+	return fmt.Sprintf("[%s]%s%.0f%%[white]", color, arrow, math.Abs(change))
+}
+
+// -----------------------------------------------------------------------
+func formatSeconds(seconds float64) string {
+	if math.IsNaN(seconds) {
+		return "—"
+	}
+	return formatAge(time.Duration(seconds * float64(time.Second)))
+}
+
+// -----------------------------------------------------------------------
+// columnUnit names what one sparkline column covers
+func columnUnit(days int) string {
+	if size := (days + sparklineWidth - 1) / sparklineWidth; size > 1 {
+		return fmt.Sprintf("%d days", size)
+	}
+	return "day"
+}
+
+// -----------------------------------------------------------------------
+func sum(values []float64) int {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return int(total)
+}
+
+// -----------------------------------------------------------------------
+func peak(values []float64) string {
+	top := 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+	}
+	return fmt.Sprintf("%.0f", top)
+}
@@ -0,0 +1,637 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/query"
+	"bpmn-manager/search"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// dataColumn describes how one column of a dataTable is drawn
+type dataColumn struct {
+	Key    string // stable name used by saved views
+	Header string
+	Prefix string // separator drawn before the value
+	Suffix string // separator drawn after the value
+	Align  int
+	Color  tcell.Color // zero keeps the default color
+	Hidden bool        // hidden until chosen in the column chooser
+	Number bool        // sort numerically
+}
+
+// dataTable is a table of plain text rows with a "/" fuzzy search bar,
+// an optional ":" query filter, multi-key sorting and a column layout.
+// Callers keep their own data and use selectedRow to map the selection
+// back to it, since drawn cells contain highlight tags.
+type dataTable struct {
+	app     *tview.Application
+	title   string
+	columns []dataColumn
+	rows    [][]string
+	colors  []tcell.Color // parallel to rows, zero keeps the column colors
+	results []search.Result
+
+	schema   *query.Schema
+	records  []query.Record
+	query    *query.Query
+	queryErr string
+
+	sortKeys []models.SortKey
+	visible  []int // indexes into columns, nil shows the default ones
+	widths   map[string]int
+	active   int // position in the shown columns that sort and resize act on
+	onSave   func()
+	onChoose func()
+	onLayout func(models.TableLayout)
+
+	table  *tview.Table
+	search *tview.InputField
+	filter *tview.InputField
+	bar    *tview.Flex
+	layout *tview.Flex
+	keys   func(event *tcell.EventKey) *tcell.EventKey
+}
+
+// -----------------------------------------------------------------------
+func newDataTable(app *tview.Application, title string, columns []dataColumn) *dataTable {
+	d := &dataTable{
+		app:     app,
+		title:   title,
+		columns: columns,
+		table: tview.NewTable().
+			SetBorders(false).
+			SetFixed(1, 0).
+			SetSelectable(true, false),
+		search: tview.NewInputField().SetLabel("/ ").SetFieldWidth(0),
+	}
+	d.table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	d.search.SetChangedFunc(func(text string) {
+		d.apply()
+	})
+	d.search.SetDoneFunc(func(key tcell.Key) {
+		d.app.SetFocus(d.table)
+	})
+
+	d.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '/':
+			d.app.SetFocus(d.search)
+			return nil
+		case ':':
+			if d.filter != nil {
+				d.app.SetFocus(d.filter)
+				return nil
+			}
+		case 'n':
+			d.jump(1)
+			return nil
+		case 'N':
+			d.jump(-1)
+			return nil
+		case 'S':
+			if d.onSave != nil {
+				d.onSave()
+				return nil
+			}
+		case 'C':
+			if d.onChoose != nil {
+				d.onChoose()
+				return nil
+			}
+		case '[', ']':
+			shown := len(d.shownColumns())
+			if event.Rune() == '[' {
+				d.active = (d.active - 1 + shown) % shown
+			} else {
+				d.active = (d.active + 1) % shown
+			}
+			d.renderHeader()
+			return nil
+		case 's', '+':
+			d.toggleSort(d.shownColumns()[d.active], event.Rune() == '+')
+			return nil
+		case '<', '>':
+			d.resize(d.shownColumns()[d.active], event.Rune() == '>')
+			return nil
+		}
+		if d.keys != nil {
+			return d.keys(event)
+		}
+		return event
+	})
+
+	d.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftClick {
+			return action, event
+		}
+		row, col := d.table.CellAt(event.Position())
+		if row != 0 || col < 0 || col >= len(d.shownColumns()) {
+			return action, event
+		}
+		d.active = col
+		d.toggleSort(d.shownColumns()[col], event.Modifiers()&tcell.ModShift != 0)
+		d.app.SetFocus(d.table)
+		return tview.MouseConsumed, nil
+	})
+
+	d.bar = tview.NewFlex().AddItem(d.search, 0, 1, false)
+	d.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.bar, 1, 0, false).
+		AddItem(d.table, 0, 1, true)
+	d.layout.SetBorder(true).SetBorderColor(tcell.Color102)
+	return d
+}
+
+// -----------------------------------------------------------------------
+// setInputCapture installs the screen's key handler, which runs after
+// the search keys
+func (d *dataTable) setInputCapture(keys func(event *tcell.EventKey) *tcell.EventKey) {
+	d.keys = keys
+}
+
+// -----------------------------------------------------------------------
+// enableQuery adds the ":" filter bar. records must be parallel to the
+// rows passed to setRows.
+func (d *dataTable) enableQuery(schema *query.Schema, records []query.Record) {
+	d.schema = schema
+	d.records = records
+	if d.filter != nil {
+		return
+	}
+	d.filter = tview.NewInputField().SetLabel(": ").SetFieldWidth(0)
+	d.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			d.setQuery(d.filter.GetText())
+		}
+		d.app.SetFocus(d.table)
+	})
+	d.bar.AddItem(d.filter, 0, 2, false)
+}
+
+// -----------------------------------------------------------------------
+// setQuery compiles and applies a filter expression. Syntax errors are
+// shown in the title and leave the previous filter in place.
+func (d *dataTable) setQuery(text string) {
+	if d.schema == nil {
+		return
+	}
+	if d.filter.GetText() != text {
+		d.filter.SetText(text)
+	}
+	q, err := query.Parse(text, d.schema)
+	if err != nil {
+		d.queryErr = err.Error()
+		d.filter.SetLabel("[red]: ")
+		d.updateTitle()
+		return
+	}
+	d.query, d.queryErr = q, ""
+	d.filter.SetLabel(": ")
+	d.apply()
+}
+
+// -----------------------------------------------------------------------
+// setRows shows new records, starting at the first row
+func (d *dataTable) setRows(rows [][]string) {
+	d.rows = rows
+	d.table.Select(0, 0)
+	d.apply()
+}
+
+// -----------------------------------------------------------------------
+// updateRows replaces the rows with new values for the same records, e.g.
+// forecasts that finished loading, keeping the selected record and the
+// scroll position
+func (d *dataTable) updateRows(rows [][]string) {
+	d.rows = rows
+	d.apply()
+}
+
+// -----------------------------------------------------------------------
+// setRowColors colors whole rows, e.g. to flag overdue tasks. colors is
+// parallel to the rows passed to setRows.
+func (d *dataTable) setRowColors(colors []tcell.Color) {
+	d.colors = colors
+	d.render()
+}
+
+// -----------------------------------------------------------------------
+// selectedRow returns the index of the selected row in the rows passed
+// to setRows, or -1 when nothing is selected
+func (d *dataTable) selectedRow() int {
+	row, _ := d.table.GetSelection()
+	if row < 1 || row > len(d.results) {
+		return -1
+	}
+	return d.results[row-1].Row
+}
+
+// -----------------------------------------------------------------------
+// apply narrows the table to the rows matching the query filter and the
+// search text
+func (d *dataTable) apply() {
+	rows, index := d.rows, []int(nil)
+	if !d.query.Empty() {
+		rows = nil
+		for i, record := range d.records {
+			if i < len(d.rows) && d.query.Match(record) {
+				rows = append(rows, d.rows[i])
+				index = append(index, i)
+			}
+		}
+	}
+	d.results = search.Rows(d.search.GetText(), rows)
+	if index != nil {
+		for i := range d.results {
+			d.results[i].Row = index[d.results[i].Row]
+		}
+	}
+	d.sortResults()
+	d.render()
+}
+
+// -----------------------------------------------------------------------
+// columnIndex returns the index of the column with key, or -1
+func (d *dataTable) columnIndex(key string) int {
+	for i, column := range d.columns {
+		if column.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// -----------------------------------------------------------------------
+// sortResults orders the shown rows by the sort keys, first key first
+func (d *dataTable) sortResults() {
+	if len(d.sortKeys) == 0 {
+		return
+	}
+	sort.SliceStable(d.results, func(i, j int) bool {
+		a, b := d.rows[d.results[i].Row], d.rows[d.results[j].Row]
+		for _, key := range d.sortKeys {
+			column := d.columnIndex(key.Column)
+			if column < 0 || column >= len(a) || column >= len(b) {
+				continue
+			}
+			order := compareCells(a[column], b[column], d.columns[column].Number)
+			if order == 0 {
+				continue
+			}
+			if key.Descending {
+				return order > 0
+			}
+			return order < 0
+		}
+		return false
+	})
+}
+
+// -----------------------------------------------------------------------
+// compareCells orders two cell values, numerically for number columns
+// with empty values last
+func compareCells(a, b string, number bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	if number {
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(search.Normalize(a), search.Normalize(b))
+}
+
+// -----------------------------------------------------------------------
+// toggleSort sorts by a column: ascending, then descending, then off.
+// With add the column becomes an extra sort key instead of replacing
+// the others.
+func (d *dataTable) toggleSort(column int, add bool) {
+	key := d.columns[column].Key
+	position := -1
+	for i, sortKey := range d.sortKeys {
+		if sortKey.Column == key {
+			position = i
+		}
+	}
+
+	switch {
+	case position < 0 && add:
+		d.sortKeys = append(d.sortKeys, models.SortKey{Column: key})
+	case position < 0:
+		d.sortKeys = []models.SortKey{{Column: key}}
+	case !d.sortKeys[position].Descending:
+		d.sortKeys[position].Descending = true
+		if !add {
+			d.sortKeys = []models.SortKey{d.sortKeys[position]}
+		}
+	default:
+		d.sortKeys = append(d.sortKeys[:position], d.sortKeys[position+1:]...)
+		if !add {
+			d.sortKeys = nil
+		}
+	}
+	d.apply()
+	d.layoutChanged()
+}
+
+// -----------------------------------------------------------------------
+// resize narrows or widens a column by two cells. Columns start
+// unlimited, so the first narrowing caps them at 30.
+func (d *dataTable) resize(column int, wider bool) {
+	key := d.columns[column].Key
+	if d.widths == nil {
+		d.widths = map[string]int{}
+	}
+	width := d.widths[key]
+	switch {
+	case width == 0 && wider:
+		return
+	case width == 0:
+		width = 30
+	case wider:
+		width += 2
+	default:
+		width -= 2
+	}
+	if width < 4 {
+		width = 4
+	}
+	if wider && width > 60 {
+		width = 0
+	}
+	d.widths[key] = width
+	if width == 0 {
+		delete(d.widths, key)
+	}
+	d.render()
+	d.layoutChanged()
+}
+
+// -----------------------------------------------------------------------
+func (d *dataTable) layoutChanged() {
+	if d.onLayout != nil {
+		d.onLayout(d.currentLayout())
+	}
+}
+
+// -----------------------------------------------------------------------
+// shownColumns returns the indexes of the columns to draw
+func (d *dataTable) shownColumns() []int {
+	if d.visible != nil {
+		return d.visible
+	}
+	var shown []int
+	for i, column := range d.columns {
+		if !column.Hidden {
+			shown = append(shown, i)
+		}
+	}
+	return shown
+}
+
+// -----------------------------------------------------------------------
+// setShown changes which columns are drawn and in which order
+func (d *dataTable) setShown(columns []int) {
+	if len(columns) == 0 {
+		return
+	}
+	d.visible = columns
+	if d.active >= len(columns) {
+		d.active = 0
+	}
+	d.render()
+	d.layoutChanged()
+}
+
+// -----------------------------------------------------------------------
+func (d *dataTable) currentLayout() models.TableLayout {
+	layout := models.TableLayout{Sort: append([]models.SortKey(nil), d.sortKeys...)}
+	if d.visible != nil {
+		for _, i := range d.visible {
+			layout.Columns = append(layout.Columns, d.columns[i].Key)
+		}
+	}
+	if len(d.widths) > 0 {
+		layout.Widths = map[string]int{}
+		for key, width := range d.widths {
+			layout.Widths[key] = width
+		}
+	}
+	return layout
+}
+
+// -----------------------------------------------------------------------
+// applyLayout restores columns, widths and sorting. Unknown columns are
+// skipped so layouts survive table changes.
+func (d *dataTable) applyLayout(layout models.TableLayout) {
+	d.sortKeys = nil
+	for _, key := range layout.Sort {
+		if d.columnIndex(key.Column) >= 0 {
+			d.sortKeys = append(d.sortKeys, key)
+		}
+	}
+	d.visible = nil
+	for _, key := range layout.Columns {
+		if i := d.columnIndex(key); i >= 0 {
+			d.visible = append(d.visible, i)
+		}
+	}
+	d.widths = map[string]int{}
+	for key, width := range layout.Widths {
+		d.widths[key] = width
+	}
+	d.active = 0
+	d.apply()
+}
+
+// -----------------------------------------------------------------------
+// view captures the table configuration for saving
+func (d *dataTable) view() models.SavedView {
+	layout := d.currentLayout()
+	view := models.SavedView{
+		Search:  d.search.GetText(),
+		Sort:    layout.Sort,
+		Columns: layout.Columns,
+		Widths:  layout.Widths,
+	}
+	if !d.query.Empty() {
+		view.Filter = d.query.Text
+	}
+	return view
+}
+
+// -----------------------------------------------------------------------
+// applyView restores a saved configuration
+func (d *dataTable) applyView(view *models.SavedView) {
+	d.search.SetText(view.Search)
+	if d.schema != nil {
+		d.setQuery(view.Filter)
+	}
+	d.applyLayout(view.Layout())
+}
+
+// -----------------------------------------------------------------------
+// jump moves the selection to the next or previous match, wrapping
+// around at the ends
+func (d *dataTable) jump(step int) {
+	if len(d.results) == 0 {
+		return
+	}
+	row, _ := d.table.GetSelection()
+	row = (row-1+step+len(d.results))%len(d.results) + 1
+	d.table.Select(row, 0)
+	d.updateTitle()
+}
+
+// -----------------------------------------------------------------------
+func (d *dataTable) updateTitle() {
+	title := fmt.Sprintf(" %s (%d) ", d.title, len(d.rows))
+	if !d.query.Empty() {
+		title = fmt.Sprintf(" %s (%d of %d) ", d.title, len(d.results), len(d.rows))
+	}
+	if strings.TrimSpace(d.search.GetText()) != "" {
+		row, _ := d.table.GetSelection()
+		if len(d.results) == 0 {
+			row = 0
+		}
+		title = fmt.Sprintf(" %s • match %d/%d of %d ", d.title, row, len(d.results), len(d.rows))
+	}
+	if d.queryErr != "" {
+		title += "• [red]" + tview.Escape(d.queryErr) + " "
+	}
+	d.layout.SetTitle(title)
+}
+
+// -----------------------------------------------------------------------
+// renderHeader draws the header row with sort arrows and the active
+// column underlined
+func (d *dataTable) renderHeader() {
+	for col, i := range d.shownColumns() {
+		header := d.columns[i].Header
+		for n, key := range d.sortKeys {
+			if key.Column == d.columns[i].Key {
+				arrow := "▲"
+				if key.Descending {
+					arrow = "▼"
+				}
+				if len(d.sortKeys) > 1 {
+					arrow += fmt.Sprint(n + 1)
+				}
+				header += " " + arrow
+			}
+		}
+		cell := tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAlign(d.columns[i].Align).
+			SetMaxWidth(d.widths[d.columns[i].Key]).
+			SetSelectable(false)
+		if col == d.active {
+			cell.SetAttributes(tcell.AttrUnderline)
+		}
+		d.table.SetCell(0, col, cell)
+	}
+}
+
+// -----------------------------------------------------------------------
+// render redraws the cells. The selected record stays selected, and in
+// place on screen, while it is among the results.
+func (d *dataTable) render() {
+	selected := d.selectedRow()
+	_, column := d.table.GetSelection()
+	offsetRow, offsetColumn := d.table.GetOffset()
+	d.table.Clear()
+	shown := d.shownColumns()
+	d.renderHeader()
+
+	for row, result := range d.results {
+		for col, i := range shown {
+			column := d.columns[i]
+			value := ""
+			if i < len(d.rows[result.Row]) {
+				value = d.rows[result.Row][i]
+			}
+			cell := tview.NewTableCell(tview.Escape(column.Prefix) +
+				highlight(value, result.Positions[i]) +
+				tview.Escape(column.Suffix)).
+				SetAlign(column.Align).
+				SetMaxWidth(d.widths[column.Key]).
+				SetReference(result.Row)
+			if column.Color != 0 {
+				cell.SetTextColor(column.Color)
+			}
+			if result.Row < len(d.colors) && d.colors[result.Row] != 0 {
+				cell.SetTextColor(d.colors[result.Row])
+			}
+			d.table.SetCell(row+1, col, cell)
+		}
+	}
+
+	d.updateTitle()
+	if selected >= 0 {
+		for i, result := range d.results {
+			if result.Row == selected {
+				d.table.SetOffset(offsetRow, offsetColumn)
+				d.table.Select(i+1, max(0, min(column, len(shown)-1)))
+				return
+			}
+		}
+	}
+	d.table.Select(1, 0)
+	d.table.ScrollToBeginning()
+}
+
+// -----------------------------------------------------------------------
+// highlight renders value right-to-left when it is Persian and marks the
+// matched rune positions
+func highlight(value string, positions []int) string {
+	runes := []rune(value)
+	marked := make([]bool, len(runes))
+	for _, p := range positions {
+		if p >= 0 && p < len(runes) {
+			marked[p] = true
+		}
+	}
+	if containsPersian(value) {
+		runes = []rune(reverseString(value))
+		for i, j := 0, len(marked)-1; i < j; i, j = i+1, j-1 {
+			marked[i], marked[j] = marked[j], marked[i]
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+		segment := tview.Escape(string(runes[i:j]))
+		if marked[i] {
+			segment = "[black:yellow]" + segment + "[-:-]"
+		}
+		b.WriteString(segment)
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"bpmn-manager/api"
+	"bpmn-manager/bpmn"
+	"bpmn-manager/calendar"
+	"bpmn-manager/export"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// processDiagram is an instance together with the parsed model of its
+// process definition
+type processDiagram struct {
+	details *models.ProcessDetails
+	model   *bpmn.Model
+}
+
+// -----------------------------------------------------------------------
+func loadDefinitionModel(client *api.APIClient, definitionID string) (*bpmn.Model, error) {
+	data, err := client.GetProcessDefinitionXML(definitionID)
+	if err != nil {
+		return nil, err
+	}
+	return bpmn.Parse(data)
+}
+
+// -----------------------------------------------------------------------
+func loadInstanceDiagram(client *api.APIClient, instanceID string) (*processDiagram, error) {
+	details, err := client.GetProcessDetails(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if details.ProcessDefinitionId == "" {
+		return nil, fmt.Errorf("instance %s has no process definition id", instanceID)
+	}
+	model, err := loadDefinitionModel(client, details.ProcessDefinitionId)
+	if err != nil {
+		return nil, err
+	}
+	return &processDiagram{details: details, model: model}, nil
+}
+
+// -----------------------------------------------------------------------
+// activeActivityIDs returns the activities of an instance that have not
+// ended yet
+func activeActivityIDs(details *models.ProcessDetails) []string {
+	var active []string
+	for _, activity := range details.Activities {
+		if activity.EndTime.IsZero() {
+			active = append(active, activity.ID)
+		}
+	}
+	return active
+}
+
+// -----------------------------------------------------------------------
+// loadHistories returns the completed instances of a definition
+func loadHistories(client *api.APIClient, definitionID string) ([]models.ProcessDetails, error) {
+	completed, err := client.GetCompletedProcesses()
+	if err != nil {
+		return nil, err
+	}
+	var histories []models.ProcessDetails
+	for _, process := range completed {
+		if process.ProcessDefinitionId == definitionID {
+			histories = append(histories, process)
+		}
+	}
+	return histories, nil
+}
+
+// -----------------------------------------------------------------------
+// loadStatisticsOverlay aggregates completed instances of a definition
+func loadStatisticsOverlay(client *api.APIClient, definitionID string) (*export.Overlay, error) {
+	histories, err := loadHistories(client, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	return export.ActivityStatistics(histories), nil
+}
+
+// -----------------------------------------------------------------------
+// loadBottleneckOverlay heat-colours the activities of a definition by
+// the time its completed instances spent there
+func loadBottleneckOverlay(client *api.APIClient, definitionID string, clock calendar.Clock) (*export.Overlay, error) {
+	histories, err := loadHistories(client, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	return export.Bottlenecks(mining.Bottlenecks(histories, clock)), nil
+}
+
+// -----------------------------------------------------------------------
+// showExportForm lets the user export the diagram of an instance's
+// process definition, optionally overlaid with the instance path or with
+// per-activity statistics
+func (m *BPMNManager) showExportForm(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance to export")
+		return
+	}
+
+	formats := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		formats[i] = string(f)
+	}
+	overlays := []string{"Instance path", "Activity statistics", "Bottlenecks", "None"}
+
+	form := tview.NewForm().
+		AddTextView("Instance:", instanceID, 40, 1, true, false).
+		AddDropDown("Format", formats, 2, nil).
+		AddDropDown("Overlay", overlays, 0, nil).
+		AddInputField("Output file", instanceID+".svg", 50, nil, nil)
+
+	form.GetFormItem(1).(*tview.DropDown).SetSelectedFunc(func(text string, index int) {
+		output := form.GetFormItem(3).(*tview.InputField)
+		name := strings.TrimSuffix(output.GetText(), ".svg")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".mmd"), ".dot")
+		output.SetText(name + export.Format(text).Extension())
+	})
+
+	form.AddButton("Export", func() {
+		_, formatName := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		overlayIndex, _ := form.GetFormItem(2).(*tview.DropDown).GetCurrentOption()
+		path := form.GetFormItem(3).(*tview.InputField).GetText()
+
+		go func() {
+			err := m.exportDiagram(instanceID, export.Format(formatName), overlayIndex, path)
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Export failed: " + err.Error())
+					return
+				}
+				m.showMessage("Diagram exported to " + path)
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Export Diagram ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("export", form, true, true)
+	m.pages.SwitchToPage("export")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) exportDiagram(instanceID string, format export.Format, overlayIndex int, path string) error {
+	diagram, err := loadInstanceDiagram(m.apiClient, instanceID)
+	if err != nil {
+		return err
+	}
+
+	var overlay *export.Overlay
+	switch overlayIndex {
+	case 0:
+		overlay = export.InstancePath(diagram.model, diagram.details)
+	case 1:
+		overlay, err = loadStatisticsOverlay(m.apiClient, diagram.details.ProcessDefinitionId)
+		if err != nil {
+			return err
+		}
+	case 2:
+		overlay, err = loadBottleneckOverlay(m.apiClient, diagram.details.ProcessDefinitionId, m.clock())
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(file, diagram.model, format, overlay); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/url"
+	"os"
+
+	"bpmn-manager/api"
+	"bpmn-manager/eventlog"
+)
+
+// eventLogPageSize is how many instances are fetched per request while
+// streaming an event log
+const eventLogPageSize = 100
+
+// eventLogSource selects the instances written to an event log
+type eventLogSource struct {
+	completed bool
+	running   bool
+	key       string // process definition key, empty for all processes
+}
+
+// -----------------------------------------------------------------------
+// streamEventLog writes the selected instances page by page, so only one
+// page is held in memory, and reports the cases written after each one
+func streamEventLog(client *api.APIClient, source eventLogSource, w eventlog.Writer, progress func(cases int)) error {
+	params := url.Values{}
+	if source.key != "" {
+		params.Set("processDefinitionKey", source.key)
+	}
+
+	if source.completed {
+		for offset := 0; ; {
+			page, err := client.GetCompletedProcessesPage(params, offset, eventLogPageSize)
+			if err != nil {
+				return err
+			}
+			for _, process := range page.Items {
+				if source.key != "" && process.ProcessDefinitionKey != source.key {
+					continue
+				}
+				if err := w.WriteCase(process); err != nil {
+					return err
+				}
+				progress(w.Cases())
+			}
+			offset += len(page.Items)
+			if len(page.Items) == 0 || offset >= page.Total {
+				break
+			}
+		}
+	}
+
+	if source.running {
+		for offset := 0; ; {
+			page, err := client.GetRunningProcessesPage(params, offset, eventLogPageSize)
+			if err != nil {
+				return err
+			}
+			for _, running := range page.Items {
+				if source.key != "" && running.ProcessDefinitionKey != source.key {
+					continue
+				}
+				process, err := client.GetProcessDetails(running.ProcessID)
+				if err != nil {
+					return err
+				}
+				if err := w.WriteCase(*process); err != nil {
+					return err
+				}
+				progress(w.Cases())
+			}
+			offset += len(page.Items)
+			if len(page.Items) == 0 || offset >= page.Total {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// writeEventLog streams an event log to a file, removing it when the
+// export fails half way
+func writeEventLog(client *api.APIClient, source eventLogSource, format eventlog.Format, variableNames []string, path string, progress func(cases int)) (int, error) {
+	out, err := createOutput(path)
+	if err != nil {
+		return 0, err
+	}
+	fail := func(err error) (int, error) {
+		out.Close()
+		if path != "" && path != "-" {
+			os.Remove(path)
+		}
+		return 0, err
+	}
+
+	w, err := eventlog.New(format, out, variableNames)
+	if err != nil {
+		return fail(err)
+	}
+	if err := streamEventLog(client, source, w, progress); err != nil {
+		return fail(err)
+	}
+	if err := w.Close(); err != nil {
+		return fail(err)
+	}
+	if err := out.Close(); err != nil {
+		return fail(err)
+	}
+	return w.Cases(), nil
+}
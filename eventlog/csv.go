@@ -0,0 +1,78 @@
+package eventlog
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+)
+
+// csvHeader names the fixed columns of a CSV event log. The ocel:
+// columns let OCEL 1.0 tools import the file with the case as the
+// object type; the others are the usual flat event log columns.
+var csvHeader = []string{
+	"ocel:eid", "ocel:activity", "ocel:timestamp", "ocel:type:case",
+	"case_id", "activity", "timestamp", "resource", "lifecycle",
+	"activity_id", "activity_type", "process_key",
+}
+
+// csvWriter writes one row per event
+type csvWriter struct {
+	out       *csv.Writer
+	variables []string // case variables added as columns
+	events    int
+	cases     int
+}
+
+// -----------------------------------------------------------------------
+// NewCSV starts a CSV event log on w. The named process variables are
+// added as columns, since a streamed log cannot learn all names upfront.
+func NewCSV(w io.Writer, variableNames []string) (Writer, error) {
+	c := &csvWriter{out: csv.NewWriter(w), variables: variableNames}
+	header := append(append([]string(nil), csvHeader...), variableNames...)
+	c.out.Write(header)
+	c.out.Flush()
+	return c, c.out.Error()
+}
+
+// -----------------------------------------------------------------------
+func (c *csvWriter) WriteCase(process models.ProcessDetails) error {
+	current := make(map[string]interface{}, len(process.CurrentVariables))
+	for _, variable := range variables.FromMap(process.CurrentVariables) {
+		current[variable.Name] = variable.Value
+	}
+	values := make([]string, len(c.variables))
+	for i, name := range c.variables {
+		if value, ok := current[name]; ok {
+			values[i] = valueText(value)
+		}
+	}
+
+	for _, event := range Events(process) {
+		c.events++
+		timestamp := event.Timestamp.Format(time.RFC3339Nano)
+		row := []string{
+			strconv.Itoa(c.events), event.Activity, timestamp, "['" + event.CaseID + "']",
+			event.CaseID, event.Activity, timestamp, event.Resource, event.Lifecycle,
+			event.ActivityID, event.ActivityType, event.ProcessKey,
+		}
+		c.out.Write(append(row, values...))
+	}
+	c.cases++
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// -----------------------------------------------------------------------
+func (c *csvWriter) Close() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// -----------------------------------------------------------------------
+func (c *csvWriter) Cases() int {
+	return c.cases
+}
@@ -0,0 +1,108 @@
+package eventlog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"bpmn-manager/models"
+)
+
+// Format is an event log file format
+type Format string
+
+const (
+	FormatXES Format = "xes"
+	FormatCSV Format = "csv"
+)
+
+// Formats lists the supported formats in the order offered to users
+var Formats = []Format{FormatXES, FormatCSV}
+
+// -----------------------------------------------------------------------
+// ParseFormat accepts a format name or file extension
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "xes", "xml":
+		return FormatXES, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unknown event log format %q (use xes or csv)", name)
+}
+
+// Lifecycle transitions of the standard XES lifecycle extension
+const (
+	LifecycleStart    = "start"
+	LifecycleComplete = "complete"
+)
+
+// Event is one lifecycle transition of an activity in a case
+type Event struct {
+	CaseID       string
+	ProcessKey   string
+	ActivityID   string
+	Activity     string // activity name, the id when unnamed
+	ActivityType string
+	Resource     string
+	Lifecycle    string
+	Timestamp    time.Time
+}
+
+// Writer writes cases one at a time so a log never has to be held in
+// memory as a whole. Close finishes the file but does not close the
+// underlying writer.
+type Writer interface {
+	WriteCase(process models.ProcessDetails) error
+	Close() error
+	Cases() int
+}
+
+// -----------------------------------------------------------------------
+// Events returns the start and complete events of an instance in time
+// order; activities still running only have a start event
+func Events(process models.ProcessDetails) []Event {
+	events := make([]Event, 0, 2*len(process.Activities))
+	for _, activity := range process.Activities {
+		event := Event{
+			CaseID:       process.ID,
+			ProcessKey:   process.ProcessDefinitionKey,
+			ActivityID:   activity.ID,
+			Activity:     activity.Name,
+			ActivityType: activity.Type,
+			Resource:     activity.Assignee,
+		}
+		if event.Activity == "" {
+			event.Activity = activity.ID
+		}
+		if !activity.StartTime.IsZero() {
+			start := event
+			start.Lifecycle, start.Timestamp = LifecycleStart, activity.StartTime
+			events = append(events, start)
+		}
+		if !activity.EndTime.IsZero() {
+			complete := event
+			complete.Lifecycle, complete.Timestamp = LifecycleComplete, activity.EndTime
+			events = append(events, complete)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// -----------------------------------------------------------------------
+// New starts a log in the given format; variableNames only apply to CSV,
+// XES logs carry all variables of each case
+func New(format Format, w io.Writer, variableNames []string) (Writer, error) {
+	switch format {
+	case FormatXES:
+		return NewXES(w)
+	case FormatCSV:
+		return NewCSV(w, variableNames)
+	}
+	return nil, fmt.Errorf("unknown event log format %q", format)
+}
@@ -0,0 +1,174 @@
+package eventlog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bpmn-manager/models"
+)
+
+var day = time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
+
+// -----------------------------------------------------------------------
+// order is a case with a finished task, a running task and an unnamed
+// gateway between them
+func order() models.ProcessDetails {
+	return models.ProcessDetails{
+		ID:                   "case-1",
+		ProcessDefinitionKey: "order",
+		ProcessDefinitionId:  "order:1",
+		CurrentVariables: map[string]interface{}{
+			"approved": true,
+			"amount":   12.5,
+			"customer": map[string]interface{}{"name": "Ada"},
+			"priority": map[string]interface{}{"type": "Short", "value": 3.0},
+		},
+		Activities: []models.ProcessActivity{
+			{ID: "ship", Name: "Ship", Type: "userTask", Assignee: "bob",
+				StartTime: day.Add(20 * time.Minute)},
+			{ID: "check", Name: "Check <order>", Type: "userTask", Assignee: "amy",
+				StartTime: day, EndTime: day.Add(15 * time.Minute)},
+			{ID: "gw", Type: "exclusiveGateway",
+				StartTime: day.Add(15 * time.Minute), EndTime: day.Add(15 * time.Minute)},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"xes", FormatXES, false},
+		{".XES", FormatXES, false},
+		{"xml", FormatXES, false},
+		{"CSV", FormatCSV, false},
+		{".csv", FormatCSV, false},
+		{"json", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	type event struct {
+		activity, lifecycle string
+		at                  time.Duration
+	}
+	var got []event
+	for _, e := range Events(order()) {
+		got = append(got, event{e.Activity, e.Lifecycle, e.Timestamp.Sub(day)})
+	}
+	want := []event{
+		{"Check <order>", LifecycleStart, 0},
+		{"Check <order>", LifecycleComplete, 15 * time.Minute},
+		{"gw", LifecycleStart, 15 * time.Minute},
+		{"gw", LifecycleComplete, 15 * time.Minute},
+		{"Ship", LifecycleStart, 20 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Events() = %v, want %v", got, want)
+	}
+}
+
+func TestXES(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(FormatXES, &out, nil)
+	if err != nil {
+		t.Fatalf("New(xes) error: %v", err)
+	}
+	if err := w.WriteCase(order()); err != nil {
+		t.Fatalf("WriteCase() error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if w.Cases() != 1 {
+		t.Errorf("Cases() = %d, want 1", w.Cases())
+	}
+
+	log := out.String()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"case id", `<string key="concept:name" value="case-1"/>`},
+		{"definition", `<string key="process:definitionId" value="order:1"/>`},
+		{"boolean variable", `<boolean key="variable:approved" value="true"/>`},
+		{"float variable", `<float key="variable:amount" value="12.5"/>`},
+		{"typed variable", `<int key="variable:priority" value="3"/>`},
+		{"json variable", `<string key="variable:customer" value="{&#34;name&#34;:&#34;Ada&#34;}"/>`},
+		{"escaped name", `<string key="concept:name" value="Check &lt;order&gt;"/>`},
+		{"timestamp", `<date key="time:timestamp" value="2024-05-07T09:15:00.000+00:00"/>`},
+		{"resource", `<string key="org:resource" value="amy"/>`},
+		{"end", "  </trace>\n</log>\n"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(log, tt.want) {
+			t.Errorf("XES %s: missing %s", tt.name, tt.want)
+		}
+	}
+	if n := strings.Count(log, "<event>"); n != 5 {
+		t.Errorf("XES has %d events, want 5", n)
+	}
+	// the gateway has no assignee, so only the two task events of amy
+	// and the start of bob's task name a resource
+	if n := strings.Count(log, "org:resource\" value"); n != 3 {
+		t.Errorf("XES has %d resources, want 3", n)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var out bytes.Buffer
+	w, err := New(FormatCSV, &out, []string{"approved", "priority", "missing"})
+	if err != nil {
+		t.Fatalf("New(csv) error: %v", err)
+	}
+	w.WriteCase(order())
+	w.WriteCase(models.ProcessDetails{ID: "case-2"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if w.Cases() != 2 {
+		t.Errorf("Cases() = %d, want 2", w.Cases())
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	if len(rows) != 6 {
+		t.Fatalf("CSV has %d rows, want header and 5 events", len(rows))
+	}
+	header := append(append([]string(nil), csvHeader...), "approved", "priority", "missing")
+	if !reflect.DeepEqual(rows[0], header) {
+		t.Errorf("header = %v, want %v", rows[0], header)
+	}
+
+	tests := []struct {
+		row  int
+		want []string
+	}{
+		{1, []string{"1", "Check <order>", "2024-05-07T09:00:00Z", "['case-1']",
+			"case-1", "Check <order>", "2024-05-07T09:00:00Z", "amy", "start",
+			"check", "userTask", "order", "true", "3", ""}},
+		{5, []string{"5", "Ship", "2024-05-07T09:20:00Z", "['case-1']",
+			"case-1", "Ship", "2024-05-07T09:20:00Z", "bob", "start",
+			"ship", "userTask", "order", "true", "3", ""}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(rows[tt.row], tt.want) {
+			t.Errorf("row %d = %v, want %v", tt.row, rows[tt.row], tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+)
+
+// xesTime is the xs:dateTime layout XES timestamps use
+const xesTime = "2006-01-02T15:04:05.000-07:00"
+
+// xesWriter writes an IEEE 1849 XES log
+type xesWriter struct {
+	out   *bufio.Writer
+	cases int
+}
+
+// -----------------------------------------------------------------------
+// NewXES starts an XES log on w with the concept, time, lifecycle and
+// org extensions
+func NewXES(w io.Writer) (Writer, error) {
+	x := &xesWriter{out: bufio.NewWriter(w)}
+	x.out.WriteString(xml.Header)
+	x.out.WriteString(`<log xes.version="1849-2016" xes.features="" xmlns="http://www.xes-standard.org/">
+  <extension name="Concept" prefix="concept" uri="http://www.xes-standard.org/concept.xesext"/>
+  <extension name="Time" prefix="time" uri="http://www.xes-standard.org/time.xesext"/>
+  <extension name="Lifecycle" prefix="lifecycle" uri="http://www.xes-standard.org/lifecycle.xesext"/>
+  <extension name="Organizational" prefix="org" uri="http://www.xes-standard.org/org.xesext"/>
+  <global scope="trace">
+    <string key="concept:name" value="__INVALID__"/>
+  </global>
+  <global scope="event">
+    <string key="concept:name" value="__INVALID__"/>
+    <date key="time:timestamp" value="1970-01-01T00:00:00.000+00:00"/>
+    <string key="lifecycle:transition" value="complete"/>
+  </global>
+  <classifier name="Activity" keys="concept:name"/>
+  <classifier name="Activity with lifecycle" keys="concept:name lifecycle:transition"/>
+  <string key="concept:name" value="bpmn-manager"/>
+`)
+	return x, x.out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func (x *xesWriter) WriteCase(process models.ProcessDetails) error {
+	x.out.WriteString("  <trace>\n")
+	x.attribute("    ", "string", "concept:name", process.ID)
+	x.attribute("    ", "string", "process:definitionKey", process.ProcessDefinitionKey)
+	x.attribute("    ", "string", "process:definitionId", process.ProcessDefinitionId)
+	for _, variable := range variables.FromMap(process.CurrentVariables) {
+		typ, value := xesValue(variable)
+		x.attribute("    ", typ, "variable:"+variable.Name, value)
+	}
+
+	for _, event := range Events(process) {
+		x.out.WriteString("    <event>\n")
+		x.attribute("      ", "string", "concept:name", event.Activity)
+		x.attribute("      ", "string", "activity:id", event.ActivityID)
+		x.attribute("      ", "string", "activity:type", event.ActivityType)
+		x.attribute("      ", "string", "lifecycle:transition", event.Lifecycle)
+		x.attribute("      ", "date", "time:timestamp", event.Timestamp.Format(xesTime))
+		if event.Resource != "" {
+			x.attribute("      ", "string", "org:resource", event.Resource)
+		}
+		x.out.WriteString("    </event>\n")
+	}
+	x.out.WriteString("  </trace>\n")
+	x.cases++
+	// flushing per case keeps memory bounded by the largest case
+	return x.out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func (x *xesWriter) attribute(indent, typ, key, value string) {
+	fmt.Fprintf(x.out, "%s<%s key=\"", indent, typ)
+	xml.EscapeText(x.out, []byte(key))
+	x.out.WriteString("\" value=\"")
+	xml.EscapeText(x.out, []byte(value))
+	x.out.WriteString("\"/>\n")
+}
+
+// -----------------------------------------------------------------------
+func (x *xesWriter) Close() error {
+	x.out.WriteString("</log>\n")
+	return x.out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func (x *xesWriter) Cases() int {
+	return x.cases
+}
+
+// -----------------------------------------------------------------------
+// xesValue maps a process variable onto an XES attribute type and value
+func xesValue(variable variables.Variable) (string, string) {
+	switch variable.Type {
+	case variables.TypeBoolean:
+		return "boolean", variables.Format(variable.Value)
+	case variables.TypeShort, variables.TypeInteger, variables.TypeLong:
+		return "int", variables.Format(variable.Value)
+	case variables.TypeDouble:
+		return "float", variables.Format(variable.Value)
+	case variables.TypeDate:
+		if text, ok := variable.Value.(string); ok {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700"} {
+				if t, err := time.Parse(layout, text); err == nil {
+					return "date", t.Format(xesTime)
+				}
+			}
+		}
+	}
+	return "string", valueText(variable.Value)
+}
+
+// -----------------------------------------------------------------------
+// valueText renders a variable value on one line, JSON for objects
+func valueText(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	return variables.Format(value)
+}
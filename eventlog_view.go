@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/eventlog"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showEventLogExport lets analysts export process histories for external
+// process mining tools
+func (m *BPMNManager) showEventLogExport() {
+	formats := make([]string, len(eventlog.Formats))
+	for i, f := range eventlog.Formats {
+		formats[i] = string(f)
+	}
+	sources := []string{"Completed instances", "Running instances", "Completed and running"}
+
+	form := tview.NewForm().
+		AddDropDown("Format", formats, 0, nil).
+		AddDropDown("Instances", sources, 0, nil).
+		AddInputField("Process key", "", 40, nil, nil).
+		AddInputField("CSV variables", "", 40, nil, nil).
+		AddInputField("Output file", "eventlog.xes", 50, nil, nil)
+
+	form.GetFormItem(0).(*tview.DropDown).SetSelectedFunc(func(text string, index int) {
+		output := form.GetFormItem(4).(*tview.InputField)
+		name := strings.TrimSuffix(strings.TrimSuffix(output.GetText(), ".xes"), ".csv")
+		output.SetText(name + "." + text)
+	})
+
+	form.AddButton("Export", func() {
+		_, formatName := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		sourceIndex, _ := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		source := eventLogSource{
+			completed: sourceIndex != 1,
+			running:   sourceIndex != 0,
+			key:       strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText()),
+		}
+		var variableNames []string
+		for _, name := range strings.Split(form.GetFormItem(3).(*tview.InputField).GetText(), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				variableNames = append(variableNames, name)
+			}
+		}
+		path := strings.TrimSpace(form.GetFormItem(4).(*tview.InputField).GetText())
+		if path == "" {
+			m.showError("Enter an output file")
+			return
+		}
+
+		status := tview.NewModal().SetText("📤 Exporting event log...")
+		m.pages.AddPage("eventlog_progress", status, true, true)
+		m.pages.SwitchToPage("eventlog_progress")
+		go func() {
+			cases, err := writeEventLog(m.apiClient, source, eventlog.Format(formatName), variableNames, path, func(cases int) {
+				if cases%50 == 0 {
+					m.app.QueueUpdateDraw(func() {
+						status.SetText(fmt.Sprintf("📤 Exporting event log... %d cases", cases))
+					})
+				}
+			})
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Event log export failed: " + err.Error())
+					return
+				}
+				m.showMessage(fmt.Sprintf("%d cases exported to %s", cases, path))
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Export Event Log • XES or CSV ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("eventlog", form, true, true)
+	m.pages.SwitchToPage("eventlog")
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"bpmn-manager/bpmn"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// -----------------------------------------------------------------------
+// DOT writes the model as a Graphviz digraph
+func DOT(w io.Writer, model *bpmn.Model, overlay *Overlay) error {
+	out := bufio.NewWriter(w)
+	grouped := children(model)
+
+	fmt.Fprintf(out, "digraph %s {\n", dotQuote(model.ProcessID))
+	fmt.Fprintln(out, "    rankdir=LR;")
+	fmt.Fprintln(out, "    node [fontname=\"Helvetica\", fontsize=10];")
+	fmt.Fprintln(out, "    edge [fontname=\"Helvetica\", fontsize=9];")
+	writeDOTNodes(out, model, grouped, "", "    ", overlay)
+
+	for _, flow := range model.Flows {
+		attrs := []string{}
+		if flow.Name != "" {
+			attrs = append(attrs, "label="+dotQuote(flow.Name))
+		}
+		if overlay.taken(flow.ID) {
+			attrs = append(attrs, "color=\"#2e7d32\"", "penwidth=2.5")
+		}
+		fmt.Fprintf(out, "    %s -> %s", dotQuote(flow.Source), dotQuote(flow.Target))
+		if len(attrs) > 0 {
+			fmt.Fprintf(out, " [%s]", strings.Join(attrs, ", "))
+		}
+		fmt.Fprintln(out, ";")
+	}
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		if node.AttachedTo != "" {
+			fmt.Fprintf(out, "    %s -> %s [style=dotted, arrowhead=none];\n", dotQuote(node.AttachedTo), dotQuote(node.ID))
+		}
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func writeDOTNodes(out *bufio.Writer, model *bpmn.Model, grouped map[string][]string, parent, indent string, overlay *Overlay) {
+	for _, id := range grouped[parent] {
+		node := model.Nodes[id]
+		label := node.Label()
+		if extra := overlay.label(id); extra != "" {
+			label += "\n" + extra
+		}
+
+		if node.IsSubProcess() {
+			fmt.Fprintf(out, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+id))
+			fmt.Fprintf(out, "%s    label=%s;\n", indent, dotQuote(label))
+			fmt.Fprintf(out, "%s    style=rounded;\n", indent)
+			// an invisible anchor lets sequence flows attach to the cluster
+			fmt.Fprintf(out, "%s    %s [shape=point, width=0.05];\n", indent, dotQuote(id))
+			writeDOTNodes(out, model, grouped, id, indent+"    ", overlay)
+			fmt.Fprintf(out, "%s}\n", indent)
+			continue
+		}
+
+		attrs := []string{"label=" + dotQuote(label)}
+		switch {
+		case node.Kind == "endEvent":
+			attrs = append(attrs, "shape=circle", "penwidth=3")
+		case node.IsEvent():
+			attrs = append(attrs, "shape=circle")
+		case node.IsGateway():
+			attrs = append(attrs, "shape=diamond")
+		default:
+			attrs = append(attrs, "shape=box", "style=\"rounded,filled\"")
+		}
+		if fill := overlay.fillColor(id); fill != "" {
+			if !node.IsActivity() {
+				attrs = append(attrs, "style=filled")
+			}
+			attrs = append(attrs, "fillcolor="+dotQuote(fill))
+		} else if node.IsActivity() {
+			attrs = append(attrs, "fillcolor=\"#ffffff\"")
+		}
+		if overlay.active(id) {
+			attrs = append(attrs, "color=\"#f57f17\"", "penwidth=3")
+		}
+		fmt.Fprintf(out, "%s%s [%s];\n", indent, dotQuote(id), strings.Join(attrs, ", "))
+	}
+}
+
+// -----------------------------------------------------------------------
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "\"", "\\\"")
+	return "\"" + strings.ReplaceAll(text, "\n", "\\n") + "\""
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bpmn-manager/bpmn"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatMermaid Format = "mermaid"
+	FormatDOT     Format = "dot"
+	FormatSVG     Format = "svg"
+)
+
+// Formats lists the supported diagram formats in display order
+var Formats = []Format{FormatMermaid, FormatDOT, FormatSVG}
+
+// -----------------------------------------------------------------------
+// ParseFormat accepts a format name or a file extension
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "mermaid", "mmd", "md":
+		return FormatMermaid, nil
+	case "dot", "gv", "graphviz":
+		return FormatDOT, nil
+	case "svg":
+		return FormatSVG, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (use mermaid, dot or svg)", name)
+}
+
+// -----------------------------------------------------------------------
+// Extension returns the conventional file extension for a format
+func (f Format) Extension() string {
+	switch f {
+	case FormatMermaid:
+		return ".mmd"
+	case FormatDOT:
+		return ".dot"
+	}
+	return "." + string(f)
+}
+
+// -----------------------------------------------------------------------
+// Write renders the model in the given format. The overlay may be nil.
+func Write(w io.Writer, model *bpmn.Model, format Format, overlay *Overlay) error {
+	if model == nil {
+		return fmt.Errorf("no BPMN model to export")
+	}
+	switch format {
+	case FormatMermaid:
+		return Mermaid(w, model, overlay)
+	case FormatDOT:
+		return DOT(w, model, overlay)
+	case FormatSVG:
+		return SVG(w, model, overlay)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+var unsafeID = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// -----------------------------------------------------------------------
+func safeID(id string) string {
+	return unsafeID.ReplaceAllString(id, "_")
+}
+
+// -----------------------------------------------------------------------
+// children groups node ids by their enclosing sub process, preserving
+// document order
+func children(model *bpmn.Model) map[string][]string {
+	grouped := make(map[string][]string)
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		grouped[node.Parent] = append(grouped[node.Parent], id)
+	}
+	return grouped
+}
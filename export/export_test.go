@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+)
+
+// reviewModel is start → "Check \"A|B\"" → gateway → end, with a quoted
+// flow name and a task inside a sub process
+const reviewModel = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <process id="review-1" name="Review &lt;Orders&gt;" isExecutable="true">
+    <startEvent id="start"/>
+    <userTask id="check" name="Check &quot;A|B&quot;"/>
+    <exclusiveGateway id="ok"/>
+    <subProcess id="sub" name="Archive">
+      <serviceTask id="store" name="Store"/>
+    </subProcess>
+    <endEvent id="end" name="Done"/>
+    <sequenceFlow id="f1" sourceRef="start" targetRef="check"/>
+    <sequenceFlow id="f2" sourceRef="check" targetRef="ok"/>
+    <sequenceFlow id="f3" name="yes &amp; &quot;sure&quot;" sourceRef="ok" targetRef="sub"/>
+    <sequenceFlow id="f4" sourceRef="sub" targetRef="end"/>
+  </process>
+</definitions>`
+
+// -----------------------------------------------------------------------
+func render(t *testing.T, format Format, overlay *Overlay) string {
+	model, err := bpmn.Parse([]byte(reviewModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, model, format, overlay); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		want Format
+	}{
+		{"mermaid", FormatMermaid},
+		{".mmd", FormatMermaid},
+		{"DOT", FormatDOT},
+		{"gv", FormatDOT},
+		{".svg", FormatSVG},
+	}
+	for _, tt := range tests {
+		if got, err := ParseFormat(tt.name); err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := ParseFormat("png"); err == nil {
+		t.Error("ParseFormat(png) succeeded")
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   []string
+		absent []string
+	}{
+		{FormatDOT, []string{
+			`digraph "review-1" {`,
+			`"check" [label="Check \"A|B\""`,
+			`"ok" -> "sub" [label="yes & \"sure\""];`,
+			`subgraph "cluster_sub" {`,
+		}, nil},
+		{FormatMermaid, []string{
+			`check("Check #quot;A#124;B#quot;")`,
+			`ok -->|yes & #quot;sure#quot;| sub`,
+			`subgraph sub["Archive"]`,
+			`end(((`,
+		}, []string{`"A|B"`}},
+		{FormatSVG, []string{
+			`<title>Review &lt;Orders&gt;</title>`,
+			`<title>Check &#34;A|B&#34; (userTask)</title>`,
+			`yes &amp; &#34;sure&#34;`,
+		}, []string{`<Orders>`, `"sure"`}},
+	}
+	for _, tt := range tests {
+		out := render(t, tt.format, nil)
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output lacks %s:\n%s", tt.format, want, out)
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(out, absent) {
+				t.Errorf("%s output contains unescaped %s", tt.format, absent)
+			}
+		}
+	}
+}
+
+func TestSafeID(t *testing.T) {
+	tests := []struct {
+		id, want string
+	}{
+		{"Activity_0abc", "Activity_0abc"},
+		{"review-1", "review_1"},
+		{"a.b c", "a_b_c"},
+	}
+	for _, tt := range tests {
+		if got := safeID(tt.id); got != tt.want {
+			t.Errorf("safeID(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestInstancePath(t *testing.T) {
+	model, err := bpmn.Parse([]byte(reviewModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
+	details := &models.ProcessDetails{Activities: []models.ProcessActivity{
+		{ID: "check", StartTime: base.Add(time.Minute), EndTime: base.Add(time.Hour)},
+		{ID: "start", StartTime: base, EndTime: base},
+		{ID: "ok", StartTime: base.Add(time.Hour)},
+	}}
+	overlay := InstancePath(model, details)
+
+	if got := []int{overlay.Visited["start"], overlay.Visited["check"], overlay.Visited["ok"]}; got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("visit order = %v, want [1 2 3]", got)
+	}
+	if !overlay.Active["ok"] || overlay.Active["check"] {
+		t.Errorf("active = %v, want only ok", overlay.Active)
+	}
+	if !overlay.Taken["f1"] || !overlay.Taken["f2"] || overlay.Taken["f3"] {
+		t.Errorf("taken = %v, want f1 and f2", overlay.Taken)
+	}
+
+	out := render(t, FormatMermaid, overlay)
+	for _, want := range []string{"start ==> check", "class start,check visited", "class ok active"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mermaid overlay lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{-1, "#4caf50"},
+		{0, "#4caf50"},
+		{0.5, "#ffeb3b"},
+		{1, "#f44336"},
+		{2, "#f44336"},
+	}
+	for _, tt := range tests {
+		if got := HeatColor(tt.value); got != tt.want {
+			t.Errorf("HeatColor(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
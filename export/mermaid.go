@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bpmn-manager/bpmn"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// -----------------------------------------------------------------------
+// Mermaid writes the model as a Mermaid flowchart
+func Mermaid(w io.Writer, model *bpmn.Model, overlay *Overlay) error {
+	out := bufio.NewWriter(w)
+	grouped := children(model)
+
+	fmt.Fprintln(out, "flowchart LR")
+	writeMermaidNodes(out, model, grouped, "", "    ", overlay)
+
+	for _, flow := range model.Flows {
+		arrow := "-->"
+		if overlay.taken(flow.ID) {
+			arrow = "==>"
+		}
+		if flow.Name != "" {
+			fmt.Fprintf(out, "    %s %s|%s| %s\n", safeID(flow.Source), arrow, mermaidText(flow.Name), safeID(flow.Target))
+		} else {
+			fmt.Fprintf(out, "    %s %s %s\n", safeID(flow.Source), arrow, safeID(flow.Target))
+		}
+	}
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		if node.AttachedTo != "" {
+			fmt.Fprintf(out, "    %s -.- %s\n", safeID(node.AttachedTo), safeID(node.ID))
+		}
+	}
+
+	var visited, active []string
+	for _, id := range model.Order {
+		switch {
+		case overlay.active(id):
+			active = append(active, safeID(id))
+		case overlay.visited(id):
+			visited = append(visited, safeID(id))
+		default:
+			if value, ok := overlay.heat(id); ok {
+				fmt.Fprintf(out, "    style %s fill:%s\n", safeID(id), HeatColor(value))
+			}
+		}
+	}
+	if len(visited) > 0 {
+		fmt.Fprintln(out, "    classDef visited fill:#c8e6c9,stroke:#2e7d32")
+		fmt.Fprintf(out, "    class %s visited\n", strings.Join(visited, ","))
+	}
+	if len(active) > 0 {
+		fmt.Fprintln(out, "    classDef active fill:#fff59d,stroke:#f57f17,stroke-width:3px")
+		fmt.Fprintf(out, "    class %s active\n", strings.Join(active, ","))
+	}
+
+	return out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func writeMermaidNodes(out *bufio.Writer, model *bpmn.Model, grouped map[string][]string, parent, indent string, overlay *Overlay) {
+	for _, id := range grouped[parent] {
+		node := model.Nodes[id]
+		label := node.Label()
+		if extra := overlay.label(id); extra != "" {
+			label += "<br/>" + extra
+		}
+		text := mermaidText(label)
+
+		if node.IsSubProcess() {
+			fmt.Fprintf(out, "%ssubgraph %s[\"%s\"]\n", indent, safeID(id), text)
+			writeMermaidNodes(out, model, grouped, id, indent+"    ", overlay)
+			fmt.Fprintf(out, "%send\n", indent)
+			continue
+		}
+
+		switch {
+		case node.Kind == "endEvent":
+			fmt.Fprintf(out, "%s%s(((\"%s\")))\n", indent, safeID(id), text)
+		case node.IsEvent():
+			fmt.Fprintf(out, "%s%s((\"%s\"))\n", indent, safeID(id), text)
+		case node.IsGateway():
+			fmt.Fprintf(out, "%s%s{\"%s\"}\n", indent, safeID(id), text)
+		default:
+			fmt.Fprintf(out, "%s%s(\"%s\")\n", indent, safeID(id), text)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------
+func mermaidText(text string) string {
+	text = strings.ReplaceAll(text, "\"", "#quot;")
+	text = strings.ReplaceAll(text, "|", "#124;")
+	return strings.ReplaceAll(text, "\n", " ")
+}
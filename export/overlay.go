@@ -0,0 +1,197 @@
+package export
+
+import (
+	"bpmn-manager/bpmn"
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Overlay decorates an exported diagram with runtime information
+type Overlay struct {
+	Visited map[string]int     // node id -> position in the instance path (1-based)
+	Active  map[string]bool    // nodes currently holding a token
+	Taken   map[string]bool    // sequence flows traversed by the instance
+	Labels  map[string]string  // extra text shown under a node, e.g. statistics
+	Heat    map[string]float64 // 0..1 intensity used to colour a node
+}
+
+// -----------------------------------------------------------------------
+// InstancePath builds an overlay highlighting the activities an instance
+// has executed and the ones it is currently waiting in
+func InstancePath(model *bpmn.Model, details *models.ProcessDetails) *Overlay {
+	overlay := &Overlay{
+		Visited: make(map[string]int),
+		Active:  make(map[string]bool),
+		Taken:   make(map[string]bool),
+	}
+	if details == nil {
+		return overlay
+	}
+
+	activities := append([]models.ProcessActivity(nil), details.Activities...)
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].StartTime.Before(activities[j].StartTime)
+	})
+	for i, activity := range activities {
+		if _, seen := overlay.Visited[activity.ID]; !seen {
+			overlay.Visited[activity.ID] = i + 1
+		}
+		if activity.EndTime.IsZero() && details.EndTime == "" {
+			overlay.Active[activity.ID] = true
+		}
+	}
+
+	if model != nil {
+		for _, flow := range model.Flows {
+			_, fromSeen := overlay.Visited[flow.Source]
+			_, toSeen := overlay.Visited[flow.Target]
+			if fromSeen && toSeen {
+				overlay.Taken[flow.ID] = true
+			}
+		}
+	}
+	return overlay
+}
+
+// -----------------------------------------------------------------------
+// ActivityStatistics builds an overlay labelling each activity with its
+// execution count and mean duration over the given histories. Nodes are
+// heat-coloured by mean duration relative to the slowest activity.
+func ActivityStatistics(histories []models.ProcessDetails) *Overlay {
+	counts := make(map[string]int)
+	totals := make(map[string]time.Duration)
+	for _, history := range histories {
+		for _, activity := range history.Activities {
+			counts[activity.ID]++
+			totals[activity.ID] += time.Duration(activity.Duration) * time.Millisecond
+		}
+	}
+
+	means := make(map[string]time.Duration, len(counts))
+	var slowest time.Duration
+	for id, count := range counts {
+		means[id] = totals[id] / time.Duration(count)
+		if means[id] > slowest {
+			slowest = means[id]
+		}
+	}
+
+	overlay := &Overlay{
+		Labels: make(map[string]string, len(counts)),
+		Heat:   make(map[string]float64, len(counts)),
+	}
+	for id, count := range counts {
+		overlay.Labels[id] = fmt.Sprintf("n=%d avg=%s", count, means[id].Round(time.Second))
+		if slowest > 0 {
+			overlay.Heat[id] = float64(means[id]) / float64(slowest)
+		}
+	}
+	return overlay
+}
+
+// -----------------------------------------------------------------------
+// Bottlenecks builds an overlay labelling each activity with its p90
+// duration and mean wait. Nodes are heat-coloured by the time instances
+// spend there relative to the worst bottleneck.
+func Bottlenecks(stats []mining.ActivityStats) *Overlay {
+	overlay := &Overlay{
+		Labels: make(map[string]string, len(stats)),
+		Heat:   make(map[string]float64, len(stats)),
+	}
+	var worst time.Duration
+	for _, s := range stats {
+		if s.Score() > worst {
+			worst = s.Score()
+		}
+	}
+	for _, s := range stats {
+		overlay.Labels[s.ActivityID] = fmt.Sprintf("p90=%s wait=%s", s.P90.Round(time.Second), s.MeanWait.Round(time.Second))
+		if worst > 0 {
+			overlay.Heat[s.ActivityID] = float64(s.Score()) / float64(worst)
+		}
+	}
+	return overlay
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) visited(id string) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.Visited[id]
+	return ok
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) order(id string) int {
+	if o == nil {
+		return 0
+	}
+	return o.Visited[id]
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) active(id string) bool {
+	return o != nil && o.Active[id]
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) taken(id string) bool {
+	return o != nil && o.Taken[id]
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) label(id string) string {
+	if o == nil {
+		return ""
+	}
+	return o.Labels[id]
+}
+
+// -----------------------------------------------------------------------
+func (o *Overlay) heat(id string) (float64, bool) {
+	if o == nil {
+		return 0, false
+	}
+	value, ok := o.Heat[id]
+	return value, ok
+}
+
+// -----------------------------------------------------------------------
+// fillColor returns the fill for a node given the overlay, or "" for the
+// default fill
+func (o *Overlay) fillColor(id string) string {
+	switch {
+	case o.active(id):
+		return "#fff59d"
+	case o.visited(id):
+		return "#c8e6c9"
+	}
+	if value, ok := o.heat(id); ok {
+		return HeatColor(value)
+	}
+	return ""
+}
+
+// -----------------------------------------------------------------------
+// HeatColor maps 0..1 onto a green -> yellow -> red gradient
+func HeatColor(value float64) string {
+	if value < 0 {
+		value = 0
+	}
+	if value > 1 {
+		value = 1
+	}
+	var r, g, b float64
+	if value < 0.5 {
+		t := value / 0.5
+		r, g, b = 76+(255-76)*t, 175+(235-175)*t, 80+(59-80)*t
+	} else {
+		t := (value - 0.5) / 0.5
+		r, g, b = 255+(244-255)*t, 235+(67-235)*t, 59+(54-59)*t
+	}
+	return fmt.Sprintf("#%02x%02x%02x", int(r), int(g), int(b))
+}
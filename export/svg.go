@@ -0,0 +1,315 @@
+package export
+
+import (
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+const (
+	svgMargin     = 20.0
+	layoutTaskW   = 100.0
+	layoutTaskH   = 80.0
+	layoutEventD  = 36.0
+	layoutGateway = 50.0
+	layoutColumn  = 160.0
+	layoutRow     = 120.0
+)
+
+// -----------------------------------------------------------------------
+// SVG writes a self-contained SVG drawing of the model. BPMNDI coordinates
+// are used when the document carries them, otherwise a simple layered
+// layout is computed.
+func SVG(w io.Writer, model *bpmn.Model, overlay *Overlay) error {
+	shapes, edges := diagramGeometry(model)
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(x, y float64) {
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	for _, b := range shapes {
+		extend(b.X, b.Y)
+		extend(b.X+b.Width, b.Y+b.Height+28) // room for overlay labels
+	}
+	for _, points := range edges {
+		for _, p := range points {
+			extend(p.X, p.Y)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	minX, minY = minX-svgMargin, minY-svgMargin
+	width, height := maxX-minX+svgMargin, maxY-minY+svgMargin
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%.0f %.0f %.0f %.0f" width="%.0f" height="%.0f" font-family="Helvetica, Arial, sans-serif" font-size="11">`+"\n",
+		minX, minY, width, height, width, height)
+	fmt.Fprintf(out, "<title>%s</title>\n", html.EscapeString(model.ProcessName))
+	fmt.Fprintln(out, `<defs>`)
+	fmt.Fprintln(out, `  <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#333"/></marker>`)
+	fmt.Fprintln(out, `  <marker id="arrow-taken" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#2e7d32"/></marker>`)
+	fmt.Fprintln(out, `</defs>`)
+	fmt.Fprintln(out, `<rect x="-100000" y="-100000" width="200000" height="200000" fill="#ffffff"/>`)
+
+	// sub processes first so their children are drawn on top
+	for _, id := range model.Order {
+		if node := model.Nodes[id]; node.IsSubProcess() {
+			writeSVGNode(out, node, shapes[id], overlay)
+		}
+	}
+
+	for _, flow := range model.Flows {
+		points := edges[flow.ID]
+		if len(points) < 2 {
+			continue
+		}
+		stroke, marker, strokeWidth := "#333", "arrow", 1.2
+		if overlay.taken(flow.ID) {
+			stroke, marker, strokeWidth = "#2e7d32", "arrow-taken", 2.5
+		}
+		fmt.Fprintf(out, `<polyline fill="none" stroke="%s" stroke-width="%.1f" marker-end="url(#%s)" points="`, stroke, strokeWidth, marker)
+		for i, p := range points {
+			if i > 0 {
+				fmt.Fprint(out, " ")
+			}
+			fmt.Fprintf(out, "%.1f,%.1f", p.X, p.Y)
+		}
+		fmt.Fprintln(out, `"/>`)
+		if flow.Name != "" {
+			mid := points[len(points)/2]
+			fmt.Fprintf(out, `<text x="%.1f" y="%.1f" fill="#555">%s</text>`+"\n", mid.X+4, mid.Y-4, html.EscapeString(flow.Name))
+		}
+	}
+
+	for _, id := range model.Order {
+		if node := model.Nodes[id]; !node.IsSubProcess() {
+			writeSVGNode(out, node, shapes[id], overlay)
+		}
+	}
+
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
+
+// -----------------------------------------------------------------------
+func writeSVGNode(out *bufio.Writer, node *bpmn.Node, b models.Bounds, overlay *Overlay) {
+	fill := overlay.fillColor(node.ID)
+	if fill == "" {
+		fill = "#ffffff"
+	}
+	stroke, strokeWidth := "#333", 1.5
+	if overlay.active(node.ID) {
+		stroke, strokeWidth = "#f57f17", 3
+	}
+	cx, cy := b.X+b.Width/2, b.Y+b.Height/2
+
+	fmt.Fprintf(out, `<g id="%s">`, html.EscapeString(node.ID))
+	fmt.Fprintf(out, "<title>%s (%s)</title>", html.EscapeString(node.Label()), node.Kind)
+	switch {
+	case node.IsEvent():
+		r := math.Min(b.Width, b.Height) / 2
+		if node.Kind == "endEvent" {
+			strokeWidth += 2
+		}
+		fmt.Fprintf(out, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s" stroke-width="%.1f"/>`, cx, cy, r, fill, stroke, strokeWidth)
+		if node.Kind == "intermediateCatchEvent" || node.Kind == "intermediateThrowEvent" || node.Kind == "boundaryEvent" {
+			fmt.Fprintf(out, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s"/>`, cx, cy, r-3, stroke)
+		}
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, cx, b.Y+b.Height+13, html.EscapeString(node.Name))
+	case node.IsGateway():
+		fmt.Fprintf(out, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s" stroke="%s" stroke-width="%.1f"/>`,
+			cx, b.Y, b.X+b.Width, cy, cx, b.Y+b.Height, b.X, cy, fill, stroke, strokeWidth)
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="16">%s</text>`, cx, cy+6, gatewaySymbol(node.Kind))
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, cx, b.Y-4, html.EscapeString(node.Name))
+	case node.IsSubProcess():
+		fmt.Fprintf(out, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="10" fill="%s" fill-opacity="0.4" stroke="%s" stroke-width="%.1f"/>`,
+			b.X, b.Y, b.Width, b.Height, fill, stroke, strokeWidth)
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f">%s</text>`, b.X+6, b.Y+14, html.EscapeString(node.Label()))
+	default:
+		fmt.Fprintf(out, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="10" fill="%s" stroke="%s" stroke-width="%.1f"/>`,
+			b.X, b.Y, b.Width, b.Height, fill, stroke, strokeWidth)
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, cx, cy+4, html.EscapeString(node.Label()))
+	}
+	if extra := overlay.label(node.ID); extra != "" {
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="9" fill="#b71c1c">%s</text>`, cx, b.Y+b.Height+24, html.EscapeString(extra))
+	}
+	if order := overlay.order(node.ID); order > 0 {
+		fmt.Fprintf(out, `<text x="%.1f" y="%.1f" font-size="9" fill="#1b5e20">#%d</text>`, b.X, b.Y-2, order)
+	}
+	fmt.Fprintln(out, "</g>")
+}
+
+// -----------------------------------------------------------------------
+func gatewaySymbol(kind string) string {
+	switch kind {
+	case "parallelGateway":
+		return "+"
+	case "inclusiveGateway":
+		return "o"
+	case "eventBasedGateway":
+		return "&#x2B20;"
+	case "complexGateway":
+		return "*"
+	}
+	return "x"
+}
+
+// -----------------------------------------------------------------------
+// diagramGeometry returns node bounds and edge waypoints, taken from BPMNDI
+// when available and computed otherwise
+func diagramGeometry(model *bpmn.Model) (map[string]models.Bounds, map[string][]models.Waypoint) {
+	shapes := make(map[string]models.Bounds, len(model.Nodes))
+	edges := make(map[string][]models.Waypoint, len(model.Flows))
+
+	if model.HasDiagram() {
+		for id, node := range model.Nodes {
+			shapes[id] = *node.Shape
+		}
+	} else {
+		shapes = layeredLayout(model)
+	}
+
+	for _, flow := range model.Flows {
+		if len(flow.Waypoints) >= 2 && model.HasDiagram() {
+			edges[flow.ID] = flow.Waypoints
+			continue
+		}
+		from, okFrom := shapes[flow.Source]
+		to, okTo := shapes[flow.Target]
+		if !okFrom || !okTo {
+			continue
+		}
+		start := models.Waypoint{X: from.X + from.Width, Y: from.Y + from.Height/2}
+		end := models.Waypoint{X: to.X, Y: to.Y + to.Height/2}
+		if end.X < start.X {
+			// back edge: route below both shapes
+			below := math.Max(from.Y+from.Height, to.Y+to.Height) + 25
+			edges[flow.ID] = []models.Waypoint{
+				{X: from.X + from.Width/2, Y: from.Y + from.Height},
+				{X: from.X + from.Width/2, Y: below},
+				{X: to.X + to.Width/2, Y: below},
+				{X: to.X + to.Width/2, Y: to.Y + to.Height},
+			}
+			continue
+		}
+		if start.Y == end.Y {
+			edges[flow.ID] = []models.Waypoint{start, end}
+			continue
+		}
+		midX := (start.X + end.X) / 2
+		edges[flow.ID] = []models.Waypoint{start, {X: midX, Y: start.Y}, {X: midX, Y: end.Y}, end}
+	}
+	return shapes, edges
+}
+
+// -----------------------------------------------------------------------
+// layeredLayout places nodes in columns by their longest distance from a
+// start event, ignoring back edges, and stacks nodes of a column
+// vertically. Boundary events sit on the lower edge of their host.
+func layeredLayout(model *bpmn.Model) map[string]models.Bounds {
+	back := backEdges(model)
+	depth := make(map[string]int, len(model.Nodes))
+
+	// longest path relaxation; the graph without back edges is a DAG so
+	// len(Nodes) rounds are enough
+	for round := 0; round < len(model.Nodes); round++ {
+		changed := false
+		for _, flow := range model.Flows {
+			if back[flow.ID] {
+				continue
+			}
+			if depth[flow.Source]+1 > depth[flow.Target] {
+				depth[flow.Target] = depth[flow.Source] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	rows := make(map[int]int)
+	shapes := make(map[string]models.Bounds, len(model.Nodes))
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		if node.AttachedTo != "" {
+			continue
+		}
+		col := depth[id]
+		row := rows[col]
+		rows[col]++
+
+		w, h := layoutTaskW, layoutTaskH
+		switch {
+		case node.IsEvent():
+			w, h = layoutEventD, layoutEventD
+		case node.IsGateway():
+			w, h = layoutGateway, layoutGateway
+		}
+		x := float64(col)*layoutColumn + (layoutColumn-w)/2
+		y := float64(row)*layoutRow + (layoutRow-h)/2
+		shapes[id] = models.Bounds{X: x, Y: y, Width: w, Height: h}
+	}
+
+	for _, id := range model.Order {
+		node := model.Nodes[id]
+		host, ok := shapes[node.AttachedTo]
+		if node.AttachedTo == "" || !ok {
+			continue
+		}
+		index := 0
+		for i, b := range model.Nodes[node.AttachedTo].Boundary {
+			if b.ID == id {
+				index = i
+			}
+		}
+		shapes[id] = models.Bounds{
+			X:      host.X + host.Width - layoutEventD*float64(index+1),
+			Y:      host.Y + host.Height - layoutEventD/2,
+			Width:  layoutEventD,
+			Height: layoutEventD,
+		}
+	}
+	return shapes
+}
+
+// -----------------------------------------------------------------------
+// backEdges finds flows that close a cycle in a depth first walk from the
+// start events
+func backEdges(model *bpmn.Model) map[string]bool {
+	back := make(map[string]bool)
+	state := make(map[string]int) // 0 unvisited, 1 on stack, 2 done
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = 1
+		for _, flow := range model.Nodes[id].Outgoing {
+			switch state[flow.Target] {
+			case 0:
+				visit(flow.Target)
+			case 1:
+				back[flow.ID] = true
+			}
+		}
+		state[id] = 2
+	}
+
+	for _, start := range model.StartNodes() {
+		if state[start.ID] == 0 {
+			visit(start.ID)
+		}
+	}
+	for _, id := range model.Order {
+		if state[id] == 0 {
+			visit(id)
+		}
+	}
+	return back
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showFilePicker browses the local file system starting at dir and calls
+// onSelect with the chosen file. Esc returns to the page it was opened
+// from.
+func (m *BPMNManager) showFilePicker(title, dir string, onSelect func(path string)) {
+	back, _ := m.pages.GetFrontPage()
+	m.browseFiles(title, dir, back, onSelect)
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) browseFiles(title, dir, back string, onSelect func(path string)) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		m.showError("Failed to read directory: " + err.Error())
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].IsDir() && !entries[j].IsDir()
+	})
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	list.AddItem("📁 ..", "", 0, func() {
+		m.browseFiles(title, filepath.Dir(dir), back, onSelect)
+	})
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			list.AddItem("📁 "+tview.Escape(entry.Name()), "", 0, func() {
+				m.browseFiles(title, path, back, onSelect)
+			})
+			continue
+		}
+		label := "   " + tview.Escape(entry.Name())
+		if info, err := entry.Info(); err == nil {
+			label += " [gray]" + formatSize(info.Size())
+		}
+		list.AddItem(label, "", 0, func() {
+			onSelect(path)
+		})
+	}
+
+	footer := tview.NewTextView().SetText("Enter open/select • Esc cancel")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(" " + title + " • " + tview.Escape(dir) + " ").SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, back)
+
+	m.pages.AddPage("file_picker", layout, true, true)
+	m.pages.SwitchToPage("file_picker")
+}
+
+// -----------------------------------------------------------------------
+// formatSize formats a byte count with a binary unit
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
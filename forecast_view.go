@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+
+	"github.com/rivo/tview"
+)
+
+// predictorMaxAge is how long completed histories are reused for
+// forecasts before they are fetched again
+const predictorMaxAge = 10 * time.Minute
+
+// -----------------------------------------------------------------------
+// loadPredictor returns the forecasting model, rebuilding it from the
+// completed histories when it is missing or stale. It blocks on the API.
+func (m *BPMNManager) loadPredictor() (*mining.Predictor, error) {
+	if predictor := m.cachedPredictor(); predictor != nil {
+		return predictor, nil
+	}
+	completed, err := m.apiClient.GetCompletedProcesses()
+	if err != nil {
+		return nil, err
+	}
+	predictor := mining.NewPredictor(completed, m.clock())
+
+	m.predictorMu.Lock()
+	defer m.predictorMu.Unlock()
+	m.predictor, m.predictorAt, m.forecasts = predictor, time.Now(), nil
+	return predictor, nil
+}
+
+// -----------------------------------------------------------------------
+// cachedPredictor returns the forecasting model if a fresh one is loaded
+func (m *BPMNManager) cachedPredictor() *mining.Predictor {
+	m.predictorMu.Lock()
+	defer m.predictorMu.Unlock()
+	if m.predictor == nil || time.Since(m.predictorAt) > predictorMaxAge {
+		return nil
+	}
+	return m.predictor
+}
+
+// -----------------------------------------------------------------------
+// resetPredictor drops the forecasting model, e.g. when durations are to
+// be measured by another clock
+func (m *BPMNManager) resetPredictor() {
+	m.predictorMu.Lock()
+	defer m.predictorMu.Unlock()
+	m.predictor, m.forecasts = nil, nil
+}
+
+// -----------------------------------------------------------------------
+// refreshForecasts loads the histories in the background when needed and
+// calls redraw on the UI goroutine once they arrive, with the error when
+// they could not be loaded. Callers arriving while a load runs wait for
+// that load.
+func (m *BPMNManager) refreshForecasts(redraw func(err error)) {
+	if m.cachedPredictor() != nil {
+		return
+	}
+	m.predictorMu.Lock()
+	loading := m.forecastWaiters != nil
+	m.forecastWaiters = append(m.forecastWaiters, redraw)
+	m.predictorMu.Unlock()
+	if loading {
+		return
+	}
+
+	go func() {
+		_, err := m.loadPredictor()
+		m.predictorMu.Lock()
+		waiters := m.forecastWaiters
+		m.forecastWaiters = nil
+		m.predictorMu.Unlock()
+		m.app.QueueUpdateDraw(func() {
+			for _, redraw := range waiters {
+				redraw(err)
+			}
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// processForecastRow is processRow with the forecast column filled in
+// once the histories are loaded
+func (m *BPMNManager) processForecastRow(process models.RunningProcess) []string {
+	return append(processRow(process), m.forecastText(process))
+}
+
+// -----------------------------------------------------------------------
+// forecastText is the forecast column of an instance, empty until the
+// histories are loaded. Forecasts scan every history of the process, so
+// they are computed once per predictor rather than on every redraw; the
+// remaining time is counted from now whenever the row is drawn.
+func (m *BPMNManager) forecastText(process models.RunningProcess) string {
+	predictor := m.cachedPredictor()
+	if predictor == nil {
+		return ""
+	}
+	m.predictorMu.Lock()
+	forecast, ok := m.forecasts[process.ProcessID]
+	m.predictorMu.Unlock()
+	if !ok {
+		if f, ok := predictor.PredictRunning(process, time.Now()); ok {
+			forecast = &f
+		}
+		m.predictorMu.Lock()
+		if m.predictor == predictor {
+			if m.forecasts == nil {
+				m.forecasts = map[string]*mining.Forecast{}
+			}
+			m.forecasts[process.ProcessID] = forecast
+		}
+		m.predictorMu.Unlock()
+	}
+	if forecast == nil {
+		return ""
+	}
+	return formatForecast(forecast.At(m.clock(), time.Now()))
+}
+
+// -----------------------------------------------------------------------
+// formatForecast gives the expected remaining time with its range
+func formatForecast(f mining.Forecast) string {
+	return fmt.Sprintf("~%s (%s–%s)", formatAge(f.Remaining), formatAge(f.Low), formatAge(f.High))
+}
+
+// -----------------------------------------------------------------------
+// forecastLoading stands in for the forecast of an instance in view until
+// the histories are loaded
+func forecastLoading(instanceID string) string {
+	return "[gray]Forecast of " + instanceID + " loading…\n"
+}
+
+// -----------------------------------------------------------------------
+// describeForecast explains the forecast of an instance for the details
+// panel, empty for finished instances. Until the histories are loaded it
+// is a placeholder, replaced in view once they are or once loading them
+// failed.
+func (m *BPMNManager) describeForecast(process *models.ProcessDetails, view *tview.TextView) string {
+	if process.EndTime != "" {
+		return ""
+	}
+	predictor := m.cachedPredictor()
+	if predictor == nil {
+		m.refreshForecasts(func(err error) {
+			// the view may show another instance by now
+			text := view.GetText(false)
+			if !strings.Contains(text, forecastLoading(process.ID)) {
+				return
+			}
+			var replacement string
+			if err != nil {
+				replacement = "[gray]Forecast unavailable: " + tview.Escape(err.Error()) + "\n"
+			} else {
+				replacement = m.describeForecast(process, view)
+			}
+			view.SetText(strings.Replace(text, forecastLoading(process.ID), replacement, 1))
+		})
+		return forecastLoading(process.ID)
+	}
+	f, ok := predictor.Predict(*process, time.Now())
+	if !ok {
+		return "[gray]Forecast unavailable: no completed instances of this process yet\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Forecast (%s):\n", m.timeMode())
+	fmt.Fprintf(&b, "	Remaining: %s\n", formatForecast(f))
+	fmt.Fprintf(&b, "	Expected completion: %s (between %s and %s)\n",
+		f.Completion.Format("2006-01-02 15:04"), f.Earliest.Format("2006-01-02 15:04"), f.Latest.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "	Based on %s\n", f.Basis)
+	if len(f.Steps) > 0 {
+		b.WriteString("	Dominant remaining steps:\n")
+		for i, step := range f.Steps {
+			if i == 3 {
+				break
+			}
+			fmt.Fprintf(&b, "	  • %s: ~%s, in %.0f%% of similar instances\n",
+				tview.Escape(rtl(step.Name)), formatAge(step.Mean), step.Probability*100)
+		}
+	}
+	return b.String()
+}
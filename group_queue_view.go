@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/query"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showGroupQueue lists the unassigned tasks offered to the groups of the
+// current user, who can claim them from here
+func (m *BPMNManager) showGroupQueue() {
+	cached := m.currentUser
+	go func() {
+		user := m.fetchCurrentUser(cached)
+		var tasks []models.UserTask
+		var err error
+		if user != nil && len(user.Groups) > 0 {
+			tasks, err = m.apiClient.GetGroupTasks(user.Groups)
+		}
+		m.app.QueueUpdateDraw(func() {
+			m.currentUser = user
+			switch {
+			case user == nil:
+				m.showError("Failed to load the current user")
+			case len(user.Groups) == 0:
+				m.showMessage("You are not a member of any group")
+			case err != nil:
+				m.showError("Failed to load group tasks: " + err.Error())
+			default:
+				m.pages.AddPage("group_queue", m.createGroupQueue(user, tasks), true, true)
+				m.pages.SwitchToPage("group_queue")
+			}
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createGroupQueue(user *models.User, tasks []models.UserTask) tview.Primitive {
+	// A backend that ignores the group filter of GetGroupTasks sends every
+	// task, so only the ones the user may claim are listed
+	var claimable []models.UserTask
+	perGroup := make(map[string]int)
+	for _, task := range tasks {
+		if !task.Claimable(user) {
+			continue
+		}
+		claimable = append(claimable, task)
+		for _, group := range task.CandidateGroups {
+			perGroup[group]++
+		}
+	}
+
+	table := newDataTable(m.app, "Group Queue",
+		taskColumns("id", "name", "processName", "candidateGroup", "created", "due"))
+	rows := make([][]string, len(claimable))
+	records := make([]query.Record, len(claimable))
+	for row, task := range claimable {
+		rows[row] = taskRow(task)
+		records[row] = query.TaskRecord(task)
+	}
+	table.enableQuery(query.TaskSchema, records)
+	table.setRows(rows)
+
+	table.setInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		selected := table.selectedRow()
+		if selected < 0 {
+			return event
+		}
+		task := claimable[selected]
+		switch {
+		case event.Key() == tcell.KeyEnter:
+			m.showTaskDetail(task)
+			return nil
+		case event.Rune() == 'c':
+			m.claimTask(task, user)
+			return nil
+		}
+		return event
+	})
+
+	groups := make([]string, len(user.Groups))
+	for i, group := range user.Groups {
+		groups[i] = fmt.Sprintf("%s [yellow]%d[white]", tview.Escape(group), perGroup[group])
+	}
+	summary := tview.NewTextView().SetDynamicColors(true).
+		SetText("Groups: " + strings.Join(groups, " • "))
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("c claim • Enter details • /: search • :: filter, e.g. candidateGroup = \"sales\" • Esc back")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 1, 0, false).
+		AddItem(table.layout, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	return layout
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) claimTask(task models.UserTask, user *models.User) {
+	go func() {
+		err := m.apiClient.ClaimTask(task.ID, user.ID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError(err.Error())
+				return
+			}
+			m.showGroupQueue()
+		})
+	}()
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// incident grouping modes, cycled with 'g'
+const (
+	groupByProcess = iota
+	groupByActivity
+	groupByMessage
+	groupModes
+)
+
+var groupModeNames = []string{"process", "activity", "error message"}
+
+// incidentRow is an open incident, or a failing job that still has
+// retries left and therefore has no incident yet
+type incidentRow struct {
+	incident models.Incident
+	job      *models.Job
+}
+
+// incidentGroup is a set of rows sharing the grouping key
+type incidentGroup struct {
+	key  string
+	rows []incidentRow
+}
+
+// -----------------------------------------------------------------------
+func (r incidentRow) jobID() string {
+	if r.job != nil {
+		return r.job.ID
+	}
+	if r.incident.IncidentType == "failedJob" {
+		return r.incident.Configuration
+	}
+	return ""
+}
+
+// -----------------------------------------------------------------------
+func (r incidentRow) groupKey(mode int) string {
+	switch mode {
+	case groupByActivity:
+		return r.incident.ActivityID
+	case groupByMessage:
+		message := strings.TrimSpace(r.incident.IncidentMessage)
+		if i := strings.IndexAny(message, "\r\n"); i >= 0 {
+			message = message[:i]
+		}
+		return message
+	}
+	return r.incident.ProcessDefinitionID
+}
+
+// -----------------------------------------------------------------------
+// mergeIncidents combines incidents with failing jobs that have not yet
+// raised one
+func mergeIncidents(incidents []models.Incident, jobs []models.Job) []incidentRow {
+	rows := make([]incidentRow, 0, len(incidents)+len(jobs))
+	withIncident := make(map[string]bool)
+	for _, incident := range incidents {
+		rows = append(rows, incidentRow{incident: incident})
+		if incident.IncidentType == "failedJob" {
+			withIncident[incident.Configuration] = true
+		}
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		if withIncident[job.ID] || job.Retries == 0 {
+			continue
+		}
+		rows = append(rows, incidentRow{
+			incident: models.Incident{
+				ProcessDefinitionID: job.ProcessDefinitionID,
+				ProcessInstanceID:   job.ProcessInstanceID,
+				ActivityID:          job.ActivityID,
+				IncidentType:        fmt.Sprintf("failing (%d retries left)", job.Retries),
+				IncidentMessage:     job.ExceptionMessage,
+			},
+			job: job,
+		})
+	}
+	return rows
+}
+
+// -----------------------------------------------------------------------
+// groupIncidents groups rows by the mode's key, largest groups first
+func groupIncidents(rows []incidentRow, mode int) []incidentGroup {
+	index := make(map[string]int)
+	var groups []incidentGroup
+	for _, row := range rows {
+		key := row.groupKey(mode)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, incidentGroup{key: key})
+		}
+		groups[i].rows = append(groups[i].rows, row)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].rows) > len(groups[j].rows)
+	})
+	for _, group := range groups {
+		sort.SliceStable(group.rows, func(i, j int) bool {
+			return group.rows[i].incident.IncidentTimestamp.After(group.rows[j].incident.IncidentTimestamp)
+		})
+	}
+	return groups
+}
+
+// -----------------------------------------------------------------------
+// showIncidents lists incidents and failing jobs
+func (m *BPMNManager) showIncidents() {
+	go func() {
+		incidents, err := m.apiClient.GetIncidents()
+		jobs, jobsErr := m.apiClient.GetFailedJobs()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load incidents: " + err.Error())
+				return
+			}
+			if jobsErr != nil {
+				jobs = nil
+			}
+			m.createIncidentsScreen(mergeIncidents(incidents, jobs))
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createIncidentsScreen(rows []incidentRow) {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	detail := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	detail.SetBorder(true).SetTitle(" Incident ").SetBorderColor(tcell.Color102)
+
+	mode := groupByProcess
+	// rowRefs maps table rows to a group (row == nil) or a single row
+	type rowRef struct {
+		group *incidentGroup
+		row   *incidentRow
+	}
+	var refs map[int]rowRef
+
+	render := func() {
+		table.Clear()
+		refs = make(map[int]rowRef)
+		headers := []string{"Instance", "|Activity", "|Type", "|Message", "|Since", "|Note"}
+		for i, header := range headers {
+			table.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+
+		groups := groupIncidents(rows, mode)
+		r := 1
+		for g := range groups {
+			group := &groups[g]
+			key := group.key
+			if key == "" {
+				key = "(none)"
+			}
+			table.SetCell(r, 0, tview.NewTableCell(fmt.Sprintf("▸ %s [%d]", tview.Escape(key), len(group.rows))).
+				SetTextColor(tcell.ColorOrange).
+				SetExpansion(1))
+			refs[r] = rowRef{group: group}
+			r++
+			for i := range group.rows {
+				row := &group.rows[i]
+				message := truncate(row.incident.IncidentMessage, 60)
+				note := ""
+				if row.incident.Annotation != "" {
+					note = "📝"
+				}
+				table.SetCell(r, 0, tview.NewTableCell("  "+row.incident.ProcessInstanceID))
+				table.SetCell(r, 1, tview.NewTableCell("|"+row.incident.ActivityID))
+				table.SetCell(r, 2, tview.NewTableCell("|"+row.incident.IncidentType).SetTextColor(tcell.ColorRed))
+				table.SetCell(r, 3, tview.NewTableCell("|"+tview.Escape(message)))
+				table.SetCell(r, 4, tview.NewTableCell("|"+m.formatSince(row.incident.IncidentTimestamp)))
+				table.SetCell(r, 5, tview.NewTableCell("|"+note))
+				refs[r] = rowRef{group: group, row: row}
+				r++
+			}
+		}
+		table.SetTitle(fmt.Sprintf(" Incidents (%d) • grouped by %s ", len(rows), groupModeNames[mode]))
+		if r > 1 {
+			table.Select(1, 0)
+		}
+	}
+
+	showDetail := func(row int) {
+		ref, ok := refs[row]
+		switch {
+		case !ok:
+			detail.SetText("")
+		case ref.row == nil:
+			detail.SetText(fmt.Sprintf("[yellow]%s[white]\n\n%d incidents in this group.\nPress R to retry all of them.",
+				tview.Escape(ref.group.key), len(ref.group.rows)))
+		default:
+			incident := ref.row.incident
+			detail.SetText(fmt.Sprintf(`[yellow]Incident:[white] %s
+[yellow]Type:[white] %s
+[yellow]Instance:[white] %s
+[yellow]Definition:[white] %s
+[yellow]Activity:[white] %s
+[yellow]Job:[white] %s
+[yellow]Since:[white] %s
+
+[red]%s[white]
+
+[yellow]Annotation:[white] %s`,
+				incident.ID, incident.IncidentType, incident.ProcessInstanceID, incident.ProcessDefinitionID,
+				incident.ActivityID, ref.row.jobID(), incident.IncidentTimestamp.Format("2006-01-02 15:04:05"),
+				tview.Escape(incident.IncidentMessage), tview.Escape(incident.Annotation)))
+		}
+	}
+	table.SetSelectionChangedFunc(func(row, column int) {
+		showDetail(row)
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		ref, ok := refs[row]
+		switch event.Rune() {
+		case 'g':
+			mode = (mode + 1) % groupModes
+			render()
+			return nil
+		case 'u':
+			m.showIncidents()
+			return nil
+		case 's':
+			if ok && ref.row != nil {
+				m.showJobStacktrace(ref.row.jobID())
+			}
+			return nil
+		case 'r':
+			if ok && ref.row != nil {
+				m.showRetryForm([]incidentRow{*ref.row})
+			}
+			return nil
+		case 'R':
+			if ok {
+				m.showRetryForm(ref.group.rows)
+			}
+			return nil
+		case 'n':
+			if ok && ref.row != nil && ref.row.incident.ID != "" {
+				m.showAnnotationForm(ref.row.incident)
+			}
+			return nil
+		}
+		return event
+	})
+
+	render()
+	showDetail(1)
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("g group by • s stack trace • r retry • R retry group • n annotate • u reload")
+	left := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	left.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	m.mainContent.Clear()
+	m.mainContent.AddItem(m.nav, 35, 1, true)
+	m.mainContent.AddItem(left, 0, 3, true)
+	m.mainContent.AddItem(detail, 0, 2, false)
+	m.app.SetFocus(table)
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showJobStacktrace(jobID string) {
+	if jobID == "" {
+		m.showError("This incident has no job to inspect")
+		return
+	}
+	go func() {
+		trace, err := m.apiClient.GetJobStacktrace(jobID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load stack trace: " + err.Error())
+				return
+			}
+			view := tview.NewTextView().SetText(trace).SetScrollable(true).SetWrap(false)
+			view.SetBorder(true).SetTitle(fmt.Sprintf(" Stack trace • job %s • Esc back ", jobID)).SetBorderColor(tcell.Color102)
+			m.backOnEsc(view, "main")
+			m.pages.AddPage("stacktrace", view, true, true)
+			m.pages.SwitchToPage("stacktrace")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// showRetryForm asks for a retry count and retries the jobs of rows
+func (m *BPMNManager) showRetryForm(rows []incidentRow) {
+	var jobIDs []string
+	for _, row := range rows {
+		if id := row.jobID(); id != "" {
+			jobIDs = append(jobIDs, id)
+		}
+	}
+	if len(jobIDs) == 0 {
+		m.showError("No retryable jobs selected")
+		return
+	}
+
+	form := tview.NewForm().
+		AddTextView("Jobs:", strconv.Itoa(len(jobIDs)), 10, 1, false, false).
+		AddInputField("Retries", "1", 5, tview.InputFieldInteger, nil)
+	form.AddButton("Retry", func() {
+		retries, err := strconv.Atoi(form.GetFormItem(1).(*tview.InputField).GetText())
+		if err != nil || retries < 1 {
+			m.showError("Retries must be a positive number")
+			return
+		}
+		go func() {
+			if len(jobIDs) == 1 {
+				err = m.apiClient.SetJobRetries(jobIDs[0], retries)
+			} else {
+				err = m.apiClient.SetJobsRetries(jobIDs, retries)
+			}
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError(err.Error())
+					return
+				}
+				m.showMessage(fmt.Sprintf("%d job(s) scheduled for retry", len(jobIDs)))
+				m.showIncidents()
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Retry Jobs ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("retry", form, true, true)
+	m.pages.SwitchToPage("retry")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showAnnotationForm(incident models.Incident) {
+	form := tview.NewForm().
+		AddTextView("Incident:", incident.ID, 40, 1, false, false).
+		AddTextArea("Annotation", incident.Annotation, 60, 6, 0, nil)
+	form.AddButton("Save", func() {
+		annotation := form.GetFormItem(1).(*tview.TextArea).GetText()
+		go func() {
+			err := m.apiClient.AnnotateIncident(incident.ID, annotation)
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError(err.Error())
+					return
+				}
+				m.pages.SwitchToPage("main")
+				m.showIncidents()
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Annotate Incident ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("annotate", form, true, true)
+	m.pages.SwitchToPage("annotate")
+}
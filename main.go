@@ -2,13 +2,20 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"bpmn-manager/api"
+	"bpmn-manager/calendar"
+	"bpmn-manager/mining"
 	"bpmn-manager/models"
-
-	"strings"
+	"bpmn-manager/query"
+	"bpmn-manager/sla"
+	"bpmn-manager/storage"
+	"bpmn-manager/variables"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -16,13 +23,46 @@ import (
 
 // -----------------------------------------------------------------------
 type BPMNManager struct {
-	app         *tview.Application
-	pages       *tview.Pages
-	apiClient   *api.APIClient
-	infoPanel   *tview.TextView
-	mainContent *tview.Flex
-	nav         *tview.List
-	currentPage string
+	app          *tview.Application
+	pages        *tview.Pages
+	apiClient    *api.APIClient
+	infoPanel    *tview.TextView
+	mainContent  *tview.Flex
+	nav          *tview.List
+	currentPage  string
+	currentUser  *models.User
+	storage      *storage.Storage
+	pendingView  *models.SavedView // applied by the next table it targets
+	navBase      int               // navigation items before the views section
+	navKeys      map[rune]bool     // shortcuts saved views may not take over
+	sla          *sla.Config
+	slaErr       error // why the SLA targets could not be loaded
+	calendar     *calendar.Calendar
+	calendarErr  error // why the working calendar could not be loaded
+	editors      *variables.Editors
+	editorsErr   error // why the variable editor groups could not be loaded
+	businessTime bool  // measure durations in working hours
+	predictor    *mining.Predictor
+	predictorAt  time.Time // when the predictor's histories were loaded
+	predictorMu  sync.Mutex
+	// forecasts caches the forecast of each instance id until the
+	// predictor is rebuilt; nil when there is none
+	forecasts map[string]*mining.Forecast
+	// forecastWaiters are redrawn once loading the histories finishes;
+	// nil while no load is running
+	forecastWaiters []func(err error)
+	// smallLists names the lists found small enough to load whole
+	smallLists map[string]bool
+	// anomalyScan is the last successful detection and anomalyWaiters
+	// get the one being run, nil while none is; both belong to the UI
+	// goroutine
+	anomalyScan    *anomalyScan
+	anomalyWaiters []func(scan *anomalyScan)
+	// attentionPanel lists running instances the anomaly detector flagged
+	attentionPanel  *tview.TextView
+	dashboard       *dashboardPanels
+	dashboardData   *dashboardData // last data the dashboard was drawn from
+	dashboardWindow int            // index into dashboardWindows
 	// contentView *tview.TextView // Add the contentView field here
 
 	baseURL string
@@ -34,8 +74,12 @@ func NewBPMNManager(baseURL string) *BPMNManager {
 		app:       tview.NewApplication(),
 		pages:     tview.NewPages(),
 		apiClient: api.NewAPIClient(baseURL),
+		storage:   storage.NewStorage(viewsDir()),
 		baseURL:   baseURL,
 	}
+	manager.sla, manager.slaErr = loadSLA()
+	manager.calendar, manager.calendarErr = loadCalendar()
+	manager.editors, manager.editorsErr = variables.LoadEditors(variablesConfigPath())
 	// Set up proper encoding for Persian/Arabic text
 	manager.setupEncoding()
 
@@ -67,6 +111,17 @@ func containsPersian(text string) bool {
 	return false
 }
 
+// -----------------------------------------------------------------------
+// truncate shortens text to width characters, counting runes so Persian
+// text is never cut inside a character
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
 // -----------------------------------------------------------------------
 func (m *BPMNManager) setupUI() {
 
@@ -119,6 +174,10 @@ func (m *BPMNManager) createMainMenu() tview.Primitive {
 		SetWordWrap(true)
 	// contentView.SetText(m.formatRTLText(welcomeText))
 	m.infoPanel.SetBorder(true).SetTitle(" Dashboard ").SetBorderColor(tcell.Color102)
+	m.attentionPanel = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	m.dashboard = newDashboardPanels(m.attentionPanel)
 
 	m.updateDashboardPanel()
 
@@ -128,7 +187,7 @@ func (m *BPMNManager) createMainMenu() tview.Primitive {
 	// Footer
 	footer := tview.NewTextView().
 		SetTextAlign(tview.AlignCenter).
-		SetText("Press F5 to refresh • Tab to navigate • Ctrl+C to exit")
+		SetText("Press F5 to refresh • F7 dashboard window • Tab to navigate • Ctrl+C to exit")
 	footer.SetBorder(false)
 
 	flex.AddItem(header, 3, 1, false)
@@ -141,37 +200,78 @@ func (m *BPMNManager) createMainMenu() tview.Primitive {
 // -----------------------------------------------------------------------
 
 func (m *BPMNManager) createNavigationPanel() *tview.List {
-	nav := tview.NewList().
-		AddItem("👤 My Tasks", "View assigned tasks", 't', func() {
+	items := []struct {
+		text, secondary string
+		shortcut        rune
+		selected        func()
+	}{
+		{"👤 My Tasks", "View assigned tasks", 't', func() {
 			// m.showUserTasks()
 			m.showDashboard()
-		}).
-		AddItem("🔄 Running Processes", "View active processes", 'r', func() {
+		}},
+		{"🔄 Running Processes", "View active processes", 'r', func() {
 			// m.showRunningProcesses()
 			m.createRunningProcesses()
-		}).
-		AddItem("📊 Completed tasks", "View completed tasks", 'c', func() {
+		}},
+		{"📊 Completed tasks", "View completed tasks", 'c', func() {
 			m.showCompletedTaskDetails()
-		}).
-		AddItem("🔎 Find Process ", "Find process by id", 'f', func() {
+		}},
+		{"🔎 Find Process ", "Find process by id", 'f', func() {
 			m.showProcessSearch()
-		}).
-		AddItem("🚀 Start Process Instance ", "Launch process instance", 'l', func() {
+		}},
+		{"🚀 Start Process Instance ", "Launch process instance", 'l', func() {
 			m.apiClient.StartProcess()
 			m.showMessage("New process instance started!")
-		}).
-		AddItem("📊 Process Details", "View process information", 'd', func() {
+		}},
+		{"📊 Process Details", "View process information", 'd', func() {
 			m.showProcessSelection()
-		}).
-		AddItem("🔄 Refresh Data", "Reload all data", 'f', func() {
+		}},
+		{"🚨 Incidents", "Failed jobs and incidents", 'i', func() {
+			m.showIncidents()
+		}},
+		{"🔀 Migrate Instances", "Move instances to another version", 'm', func() {
+			m.showMigration()
+		}},
+		{"👥 Group Queue", "Claim tasks offered to your groups", 'g', func() {
+			m.showGroupQueue()
+		}},
+		{"⚖️ Workload", "Open tasks per assignee", 'k', func() {
+			m.showWorkload()
+		}},
+		{"🧭 Variants", "Paths taken by completed instances", 'v', func() {
+			m.showVariants()
+		}},
+		{"🐢 Bottlenecks", "Slowest activities and waiting times", 'b', func() {
+			m.showBottlenecks()
+		}},
+		{"✅ Conformance", "Instances that deviated from the model", 'o', func() {
+			m.showConformance()
+		}},
+		{"📤 Event Log", "Export histories as XES or CSV", 'x', func() {
+			m.showEventLogExport()
+		}},
+		{"⚠️ Needs Attention", "Stuck and abnormal running instances", 'a', func() {
+			m.showAnomalies()
+		}},
+		{"🔄 Refresh Data", "Reload all data", 'f', func() {
 			m.updateDashboardPanel()
-		}).
-		AddItem("⚙️ Settings", "Configure connection", 's', func() {
+		}},
+		{"⚙️ Settings", "Configure connection", 's', func() {
 			m.showSettings()
-		}).
-		AddItem("❌ Quit", "Exit application", 'q', func() {
+		}},
+		{"❌ Quit", "Exit application", 'q', func() {
 			m.app.Stop()
-		})
+		}},
+	}
+
+	nav := tview.NewList()
+	m.navKeys = map[rune]bool{viewsShortcut: true}
+	for _, item := range items {
+		nav.AddItem(item.text, item.secondary, item.shortcut, item.selected)
+		m.navKeys[item.shortcut] = true
+	}
+	m.navBase = nav.GetItemCount()
+	m.refreshViewNav(nav)
 	nav.SetBorder(true).SetTitle(" Navigation ").SetBorderColor(tcell.Color102)
 	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen)
 	nav.SetSelectedStyle(selectedStyle)
@@ -179,41 +279,6 @@ func (m *BPMNManager) createNavigationPanel() *tview.List {
 	return nav
 }
 
-// -----------------------------------------------------------------------
-
-func (m *BPMNManager) updateDashboardPanel() {
-
-	tasks, _ := m.apiClient.GetUserTasks()
-	completedTasks, _ := m.apiClient.GetCompletedTasks()
-	processes, _ := m.apiClient.GetRunningProcesses()
-	completedProcesses, _ := m.apiClient.GetCompletedProcesses()
-
-	detailsText := fmt.Sprintf(`
-
- 🎯 Welcome to BPMN Manager!
-  [yellow]---------------------------------	
- [yellow] 📊  Total Running Tasks: %d   
-  📊  Total Completed Tasks: %d 
-  ---------------------------------
-[yellow]  📊  Total Running Processes: %d   
-  📊  Total Completed Processes: %d 
- ----------------------------------[white] 
- 
-  Use the navigation menu to:
-  • View your assigned tasks
-  • View completed tasks
-  • Monitor running processes
-  • Check process details
-  
-  Press F5 to refresh data`, len(tasks), len(completedTasks), len(processes), len(completedProcesses))
-
-	m.infoPanel.SetText(detailsText)
-	m.mainContent.Clear()
-	m.mainContent.AddItem(m.nav, 35, 1, true)
-	m.mainContent.AddItem(m.infoPanel, 0, 3, false)
-	m.app.SetFocus(m.nav)
-}
-
 // -----------------------------------------------------------------------
 func (m *BPMNManager) showDashboard() {
 	// Create a simple dashboard view
@@ -325,7 +390,7 @@ func (m *BPMNManager) createTaskDetails(tasks []models.UserTask) *tview.TextView
 }
 
 // -----------------------------------------------------------------------
-func (m *BPMNManager) createProcessDetailsPanel(processes []models.RunningProcess) *tview.TextView {
+func (m *BPMNManager) createProcessDetailsPanel(running int) *tview.TextView {
 
 	taskDetails := tview.NewTextView().
 		SetDynamicColors(true).
@@ -336,7 +401,7 @@ func (m *BPMNManager) createProcessDetailsPanel(processes []models.RunningProces
 
 	detailsText := fmt.Sprintf(`Task Summary:
 [yellow]  📊  Total Running Processes: %d   
-  📊  Total Completed Processes: %d [white] `, len(processes), len(completedTasks))
+  📊  Total Completed Processes: %d [white] `, running, len(completedTasks))
 
 	taskDetails.SetBorder(true).SetBorderColor(tcell.Color102)
 	taskDetails.SetText(detailsText)
@@ -360,7 +425,6 @@ func (m *BPMNManager) CreateModalForTaskCompletion(taskId string) tview.Primitiv
 				data := models.FormData{
 					ReDefineDecision: false,
 					DbDecision:       false,
-					Comment:          "comment",
 				}
 
 				err := m.apiClient.CompleteTask(taskId, data)
@@ -407,7 +471,9 @@ func (m *BPMNManager) CreateModalForTaskCompletion(taskId string) tview.Primitiv
 }
 
 // -----------------------------------------------------------------------
-func (m *BPMNManager) createProcessDetails(processId string) string {
+// createProcessDetails describes an instance for view, which receives the
+// forecast once it is loaded
+func (m *BPMNManager) createProcessDetails(processId string, view *tview.TextView) string {
 
 	process, err := m.apiClient.GetProcessDetails(processId)
 
@@ -435,18 +501,15 @@ func (m *BPMNManager) createProcessDetails(processId string) string {
 		process.ProcessDefinitionKey,
 		process.StartTime,
 		process.EndTime,
-		formatDuration(process.Duration/1000),
+		m.formatElapsed(parseEngineTime(process.StartTime), parseEngineTime(process.EndTime), process.Duration/1000),
 	)
 
-	detailsText += "[violet]CurrentVariables:\n"
-	for key, variable := range process.CurrentVariables {
-		switch v := variable.(type) {
-		case string:
-			detailsText += fmt.Sprintf("	[violet]%s:[violet] %s\n", key, v)
-		case bool:
-			detailsText += fmt.Sprintf("	[violet]%s:[violet] %t\n", key, v)
+	detailsText += m.describeForecast(process, view)
 
-		}
+	detailsText += "[violet]CurrentVariables:\n"
+	for _, variable := range variables.FromMap(process.CurrentVariables) {
+		detailsText += fmt.Sprintf("	[violet]%s:[violet] %s [gray](%s)[violet]\n",
+			variable.Name, tview.Escape(variables.Summary(variable.Value, 60)), variable.Type)
 	}
 
 	detailsText += "[orange]Activities:"
@@ -470,7 +533,7 @@ func (m *BPMNManager) createProcessDetails(processId string) string {
 			activity.Assignee,
 			activity.StartTime.Format("2006-01-02 15:04:05"),
 			formatEndTime(activity.EndTime), //.Format("2025-01-02 15:04:05"),
-			formatDuration(activity.Duration/1000),
+			m.formatElapsed(activity.StartTime, activity.EndTime, activity.Duration/1000),
 		)
 	}
 
@@ -548,42 +611,26 @@ func reverseString(s string) string {
 func (m *BPMNManager) showCompletedTaskDetails() {
 
 	// Create tasks table
-	table := tview.NewTable().
-		SetBorders(false).
-		SetFixed(1, 0).
-		SetSelectable(true, false).SetBordersColor(tcell.Color100)
-
-	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen)
-	table.SetSelectedStyle(selectedStyle)
-
-	// Headers
-	headers := []string{"TaskID|", "TaskName", "|ProcessID", "|Assignee"}
-	for i, header := range headers {
-		table.SetCell(0, i,
-			tview.NewTableCell(header).
-				SetTextColor(tcell.ColorYellow).
-				SetAlign(tview.AlignCenter).
-				SetSelectable(false))
-	}
+	tasksTable := newDataTable(m.app, "Completed Tasks List",
+		taskColumns("id", "name", "processInstanceId", "assignee"))
 
 	completedTasks, _ := m.apiClient.GetCompletedTasks()
 	// Add data to table
+	rows := make([][]string, len(completedTasks))
+	records := make([]query.Record, len(completedTasks))
 	for row, task := range completedTasks {
-		table.SetCell(row+1, 0, tview.NewTableCell(task.ID+" |"))
-		table.SetCell(row+1, 1, tview.NewTableCell(reverseString(task.Name)).SetAlign(tview.AlignRight))
-		table.SetCell(row+1, 2, tview.NewTableCell("|"+task.ProcessID))
-		statusCell := tview.NewTableCell("|" + task.Assignee)
-		statusCell.SetTextColor(tcell.ColorYellow)
-		table.SetCell(row+1, 3, statusCell)
+		rows[row] = taskRow(task)
+		records[row] = query.TaskRecord(task)
 	}
-
-	table.SetBorder(true).SetBorderColor(tcell.Color102)
-	table.SetTitle(" Completed Tasks List")
+	tasksTable.enableQuery(query.TaskSchema, records)
+	tasksTable.setRows(rows)
+	m.attachViews(tasksTable, viewCompleted)
+	table := tasksTable.layout
 
 	m.mainContent.Clear()
 	m.mainContent.AddItem(m.nav, 35, 1, true)
 	m.mainContent.AddItem(table, 0, 3, true)
-	m.app.SetFocus(table)
+	m.app.SetFocus(tasksTable.table)
 
 	// leftPanel := m.createUserTasksTable()
 	// m.mainContent.AddItem(leftPanel, 70, 1, true)
@@ -593,164 +640,202 @@ func (m *BPMNManager) showCompletedTaskDetails() {
 }
 
 // -----------------------------------------------------------------------
-func (m *BPMNManager) createUserTasksTable() *tview.Flex {
-
-	// Create tasks table
-	table := tview.NewTable().
-		SetBorders(false).
-		SetFixed(1, 0).
-		SetSelectable(true, false).SetBordersColor(tcell.Color100)
+func (m *BPMNManager) createUserTasksTable() tview.Primitive {
 
-	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen)
-	table.SetSelectedStyle(selectedStyle)
-
-	// Headers
-	headers := []string{"TaskID|", "TaskName", "|TaskDefinitionKey", "|ProcessID", "|Assignee"}
-	for i, header := range headers {
-		table.SetCell(0, i,
-			tview.NewTableCell(header).
-				SetTextColor(tcell.ColorYellow).
-				SetAlign(tview.AlignCenter).
-				SetSelectable(false))
+	// Large lists are browsed page by page
+	load, _, err := openLargeList(m, viewTasks, func(params url.Values, offset, limit int) ([]models.UserTask, int, error) {
+		page, err := m.apiClient.GetUserTasksPage(params, offset, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Items, page.Total, nil
+	})
+	if err != nil {
+		m.showError("Failed to load user tasks: " + err.Error())
 	}
+	if load != nil {
+		return m.createPagedUserTasks(load)
+	}
+
+	// Create tasks table
+	tasksTable := newDataTable(m.app, "Task List",
+		taskColumns("id", "name", "taskDefinitionKey", "processInstanceId", "assignee", "due", "sla"))
 
 	tasks, _ := m.apiClient.GetUserTasks()
 
 	// Add data to table
+	rows := make([][]string, len(tasks))
+	colors := make([]tcell.Color, len(tasks))
+	records := make([]query.Record, len(tasks))
 	for row, task := range tasks {
-		table.SetCell(row+1, 0, tview.NewTableCell(task.ID+" |"))
-		table.SetCell(row+1, 1, tview.NewTableCell(reverseString(task.Name)).SetAlign(tview.AlignRight))
-		table.SetCell(row+1, 2, tview.NewTableCell("|"+task.TaskDefinitionKey))
-		table.SetCell(row+1, 3, tview.NewTableCell("|"+task.ProcessID))
-		statusCell := tview.NewTableCell("|" + task.Assignee)
-		statusCell.SetTextColor(tcell.ColorYellow)
-		table.SetCell(row+1, 4, statusCell)
+		rows[row] = m.taskSLARow(task)
+		colors[row] = m.taskSLAColor(task)
+		records[row] = query.TaskRecord(task)
 	}
+	tasksTable.enableQuery(query.TaskSchema, records)
+	tasksTable.setRows(rows)
+	tasksTable.setRowColors(colors)
+	m.attachViews(tasksTable, viewTasks)
+
+	tasksTable.setInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		selected := tasksTable.selectedRow()
+		if selected < 0 {
+			return event
+		}
+		return m.handleTaskKey(tasks[selected], event)
+	})
 
-	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyF2:
-			selectedRow, _ := table.GetSelection()
-			taskId := table.GetCell(selectedRow, 0).Text
-			m.CreateModalForTaskCompletion(taskId)
-			// m.mainContent.AddItem(m.CreateModalForTaskCompletion(taskId), 1, 0, false)
-			return nil
-		case tcell.KeyEnter:
-
-			selectedRow, _ := table.GetSelection()
-			taskId := strings.ReplaceAll(table.GetCell(selectedRow, 0).Text, "|", "")
-			taskId = strings.TrimSpace(taskId)
-
-			taskKey := strings.ReplaceAll(table.GetCell(selectedRow, 2).Text, "|", "")
-			taskKey = strings.TrimSpace(taskKey)
-
-			processId := strings.ReplaceAll(table.GetCell(selectedRow, 3).Text, "|", "")
-			processId = strings.TrimSpace(processId)
-
-			reDefineDecision := tview.NewCheckbox().
-				SetLabel("ReDefineDecision: ").SetChecked(false)
-			// reDefineDecision.SetBorderPadding(1, 1, 1, 1)
-
-			dbDecision := tview.NewCheckbox().
-				SetLabel("DbDecision: ").SetChecked(false)
-			// dbDecision.SetBorderPadding(1, 1, 1, 1)
-
-			businessApproved := tview.NewCheckbox().
-				SetLabel("BusinessApproved: ").SetChecked(false)
-
-			technicalApproved := tview.NewCheckbox().
-				SetLabel("TechnicalApproved: ").SetChecked(false)
-
-			operationApproved := tview.NewCheckbox().
-				SetLabel("OperationApproved: ").SetChecked(false)
-
-			form := tview.NewForm().
-				AddTextView("Task Id:", taskId, 10, 1, true, false).
-				AddTextView("Task Definition Key:", taskKey, 30, 1, true, false).
-				AddTextView("Process Id:", processId, 10, 1, true, false).
-
-				// AddInputField("Name", "", 20, nil, nil).
-				// AddInputField("ReDefineDecision", "", 10, nil, nil).
-				// AddInputField("DbDecision", "", 10, nil, nil).
-				// AddCheckbox("ReDefineDecision", false, nil).
-				// AddCheckbox("DbDecision", false, nil).
-				// AddTextArea("Task Description", "", 20, 8, 30, nil).
-				AddButton("Complete Task", func() {
-
-					data := models.FormData{
-						ReDefineDecision:  reDefineDecision.IsChecked(),
-						DbDecision:        dbDecision.IsChecked(),
-						BusinessApproved:  businessApproved.IsChecked(),
-						TechnicalApproved: technicalApproved.IsChecked(),
-						OperationApproved: operationApproved.IsChecked(),
-						Comment:           "test comment",
-						Message:           "Comeleted by BPMN-MANAGER",
-					}
-					err := m.apiClient.CompleteTask(taskId, data)
-					if err != nil {
-						m.infoPanel.SetText(err.Error())
-					} else {
-						lastItem := m.mainContent.GetItem(2)
-						m.mainContent.RemoveItem(lastItem)
-						m.app.SetFocus(m.mainContent.GetItem(0))
-						// m.showMessage("Task Successfully Completed !")
-						m.mainContent.AddItem(m.infoPanel, 0, 1, true)
-						m.showDashboard()
-					}
+	return tasksTable.layout
 
-					// m.pages.SwitchToPage("main")
+}
 
-					// Simulate completing the task by stopping the app
-					// Normally, this would trigger a BPMN event like moving to the next task or workflow step
-				})
-			// SetButtonsAlign(tview.AlignCenter).
-
-			switch taskKey {
-			case "Activity_0ol9pgw":
-				form.AddFormItem(reDefineDecision).
-					AddFormItem(dbDecision)
-			case "Activity_0bowttv":
-				form.AddFormItem(businessApproved)
-			case "Activity_06k5ayj":
-				form.AddFormItem(technicalApproved)
-			case "Activity_018w7i0":
-				form.AddFormItem(operationApproved)
-			}
+// -----------------------------------------------------------------------
+// createPagedUserTasks shows the user tasks as a virtual table that
+// fetches pages from the engine while scrolling
+func (m *BPMNManager) createPagedUserTasks(load pageLoader[models.UserTask]) tview.Primitive {
+	tasksTable := newPagedTable(m.app, "Task List",
+		taskColumns("id", "name", "taskDefinitionKey", "processInstanceId", "assignee", "due", "sla"), query.TaskSchema, m.taskSLARow,
+		load)
+	tasksTable.color = m.taskSLAColor
+	tasksTable.setInputCapture(m.handleTaskKey)
+	if m.pendingView != nil && m.pendingView.Target == viewTasks {
+		tasksTable.filter.SetText(m.pendingView.Filter)
+		tasksTable.setQuery(m.pendingView.Filter)
+		m.pendingView = nil
+	}
+	return tasksTable.layout
+}
 
-			form.SetBorder(true).SetBorderColor(tcell.Color102)
-			form.SetTitle(" User Task Form ")
-			// Create a status message to show task completion (could simulate a BPMN task state)
-			// statusMessage := tview.NewTextView().
-			// 	SetText("Task not completed yet. Fill the form to complete it.").
-			// 	SetTextAlign(tview.AlignCenter).
-			// 	SetDynamicColors(true).
-			// 	SetBorder(true).
-			// 	SetTitle("Status")
-
-			// Create a flex layout to arrange the form and status message
-			layout := tview.NewFlex().
-				SetDirection(tview.FlexRow).
-				// AddItem(statusMessage, 0, 1, false).
-				AddItem(form, 0, 3, true)
-
-			// m.mainContent.Clear()
-			m.mainContent.RemoveItem(m.infoPanel)
-			// m.mainContent.AddItem(m.nav, 35, 1, true)
-			m.mainContent.AddItem(layout, 0, 1, true)
-			m.app.SetFocus(form)
+// -----------------------------------------------------------------------
+// handleTaskKey runs the action bound to a key on a selected task
+func (m *BPMNManager) handleTaskKey(task models.UserTask, event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyF2:
+		m.CreateModalForTaskCompletion(task.ID)
+		// m.mainContent.AddItem(m.CreateModalForTaskCompletion(taskId), 1, 0, false)
+		return nil
+	case tcell.KeyEnter:
+		m.showTaskDetail(task)
+		return nil
+	case tcell.KeyRune:
+		if event.Rune() == 'c' {
+			m.showCompletionForm(task)
+			return nil
 		}
-		return event
-	})
+	}
+	return event
+}
 
-	// Set up the layout: add the box containing the table to the app
-	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(table, 0, 2, true)
+// -----------------------------------------------------------------------
+// showCompletionForm replaces the side panel with the completion form of
+// a task
+func (m *BPMNManager) showCompletionForm(task models.UserTask) {
+	m.pages.SwitchToPage("main")
 
-	flex.SetTitle("Task List")
-	flex.SetBorder(true).SetBorderColor(tcell.Color102)
+	taskId := task.ID
+	taskKey := task.TaskDefinitionKey
+	processId := task.ProcessID
 
-	return flex
+	reDefineDecision := tview.NewCheckbox().
+		SetLabel("ReDefineDecision: ").SetChecked(false)
+	// reDefineDecision.SetBorderPadding(1, 1, 1, 1)
+
+	dbDecision := tview.NewCheckbox().
+		SetLabel("DbDecision: ").SetChecked(false)
+	// dbDecision.SetBorderPadding(1, 1, 1, 1)
+
+	businessApproved := tview.NewCheckbox().
+		SetLabel("BusinessApproved: ").SetChecked(false)
 
+	technicalApproved := tview.NewCheckbox().
+		SetLabel("TechnicalApproved: ").SetChecked(false)
+
+	operationApproved := tview.NewCheckbox().
+		SetLabel("OperationApproved: ").SetChecked(false)
+
+	comment := tview.NewTextArea().
+		SetLabel("Comment: ").SetSize(5, 0).
+		SetPlaceholder("Optional, saved in the task's comment thread")
+
+	message := tview.NewInputField().
+		SetLabel("Message: ").SetText("Completed by BPMN-MANAGER")
+
+	form := tview.NewForm().
+		AddTextView("Task Id:", taskId, 10, 1, true, false).
+		AddTextView("Task Definition Key:", taskKey, 30, 1, true, false).
+		AddTextView("Process Id:", processId, 10, 1, true, false).
+
+		// AddInputField("Name", "", 20, nil, nil).
+		// AddInputField("ReDefineDecision", "", 10, nil, nil).
+		// AddInputField("DbDecision", "", 10, nil, nil).
+		// AddCheckbox("ReDefineDecision", false, nil).
+		// AddCheckbox("DbDecision", false, nil).
+		// AddTextArea("Task Description", "", 20, 8, 30, nil).
+		AddButton("Complete Task", func() {
+
+			data := models.FormData{
+				ReDefineDecision:  reDefineDecision.IsChecked(),
+				DbDecision:        dbDecision.IsChecked(),
+				BusinessApproved:  businessApproved.IsChecked(),
+				TechnicalApproved: technicalApproved.IsChecked(),
+				OperationApproved: operationApproved.IsChecked(),
+				Comment:           strings.TrimSpace(comment.GetText()),
+				Message:           strings.TrimSpace(message.GetText()),
+			}
+			err := m.apiClient.CompleteTask(taskId, data)
+			if err != nil {
+				m.infoPanel.SetText(err.Error())
+			} else {
+				lastItem := m.mainContent.GetItem(2)
+				m.mainContent.RemoveItem(lastItem)
+				m.app.SetFocus(m.mainContent.GetItem(0))
+				// m.showMessage("Task Successfully Completed !")
+				m.mainContent.AddItem(m.infoPanel, 0, 1, true)
+				m.showDashboard()
+			}
+
+			// m.pages.SwitchToPage("main")
+
+			// Simulate completing the task by stopping the app
+			// Normally, this would trigger a BPMN event like moving to the next task or workflow step
+		})
+	// SetButtonsAlign(tview.AlignCenter).
+
+	switch taskKey {
+	case "Activity_0ol9pgw":
+		form.AddFormItem(reDefineDecision).
+			AddFormItem(dbDecision)
+	case "Activity_0bowttv":
+		form.AddFormItem(businessApproved)
+	case "Activity_06k5ayj":
+		form.AddFormItem(technicalApproved)
+	case "Activity_018w7i0":
+		form.AddFormItem(operationApproved)
+	}
+	form.AddFormItem(comment).
+		AddFormItem(message)
+
+	form.SetBorder(true).SetBorderColor(tcell.Color102)
+	form.SetTitle(" User Task Form ")
+	// Create a status message to show task completion (could simulate a BPMN task state)
+	// statusMessage := tview.NewTextView().
+	// 	SetText("Task not completed yet. Fill the form to complete it.").
+	// 	SetTextAlign(tview.AlignCenter).
+	// 	SetDynamicColors(true).
+	// 	SetBorder(true).
+	// 	SetTitle("Status")
+
+	// Create a flex layout to arrange the form and status message
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		// AddItem(statusMessage, 0, 1, false).
+		AddItem(form, 0, 3, true)
+
+	// m.mainContent.Clear()
+	m.mainContent.RemoveItem(m.infoPanel)
+	// m.mainContent.AddItem(m.nav, 35, 1, true)
+	m.mainContent.AddItem(layout, 0, 1, true)
+	m.app.SetFocus(form)
 }
 
 // -----------------------------------------------------------------------
@@ -835,52 +920,53 @@ func (m *BPMNManager) showUserTasks() {
 // -----------------------------------------------------------------------
 func (m *BPMNManager) createRunningProcesses() {
 
-	// Create tasks table
-	table := tview.NewTable().
-		SetBorders(false).
-		SetFixed(1, 0).
-		SetSelectable(true, false).SetBordersColor(tcell.Color100)
-
-	selectedStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen)
-	table.SetSelectedStyle(selectedStyle)
-
-	// Headers
-	headers := []string{"ProcessID", "| ProcessStatus", "| ProcessDefKey", "| StartTime"}
-	for i, header := range headers {
-		table.SetCell(0, i,
-			tview.NewTableCell(header).
-				SetTextColor(tcell.ColorYellow).
-				SetAlign(tview.AlignLeft).
-				SetSelectable(false))
+	// Large lists are browsed page by page
+	load, total, err := openLargeList(m, viewInstances, func(params url.Values, offset, limit int) ([]models.RunningProcess, int, error) {
+		page, err := m.apiClient.GetRunningProcessesPage(params, offset, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Items, page.Total, nil
+	})
+	if err != nil {
+		m.showError("Failed to load running processes: " + err.Error())
 	}
+	if load != nil {
+		m.createPagedProcesses(load, total)
+		return
+	}
+
+	// Create tasks table
+	processTable := newDataTable(m.app, "Process List",
+		processColumns("id", "status", "processDefinitionKey", "started", "forecast"))
 
 	processes, _ := m.apiClient.GetRunningProcesses()
 
 	// Add data to table
+	rows := func() [][]string {
+		rows := make([][]string, len(processes))
+		for row, process := range processes {
+			rows[row] = m.processForecastRow(process)
+		}
+		return rows
+	}
+	records := make([]query.Record, len(processes))
 	for row, process := range processes {
-		table.SetCell(row+1, 0, tview.NewTableCell(process.ProcessID))
-		table.SetCell(row+1, 1, tview.NewTableCell("| "+process.Status))
-		table.SetCell(row+1, 2, tview.NewTableCell("| "+process.ProcessDefinitionKey))
-		table.SetCell(row+1, 3, tview.NewTableCell("| "+process.StartTime.Format("2006-01-02 15:04:05")))
+		records[row] = query.ProcessRecord(process)
 	}
+	processTable.enableQuery(query.ProcessSchema, records)
+	processTable.setRows(rows())
+	m.refreshForecasts(func(err error) {
+		processTable.updateRows(rows())
+	})
+	m.attachViews(processTable, viewInstances)
 
-	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEnter:
-
-			selectedRow, _ := table.GetSelection()
-			// m.infoPanel.SetText(strconv.Itoa(selectedRow))
-			m.infoPanel.SetText(table.GetCell(selectedRow, 0).Text)
-			selectedId := table.GetCell(selectedRow, 0).Text
-			details := m.createProcessDetails(selectedId)
-			// m.infoPanel.SetBackgroundColor(0x005F87)
-			m.infoPanel.SetBackgroundColor(tcell.ColorDarkGreen)
-			m.infoPanel.SetDynamicColors(true)
-			m.infoPanel.SetTitle("Process Details")
-			m.infoPanel.SetText(details)
-
+	processTable.setInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		selected := processTable.selectedRow()
+		if selected < 0 {
+			return event
 		}
-		return event
+		return m.handleProcessKey(processes[selected].ProcessID, event)
 	})
 
 	totalText := tview.NewTextView().
@@ -888,25 +974,97 @@ func (m *BPMNManager) createRunningProcesses() {
 		SetRegions(true).
 		SetWordWrap(true)
 
-	totalText.SetText(fmt.Sprintf("[orange]Total Proceses: %d", len(processes)))
+	totalText.SetText(fmt.Sprintf("[orange]Total Proceses: %d   [gray]/: search • :: filter • n/N: next/prev match • s/+: sort • [/]: column • </>: width • C: columns • S: save view • %s", len(processes), processKeysHint))
+
+	m.showProcessList(processTable.layout, totalText, len(processes))
+}
+
+// processKeysHint lists the keys handled by handleProcessKey
+const processKeysHint = "Enter: details • v: variables • h: variable history • m: send message/signal • o: modify tokens • t: timeline • e: export diagram • c: comments"
+
+// -----------------------------------------------------------------------
+// createPagedProcesses shows the running instances as a virtual table
+// that fetches pages from the engine while scrolling
+func (m *BPMNManager) createPagedProcesses(load pageLoader[models.RunningProcess], total int) {
+	processTable := newPagedTable(m.app, "Process List",
+		processColumns("id", "status", "processDefinitionKey", "started", "forecast"), query.ProcessSchema, m.processForecastRow,
+		load)
+	processTable.setInputCapture(func(process models.RunningProcess, event *tcell.EventKey) *tcell.EventKey {
+		return m.handleProcessKey(process.ProcessID, event)
+	})
+	m.refreshForecasts(func(err error) {})
+	if m.pendingView != nil && m.pendingView.Target == viewInstances {
+		processTable.filter.SetText(m.pendingView.Filter)
+		processTable.setQuery(m.pendingView.Filter)
+		m.pendingView = nil
+	}
+
+	totalText := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWordWrap(true)
+
+	totalText.SetText("[orange]Large result set, loaded page by page   [gray]:: server filter • g: go to page • s: sort • [/]: column • " + processKeysHint)
+
+	m.showProcessList(processTable.layout, totalText, total)
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showProcessList(table, footer tview.Primitive, running int) {
 	// Set up the layout: add the box containing the table to the app
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(table, 0, 22, true).
-		AddItem(totalText, 0, 1, true)
-
-	flex.SetTitle(" Process List ")
-	flex.SetBorder(true).SetBorderColor(tcell.Color102)
+		AddItem(footer, 0, 1, true)
 
 	m.mainContent.Clear()
 	m.mainContent.AddItem(m.nav, 35, 1, true)
 	m.mainContent.AddItem(flex, 0, 3, true)
 	m.app.SetFocus(flex)
 
-	m.infoPanel = m.createProcessDetailsPanel(processes)
+	m.infoPanel = m.createProcessDetailsPanel(running)
 	m.mainContent.AddItem(m.infoPanel, 0, 3, true)
 
 }
 
+// -----------------------------------------------------------------------
+// handleProcessKey runs the action bound to a key on a selected process
+func (m *BPMNManager) handleProcessKey(selectedId string, event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEnter:
+
+		// m.infoPanel.SetText(strconv.Itoa(selectedRow))
+		m.infoPanel.SetText(selectedId)
+		details := m.createProcessDetails(selectedId, m.infoPanel)
+		// m.infoPanel.SetBackgroundColor(0x005F87)
+		m.infoPanel.SetBackgroundColor(tcell.ColorDarkGreen)
+		m.infoPanel.SetDynamicColors(true)
+		m.infoPanel.SetTitle("Process Details")
+		m.infoPanel.SetText(details)
+
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'v':
+			m.showVariables(selectedId)
+		case 'h':
+			m.showVariableHistory(selectedId)
+		case 'm':
+			m.showCorrelation(selectedId)
+		case 'o':
+			m.showModification(selectedId)
+		case 't':
+			m.showTimeline(selectedId)
+		case 'e':
+			m.showExportForm(selectedId)
+		case 'c':
+			m.showInstanceComments(selectedId)
+		default:
+			return event
+		}
+		return nil
+	}
+	return event
+}
+
 // -----------------------------------------------------------------------
 func (m *BPMNManager) showRunningProcesses() {
 	modal := tview.NewModal().
@@ -974,6 +1132,22 @@ func (m *BPMNManager) showProcessSearch() {
 		//form.SetFocus(0)
 
 	}).
+		AddButton("Variables", func() {
+			processID := form.GetFormItem(0).(*tview.InputField).GetText()
+			m.showVariables(processID)
+		}).
+		AddButton("Timeline", func() {
+			processID := form.GetFormItem(0).(*tview.InputField).GetText()
+			m.showTimeline(processID)
+		}).
+		AddButton("Export", func() {
+			processID := form.GetFormItem(0).(*tview.InputField).GetText()
+			m.showExportForm(processID)
+		}).
+		AddButton("Modify", func() {
+			processID := form.GetFormItem(0).(*tview.InputField).GetText()
+			m.showModification(processID)
+		}).
 		AddButton("Back", func() {
 			m.pages.SwitchToPage("main")
 		})
@@ -1009,7 +1183,7 @@ func (m *BPMNManager) showProcessSelection() {
 // -----------------------------------------------------------------------
 func (m *BPMNManager) showProcessDetails(processID string) {
 
-	details := m.createProcessDetails(processID)
+	details := m.createProcessDetails(processID, m.infoPanel)
 	m.infoPanel.SetBackgroundColor(tcell.ColorDarkGreen).SetBorderColor(tcell.Color102)
 	m.infoPanel.SetDynamicColors(true)
 	m.infoPanel.SetTitle("Process Details")
@@ -1076,11 +1250,21 @@ Statistics:
 func (m *BPMNManager) showSettings() {
 	form := tview.NewForm().
 		AddInputField("API Base URL", m.baseURL, 50, nil, nil).
-		AddInputField("Auth Token", "your-token-here", 50, nil, nil)
+		AddInputField("Auth Token", "your-token-here", 50, nil, nil).
+		AddCheckbox("Business time", m.businessTime, nil)
+	windows := make([]string, len(dashboardWindows))
+	for i, days := range dashboardWindows {
+		windows[i] = fmt.Sprintf("Last %d days", days)
+	}
+	form.AddDropDown("Dashboard window", windows, m.dashboardWindow, nil)
 
 	form.AddButton("Save", func() {
 		newURL := form.GetFormItem(0).(*tview.InputField).GetText()
 		token := form.GetFormItem(1).(*tview.InputField).GetText()
+		m.setBusinessTime(form.GetFormItem(2).(*tview.Checkbox).IsChecked())
+		if window, _ := form.GetFormItem(3).(*tview.DropDown).GetCurrentOption(); window >= 0 {
+			m.setDashboardWindow(window)
+		}
 
 		m.baseURL = newURL
 		m.apiClient = api.NewAPIClient(newURL)
@@ -1110,6 +1294,24 @@ func (m *BPMNManager) showMessage(message string) {
 	m.pages.SwitchToPage("message")
 }
 
+// -----------------------------------------------------------------------
+// backOnEsc makes Esc on a page switch back to the page named back. An
+// input field inside the page still gets the key to leave its search.
+func (m *BPMNManager) backOnEsc(page interface {
+	SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey) *tview.Box
+}, back string) {
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyEsc {
+			return event
+		}
+		if _, ok := m.app.GetFocus().(*tview.InputField); ok {
+			return event
+		}
+		m.pages.SwitchToPage(back)
+		return nil
+	})
+}
+
 // -----------------------------------------------------------------------
 func (m *BPMNManager) Run() error {
 	m.setupUI()
@@ -1124,11 +1326,18 @@ func (m *BPMNManager) Run() error {
 		case tcell.KeyF3:
 			m.showProcessSearch()
 			return nil
+		case tcell.KeyF6:
+			m.setBusinessTime(!m.businessTime)
+			m.showMessage("Durations and SLAs are now measured in " + m.timeMode())
+			return nil
+		case tcell.KeyF7:
+			m.cycleDashboardWindow()
+			return nil
 		case tcell.KeyF2:
 			if m.currentPage == "process_selection" {
 				m.updateDashboardPanel()
+				return nil
 			}
-			return nil
 		case tcell.KeyCtrlC:
 			m.app.Stop()
 			return nil
@@ -1136,6 +1345,14 @@ func (m *BPMNManager) Run() error {
 			if m.currentPage == "process_selection" {
 				return nil
 			}
+			// other pages and input fields handle Esc themselves, e.g. to
+			// close a list or leave a search
+			if name, _ := m.pages.GetFrontPage(); name != "main" {
+				return event
+			}
+			if _, ok := m.app.GetFocus().(*tview.InputField); ok {
+				return event
+			}
 			m.updateDashboardPanel()
 			return nil
 		}
@@ -1147,8 +1364,12 @@ func (m *BPMNManager) Run() error {
 
 // -----------------------------------------------------------------------
 func main() {
+	if runCLI(os.Args[1:]) {
+		return
+	}
+
 	// Default API URL
-	baseURL := "http://192.168.164.150:8086"
+	baseURL := defaultBaseURL
 	if len(os.Args) > 1 {
 		baseURL = os.Args[1]
 	}
@@ -0,0 +1,77 @@
+package migration
+
+import (
+	"time"
+)
+
+// BatchResult is the outcome of migrating one batch of instances
+type BatchResult struct {
+	Number      int
+	InstanceIDs []string
+	Duration    time.Duration
+	Err         error
+}
+
+// Report collects the results of a batched migration
+type Report struct {
+	Results []BatchResult
+}
+
+// -----------------------------------------------------------------------
+// Batches splits ids into chunks of at most size ids
+func Batches(ids []string, size int) [][]string {
+	if size < 1 {
+		size = 1
+	}
+	var batches [][]string
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batches = append(batches, ids[start:end])
+	}
+	return batches
+}
+
+// -----------------------------------------------------------------------
+// Run migrates ids batch by batch. A failed batch does not stop the
+// following ones; progress is called after each batch.
+func Run(ids []string, size int, migrate func(batch []string) error, progress func(BatchResult)) *Report {
+	report := &Report{}
+	for i, batch := range Batches(ids, size) {
+		started := time.Now()
+		result := BatchResult{Number: i + 1, InstanceIDs: batch}
+		result.Err = migrate(batch)
+		result.Duration = time.Since(started)
+		report.Results = append(report.Results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+	return report
+}
+
+// -----------------------------------------------------------------------
+// Migrated returns the number of instances in successful batches
+func (r *Report) Migrated() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Err == nil {
+			n += len(result.InstanceIDs)
+		}
+	}
+	return n
+}
+
+// -----------------------------------------------------------------------
+// Failed returns the instances of failed batches
+func (r *Report) Failed() []string {
+	var ids []string
+	for _, result := range r.Results {
+		if result.Err != nil {
+			ids = append(ids, result.InstanceIDs...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,179 @@
+package migration
+
+import (
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mapping pairs a source activity with its target, Target is empty when
+// the activity is not migrated
+type Mapping struct {
+	Source string
+	Target string
+	Auto   bool // matched by identical id
+}
+
+// Plan is an editable migration plan between two parsed definitions
+type Plan struct {
+	SourceID string
+	TargetID string
+	Source   *bpmn.Model
+	Target   *bpmn.Model
+	Mappings []Mapping
+}
+
+// -----------------------------------------------------------------------
+// migratable reports whether a node can hold a token that needs mapping
+func migratable(node *bpmn.Node) bool {
+	return node.Kind != "startEvent" && node.Kind != "endEvent" && !node.IsGateway()
+}
+
+// -----------------------------------------------------------------------
+// AutoMatch builds a plan that maps every source activity to the target
+// activity with the same id and kind
+func AutoMatch(sourceID string, source *bpmn.Model, targetID string, target *bpmn.Model) *Plan {
+	plan := &Plan{SourceID: sourceID, TargetID: targetID, Source: source, Target: target}
+	for _, id := range source.Order {
+		node := source.Node(id)
+		if !migratable(node) {
+			continue
+		}
+		mapping := Mapping{Source: id}
+		if other := target.Node(id); other != nil && other.Kind == node.Kind {
+			mapping.Target = id
+			mapping.Auto = true
+		}
+		plan.Mappings = append(plan.Mappings, mapping)
+	}
+	return plan
+}
+
+// -----------------------------------------------------------------------
+// Candidates lists the target activities a source activity may map to,
+// nodes of the same kind first
+func (p *Plan) Candidates(sourceID string) []*bpmn.Node {
+	node := p.Source.Node(sourceID)
+	var same, other []*bpmn.Node
+	for _, id := range p.Target.Order {
+		candidate := p.Target.Node(id)
+		if !migratable(candidate) {
+			continue
+		}
+		if node != nil && candidate.Kind == node.Kind {
+			same = append(same, candidate)
+		} else {
+			other = append(other, candidate)
+		}
+	}
+	return append(same, other...)
+}
+
+// -----------------------------------------------------------------------
+// SetTarget changes the target of a source activity, an empty target
+// removes the mapping
+func (p *Plan) SetTarget(sourceID, targetID string) error {
+	if targetID != "" && p.Target.Node(targetID) == nil {
+		return fmt.Errorf("activity %s does not exist in the target definition", targetID)
+	}
+	for i := range p.Mappings {
+		if p.Mappings[i].Source == sourceID {
+			p.Mappings[i].Target = targetID
+			p.Mappings[i].Auto = false
+			return nil
+		}
+	}
+	return fmt.Errorf("activity %s is not part of the source definition", sourceID)
+}
+
+// -----------------------------------------------------------------------
+// Unmapped returns the source activities without a target
+func (p *Plan) Unmapped() []string {
+	var ids []string
+	for _, mapping := range p.Mappings {
+		if mapping.Target == "" {
+			ids = append(ids, mapping.Source)
+		}
+	}
+	return ids
+}
+
+// -----------------------------------------------------------------------
+// Engine converts the plan into the engine payload
+func (p *Plan) Engine() models.MigrationPlan {
+	plan := models.MigrationPlan{
+		SourceProcessDefinitionID: p.SourceID,
+		TargetProcessDefinitionID: p.TargetID,
+		Instructions:              []models.MigrationInstruction{},
+	}
+	for _, mapping := range p.Mappings {
+		if mapping.Target == "" {
+			continue
+		}
+		instruction := models.MigrationInstruction{
+			SourceActivityIDs: []string{mapping.Source},
+			TargetActivityIDs: []string{mapping.Target},
+		}
+		if node := p.Source.Node(mapping.Source); node != nil && node.EventType != "" {
+			instruction.UpdateEventTrigger = true
+		}
+		plan.Instructions = append(plan.Instructions, instruction)
+	}
+	return plan
+}
+
+// -----------------------------------------------------------------------
+// Failures flattens an engine validation report into source id -> reasons
+func Failures(report *models.MigrationValidationReport) map[string][]string {
+	failures := make(map[string][]string)
+	if report == nil {
+		return failures
+	}
+	for _, r := range report.InstructionReports {
+		if len(r.Failures) == 0 {
+			continue
+		}
+		key := strings.Join(r.Instruction.SourceActivityIDs, ",")
+		failures[key] = append(failures[key], r.Failures...)
+	}
+	return failures
+}
+
+// Filter selects the instances of the source definition to migrate
+type Filter struct {
+	Text          string    // matched against instance id, key and name
+	StartedBefore time.Time // zero means no limit
+}
+
+// -----------------------------------------------------------------------
+// Select returns the ids of the running instances of definitionID that
+// match the filter, oldest first
+func Select(processes []models.RunningProcess, definitionID string, filter Filter) []string {
+	text := strings.ToLower(strings.TrimSpace(filter.Text))
+	var selected []models.RunningProcess
+	for _, process := range processes {
+		if process.ProcessDefinitionId != definitionID {
+			continue
+		}
+		if !filter.StartedBefore.IsZero() && !process.StartTime.Before(filter.StartedBefore) {
+			continue
+		}
+		if text != "" &&
+			!strings.Contains(strings.ToLower(process.ProcessID), text) &&
+			!strings.Contains(strings.ToLower(process.ProcessName), text) &&
+			!strings.Contains(strings.ToLower(process.ProcessDefinitionKey), text) {
+			continue
+		}
+		selected = append(selected, process)
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].StartTime.Before(selected[j].StartTime) })
+
+	ids := make([]string, len(selected))
+	for i, process := range selected {
+		ids[i] = process.ProcessID
+	}
+	return ids
+}
@@ -0,0 +1,170 @@
+package migration
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+)
+
+// v1 holds a review task, a payment message event and a ship task
+const v1 = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <message id="msg" name="paid"/>
+  <process id="order" isExecutable="true">
+    <startEvent id="start"/>
+    <userTask id="review" name="Review"/>
+    <intermediateCatchEvent id="payment" name="Payment">
+      <messageEventDefinition messageRef="msg"/>
+    </intermediateCatchEvent>
+    <serviceTask id="ship" name="Ship"/>
+    <endEvent id="end"/>
+  </process>
+</definitions>`
+
+// v2 keeps review and payment and replaces ship by a gateway, a Dispatch
+// user task and a Deliver service task
+const v2 = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <message id="msg" name="paid"/>
+  <process id="order" isExecutable="true">
+    <startEvent id="start"/>
+    <userTask id="review" name="Review"/>
+    <intermediateCatchEvent id="payment" name="Payment">
+      <messageEventDefinition messageRef="msg"/>
+    </intermediateCatchEvent>
+    <exclusiveGateway id="check"/>
+    <userTask id="dispatch" name="Dispatch"/>
+    <serviceTask id="deliver" name="Deliver"/>
+    <endEvent id="end"/>
+  </process>
+</definitions>`
+
+// -----------------------------------------------------------------------
+func testPlan(t *testing.T) *Plan {
+	source, err := bpmn.Parse([]byte(v1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := bpmn.Parse([]byte(v2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return AutoMatch("order:1", source, "order:2", target)
+}
+
+func TestAutoMatch(t *testing.T) {
+	plan := testPlan(t)
+	want := []Mapping{
+		{Source: "review", Target: "review", Auto: true},
+		{Source: "payment", Target: "payment", Auto: true},
+		{Source: "ship"},
+	}
+	if !reflect.DeepEqual(plan.Mappings, want) {
+		t.Errorf("mappings = %+v, want %+v", plan.Mappings, want)
+	}
+	if got := plan.Unmapped(); !reflect.DeepEqual(got, []string{"ship"}) {
+		t.Errorf("Unmapped() = %v", got)
+	}
+
+	var candidates []string
+	for _, node := range plan.Candidates("ship") {
+		candidates = append(candidates, node.ID)
+	}
+	if want := []string{"deliver", "review", "payment", "dispatch"}; !reflect.DeepEqual(candidates, want) {
+		t.Errorf("Candidates(ship) = %v, want %v", candidates, want)
+	}
+}
+
+func TestSetTargetAndEngine(t *testing.T) {
+	plan := testPlan(t)
+	if err := plan.SetTarget("ship", "deliver"); err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.SetTarget("review", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.SetTarget("ship", "missing"); err == nil {
+		t.Error("mapping to a missing target succeeded")
+	}
+	if err := plan.SetTarget("start", "review"); err == nil {
+		t.Error("mapping a start event succeeded")
+	}
+
+	want := models.MigrationPlan{
+		SourceProcessDefinitionID: "order:1",
+		TargetProcessDefinitionID: "order:2",
+		Instructions: []models.MigrationInstruction{
+			{SourceActivityIDs: []string{"payment"}, TargetActivityIDs: []string{"payment"}, UpdateEventTrigger: true},
+			{SourceActivityIDs: []string{"ship"}, TargetActivityIDs: []string{"deliver"}},
+		},
+	}
+	if got := plan.Engine(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Engine() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	report := &models.MigrationValidationReport{InstructionReports: []models.MigrationInstructionReport{
+		{Instruction: models.MigrationInstruction{SourceActivityIDs: []string{"review"}}},
+		{Instruction: models.MigrationInstruction{SourceActivityIDs: []string{"ship"}}, Failures: []string{"kind differs"}},
+	}}
+	if got := Failures(report); !reflect.DeepEqual(got, map[string][]string{"ship": {"kind differs"}}) {
+		t.Errorf("Failures() = %v", got)
+	}
+	if got := Failures(nil); len(got) != 0 {
+		t.Errorf("Failures(nil) = %v", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	processes := []models.RunningProcess{
+		{ProcessID: "p3", ProcessDefinitionId: "order:1", ProcessName: "Order", StartTime: day(3)},
+		{ProcessID: "p1", ProcessDefinitionId: "order:1", ProcessName: "Order", StartTime: day(1)},
+		{ProcessID: "p2", ProcessDefinitionId: "order:2", ProcessName: "Order", StartTime: day(2)},
+		{ProcessID: "x4", ProcessDefinitionId: "order:1", ProcessName: "Rush order", StartTime: day(4)},
+	}
+	tests := []struct {
+		filter Filter
+		want   []string
+	}{
+		{Filter{}, []string{"p1", "p3", "x4"}},
+		{Filter{Text: "RUSH"}, []string{"x4"}},
+		{Filter{Text: "p"}, []string{"p1", "p3"}},
+		{Filter{StartedBefore: day(3)}, []string{"p1"}},
+	}
+	for _, tt := range tests {
+		if got := Select(processes, "order:1", tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%+v) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if got := Batches([]string{"a", "b", "c", "d", "e"}, 2); !reflect.DeepEqual(got, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}) {
+		t.Errorf("Batches() = %v", got)
+	}
+	if got := Batches([]string{"a", "b"}, 0); len(got) != 2 {
+		t.Errorf("Batches(size 0) = %v", got)
+	}
+
+	var progress []int
+	report := Run([]string{"a", "b", "c", "d", "e"}, 2, func(batch []string) error {
+		if batch[0] == "c" {
+			return errors.New("rejected")
+		}
+		return nil
+	}, func(result BatchResult) {
+		progress = append(progress, result.Number)
+	})
+	if !reflect.DeepEqual(progress, []int{1, 2, 3}) {
+		t.Errorf("progress = %v", progress)
+	}
+	if report.Migrated() != 3 || !reflect.DeepEqual(report.Failed(), []string{"c", "d"}) {
+		t.Errorf("migrated %d, failed %v; want 3, [c d]", report.Migrated(), report.Failed())
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bpmn-manager/migration"
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showMigration lets the user pick the source and target versions of a
+// migration
+func (m *BPMNManager) showMigration() {
+	go func() {
+		definitions, err := m.apiClient.GetProcessDefinitions()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load process definitions: " + err.Error())
+				return
+			}
+			if len(definitions) < 2 {
+				m.showError("At least two process definitions are needed for a migration")
+				return
+			}
+			m.showMigrationVersions(definitions)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func definitionLabel(definition models.ProcessDefinition) string {
+	label := fmt.Sprintf("%s v%d", definition.Key, definition.Version)
+	if definition.VersionTag != "" {
+		label += " (" + definition.VersionTag + ")"
+	}
+	return label
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showMigrationVersions(definitions []models.ProcessDefinition) {
+	sort.Slice(definitions, func(i, j int) bool {
+		if definitions[i].Key != definitions[j].Key {
+			return definitions[i].Key < definitions[j].Key
+		}
+		return definitions[i].Version < definitions[j].Version
+	})
+	labels := make([]string, len(definitions))
+	for i, definition := range definitions {
+		labels[i] = definitionLabel(definition)
+	}
+
+	// latestOf returns the newest version sharing the key of definition i
+	latestOf := func(i int) int {
+		latest := i
+		for j, definition := range definitions {
+			if definition.Key == definitions[i].Key && definition.Version > definitions[latest].Version {
+				latest = j
+			}
+		}
+		return latest
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Source version", labels, 0, nil).
+		AddDropDown("Target version", labels, latestOf(0), nil)
+	form.GetFormItem(0).(*tview.DropDown).SetSelectedFunc(func(text string, index int) {
+		form.GetFormItem(1).(*tview.DropDown).SetCurrentOption(latestOf(index))
+	})
+
+	form.AddButton("Build plan", func() {
+		sourceIndex, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		targetIndex, _ := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		if sourceIndex == targetIndex {
+			m.showError("Source and target must be different versions")
+			return
+		}
+		source, target := definitions[sourceIndex], definitions[targetIndex]
+
+		go func() {
+			sourceModel, err := loadDefinitionModel(m.apiClient, source.ID)
+			if err == nil {
+				targetModel, targetErr := loadDefinitionModel(m.apiClient, target.ID)
+				if targetErr != nil {
+					err = targetErr
+				} else {
+					plan := migration.AutoMatch(source.ID, sourceModel, target.ID, targetModel)
+					m.app.QueueUpdateDraw(func() {
+						m.showMigrationPlan(plan, definitionLabel(source), definitionLabel(target))
+					})
+					return
+				}
+			}
+			m.app.QueueUpdateDraw(func() {
+				m.showError("Failed to load process model: " + err.Error())
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Migrate Instances ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("migration_versions", form, true, true)
+	m.pages.SwitchToPage("migration_versions")
+}
+
+// -----------------------------------------------------------------------
+// showMigrationPlan opens the mapping editor of a plan
+func (m *BPMNManager) showMigrationPlan(plan *migration.Plan, sourceLabel, targetLabel string) {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	info := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	info.SetBorder(true).SetTitle(" Validation ").SetBorderColor(tcell.Color102)
+	info.SetText("[gray]Press 'v' to validate the plan against the engine")
+
+	failures := map[string][]string{}
+
+	render := func() {
+		row, _ := table.GetSelection()
+		table.Clear()
+		headers := []string{"Source activity", "|Type", "|Target activity", "|Status"}
+		for i, header := range headers {
+			table.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		for i, mapping := range plan.Mappings {
+			node := plan.Source.Node(mapping.Source)
+			target, status, color := "—", "not migrated", tcell.ColorOrange
+			if mapping.Target != "" {
+				target = rtl(plan.Target.Node(mapping.Target).Label())
+				status, color = "manual", tcell.ColorWhite
+				if mapping.Auto {
+					status, color = "auto", tcell.ColorGreen
+				}
+			}
+			if reasons := failures[mapping.Source]; len(reasons) > 0 {
+				status, color = "invalid", tcell.ColorRed
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(rtl(node.Label())).SetReference(mapping.Source))
+			table.SetCell(i+1, 1, tview.NewTableCell("|"+node.Kind).SetTextColor(tcell.ColorGray))
+			table.SetCell(i+1, 2, tview.NewTableCell("|"+target))
+			table.SetCell(i+1, 3, tview.NewTableCell("|"+status).SetTextColor(color))
+		}
+		table.SetTitle(fmt.Sprintf(" %s → %s • %d unmapped ", sourceLabel, targetLabel, len(plan.Unmapped())))
+		if row < 1 {
+			row = 1
+		}
+		table.Select(row, 0)
+	}
+	render()
+
+	table.SetSelectionChangedFunc(func(row, column int) {
+		if row < 1 || row > len(plan.Mappings) {
+			return
+		}
+		if reasons := failures[plan.Mappings[row-1].Source]; len(reasons) > 0 {
+			info.SetText("[red]" + tview.Escape(strings.Join(reasons, "\n")))
+		}
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if event.Key() == tcell.KeyEnter {
+			if row >= 1 && row <= len(plan.Mappings) {
+				m.showMigrationTargets(plan, plan.Mappings[row-1].Source, render)
+			}
+			return nil
+		}
+		switch event.Rune() {
+		case 'v':
+			info.SetText("[yellow]Validating...")
+			go func() {
+				report, err := m.apiClient.ValidateMigrationPlan(plan.Engine())
+				m.app.QueueUpdateDraw(func() {
+					if err != nil {
+						info.SetText("[red]" + tview.Escape(err.Error()))
+						return
+					}
+					failures = migration.Failures(report)
+					if len(failures) == 0 {
+						info.SetText("[green]✅ The engine accepts this plan")
+					} else {
+						info.SetText(fmt.Sprintf("[red]%d instruction(s) rejected, select a red row for details", len(failures)))
+					}
+					render()
+				})
+			}()
+			return nil
+		case 'x':
+			m.showMigrationExecution(plan)
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Enter change target • v validate • x migrate instances • Esc back")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 3, true).
+		AddItem(info, 6, 0, false).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(" Migration Plan ").SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, "main")
+	m.pages.AddPage("migration", layout, true, true)
+	m.pages.SwitchToPage("migration")
+}
+
+// -----------------------------------------------------------------------
+// showMigrationTargets lets the user remap one source activity
+func (m *BPMNManager) showMigrationTargets(plan *migration.Plan, sourceID string, done func()) {
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	choose := func(targetID string) {
+		if err := plan.SetTarget(sourceID, targetID); err != nil {
+			m.showError(err.Error())
+			return
+		}
+		m.pages.SwitchToPage("migration")
+		done()
+	}
+
+	list.AddItem("— not migrated", "instances holding a token here fail to migrate", 0, func() {
+		choose("")
+	})
+	for _, candidate := range plan.Candidates(sourceID) {
+		id := candidate.ID
+		list.AddItem(rtl(candidate.Label()), candidate.Kind+" • "+id, 0, func() {
+			choose(id)
+		})
+	}
+
+	list.SetDoneFunc(func() {
+		m.pages.SwitchToPage("migration")
+	})
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Target for %s ", rtl(plan.Source.Node(sourceID).Label()))).
+		SetBorderColor(tcell.Color102)
+	m.pages.AddPage("migration_target", list, true, true)
+	m.pages.SwitchToPage("migration_target")
+}
+
+// -----------------------------------------------------------------------
+// showMigrationExecution selects the instances to migrate and runs the
+// plan in batches
+func (m *BPMNManager) showMigrationExecution(plan *migration.Plan) {
+	form := tview.NewForm().
+		AddInputField("Filter", "", 40, nil, nil).
+		AddInputField("Started before", "", 20, nil, nil).
+		AddInputField("Batch size", "50", 6, tview.InputFieldInteger, nil).
+		AddCheckbox("Skip custom listeners", false, nil).
+		AddCheckbox("Skip IO mappings", false, nil)
+
+	form.AddButton("Migrate", func() {
+		filter := migration.Filter{Text: form.GetFormItem(0).(*tview.InputField).GetText()}
+		if before := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()); before != "" {
+			t, err := time.ParseInLocation("2006-01-02", before, time.Local)
+			if err != nil {
+				m.showError("Started before must be a date like 2006-01-02")
+				return
+			}
+			filter.StartedBefore = t
+		}
+		size, err := strconv.Atoi(form.GetFormItem(2).(*tview.InputField).GetText())
+		if err != nil || size < 1 {
+			m.showError("Batch size must be a positive number")
+			return
+		}
+		execution := models.MigrationExecution{
+			MigrationPlan:       plan.Engine(),
+			SkipCustomListeners: form.GetFormItem(3).(*tview.Checkbox).IsChecked(),
+			SkipIoMappings:      form.GetFormItem(4).(*tview.Checkbox).IsChecked(),
+		}
+
+		go func() {
+			processes, err := m.apiClient.GetRunningProcesses()
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError("Failed to load running processes: " + err.Error())
+					return
+				}
+				ids := migration.Select(processes, plan.SourceID, filter)
+				if len(ids) == 0 {
+					m.showError("No running instances of the source version match the filter")
+					return
+				}
+				m.confirmMigration(execution, ids, size)
+			})
+		}()
+	}).
+		AddButton("Back", func() {
+			m.pages.SwitchToPage("migration")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("migration")
+	})
+	form.SetBorder(true).SetTitle(" Migrate Instances ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("migration_execute", form, true, true)
+	m.pages.SwitchToPage("migration_execute")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) confirmMigration(execution models.MigrationExecution, ids []string, size int) {
+	batches := len(migration.Batches(ids, size))
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Migrate %d instance(s) in %d batch(es)?", len(ids), batches)).
+		AddButtons([]string{"Migrate", "Back"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel != "Migrate" {
+				m.pages.SwitchToPage("migration_execute")
+				return
+			}
+			m.runMigration(execution, ids, size)
+		})
+	m.pages.AddPage("migration_confirm", modal, true, true)
+	m.pages.SwitchToPage("migration_confirm")
+}
+
+// -----------------------------------------------------------------------
+// runMigration executes the batches in the background and shows their
+// progress followed by the final report
+func (m *BPMNManager) runMigration(execution models.MigrationExecution, ids []string, size int) {
+	progress := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true).SetScrollable(true)
+	progress.SetBorder(true).SetTitle(" Migration Report • Esc back ").SetBorderColor(tcell.Color102)
+	m.backOnEsc(progress, "main")
+	progress.SetText(fmt.Sprintf("[yellow]Migrating %d instance(s)...\n", len(ids)))
+	m.pages.AddPage("migration_report", progress, true, true)
+	m.pages.SwitchToPage("migration_report")
+
+	go func() {
+		report := migration.Run(ids, size, func(batch []string) error {
+			request := execution
+			request.ProcessInstanceIDs = batch
+			return m.apiClient.ExecuteMigration(request)
+		}, func(result migration.BatchResult) {
+			line := fmt.Sprintf("[green]batch %d: %d instance(s) migrated\n", result.Number, len(result.InstanceIDs))
+			if result.Err != nil {
+				line = fmt.Sprintf("[red]batch %d: %s\n", result.Number, tview.Escape(result.Err.Error()))
+			}
+			m.app.QueueUpdateDraw(func() {
+				fmt.Fprint(progress, line)
+			})
+		})
+
+		m.app.QueueUpdateDraw(func() {
+			text := fmt.Sprintf("\n[yellow]Migrated %d, failed %d[white]\n", report.Migrated(), len(report.Failed()))
+			for _, id := range report.Failed() {
+				text += "  " + id + "\n"
+			}
+			fmt.Fprint(progress, text)
+			progress.ScrollToEnd()
+		})
+	}()
+}
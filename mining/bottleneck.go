@@ -0,0 +1,124 @@
+package mining
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// ActivityStats summarises how long an activity took across instances
+// and how long instances waited before it started
+type ActivityStats struct {
+	ActivityID string
+	Name       string
+	Type       string
+	Count      int
+	Mean       time.Duration
+	Median     time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	MeanWait   time.Duration
+	MedianWait time.Duration
+	P90Wait    time.Duration
+}
+
+// -----------------------------------------------------------------------
+// Score is the mean time an instance spends at the activity, waiting and
+// working; bottlenecks are ranked by it
+func (s ActivityStats) Score() time.Duration {
+	return s.Mean + s.MeanWait
+}
+
+// -----------------------------------------------------------------------
+// Bottlenecks computes per-activity duration and waiting statistics over
+// the instances, measured by clock, highest Score first. The wait of an
+// activity is the gap since the latest earlier activity that had ended
+// when it started; the first activity of an instance never waits.
+func Bottlenecks(processes []models.ProcessDetails, clock calendar.Clock) []ActivityStats {
+	byID := make(map[string]*ActivityStats)
+	var ids []string
+	durations := make(map[string][]time.Duration)
+	waits := make(map[string][]time.Duration)
+
+	for _, process := range processes {
+		trace := Trace(process)
+		for i, activity := range trace {
+			stats, ok := byID[activity.ID]
+			if !ok {
+				stats = &ActivityStats{ActivityID: activity.ID, Name: activity.Name, Type: activity.Type}
+				byID[activity.ID] = stats
+				ids = append(ids, activity.ID)
+			}
+			stats.Count++
+
+			if !activity.StartTime.IsZero() && !activity.EndTime.IsZero() {
+				durations[activity.ID] = append(durations[activity.ID], clock.Between(activity.StartTime, activity.EndTime))
+			} else {
+				durations[activity.ID] = append(durations[activity.ID], time.Duration(activity.Duration)*time.Millisecond)
+			}
+
+			var released time.Time
+			for _, earlier := range trace[:i] {
+				if !earlier.EndTime.IsZero() && !earlier.EndTime.After(activity.StartTime) && earlier.EndTime.After(released) {
+					released = earlier.EndTime
+				}
+			}
+			if !released.IsZero() {
+				waits[activity.ID] = append(waits[activity.ID], clock.Between(released, activity.StartTime))
+			}
+		}
+	}
+
+	all := make([]ActivityStats, 0, len(ids))
+	for _, id := range ids {
+		stats := byID[id]
+		d := durations[id]
+		stats.Mean, stats.Median, stats.P90, stats.P99 = Mean(d), Percentile(d, 50), Percentile(d, 90), Percentile(d, 99)
+		stats.Max = Percentile(d, 100)
+		w := waits[id]
+		stats.MeanWait, stats.MedianWait, stats.P90Wait = Mean(w), Percentile(w, 50), Percentile(w, 90)
+		all = append(all, *stats)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Score() != all[j].Score() {
+			return all[i].Score() > all[j].Score()
+		}
+		return all[i].ActivityID < all[j].ActivityID
+	})
+	return all
+}
+
+// -----------------------------------------------------------------------
+// Mean returns the average of durations, zero for none
+func Mean(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// -----------------------------------------------------------------------
+// Percentile returns the nearest-rank p-th percentile of durations, zero
+// for none. durations is sorted in place.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(durations) {
+		rank = len(durations)
+	}
+	return durations[rank-1]
+}
@@ -0,0 +1,64 @@
+package mining
+
+import (
+	"testing"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1},
+		{50, 5},
+		{90, 9},
+		{99, 10},
+		{100, 10},
+	}
+	for _, tt := range tests {
+		if got := Percentile(durations, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if Percentile(nil, 50) != 0 || Mean(nil) != 0 {
+		t.Error("statistics of no durations are not zero")
+	}
+	if got := Mean([]time.Duration{time.Second, 2 * time.Second, 6 * time.Second}); got != 3*time.Second {
+		t.Errorf("Mean() = %v, want 3s", got)
+	}
+}
+
+func TestBottlenecks(t *testing.T) {
+	minutes := func(m int) time.Time { return day.Add(time.Duration(m) * time.Minute) }
+	process := func(checkEnd, approveStart, approveEnd int) models.ProcessDetails {
+		return models.ProcessDetails{Activities: []models.ProcessActivity{
+			{ID: "check", Name: "Check", StartTime: minutes(0), EndTime: minutes(checkEnd)},
+			{ID: "approve", Name: "Approve", StartTime: minutes(approveStart), EndTime: minutes(approveEnd)},
+		}}
+	}
+	stats := Bottlenecks([]models.ProcessDetails{
+		process(10, 40, 50),
+		process(20, 20, 30),
+		process(30, 90, 100),
+	}, calendar.WallClock{})
+
+	if len(stats) != 2 || stats[0].ActivityID != "approve" || stats[1].ActivityID != "check" {
+		t.Fatalf("ranking = %+v, want approve before check", stats)
+	}
+	approve, check := stats[0], stats[1]
+	if approve.Count != 3 || approve.Mean != 10*time.Minute || approve.MeanWait != 30*time.Minute ||
+		approve.MedianWait != 30*time.Minute || approve.P90Wait != time.Hour {
+		t.Errorf("approve = %+v", approve)
+	}
+	if approve.Score() != 40*time.Minute {
+		t.Errorf("approve score = %v, want 40m", approve.Score())
+	}
+	if check.Mean != 20*time.Minute || check.Median != 20*time.Minute || check.Max != 30*time.Minute || check.MeanWait != 0 {
+		t.Errorf("check = %+v", check)
+	}
+}
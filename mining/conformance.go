@@ -0,0 +1,335 @@
+package mining
+
+import (
+	"sort"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+)
+
+// Replay is the result of replaying an instance's activities on the
+// process model with tokens on the sequence flows
+type Replay struct {
+	Instance  models.ProcessDetails
+	Fitness   float64 // 1 when the instance followed the model exactly
+	Produced  int
+	Consumed  int
+	Missing   int // tokens created because an activity ran without being enabled
+	Remaining int // tokens left over when the instance ended
+
+	Unexpected []string // activities that ran without the model enabling them
+	Skipped    []string // activities the model enabled that never ran
+	Unknown    []string // activities that are not part of the model
+}
+
+// -----------------------------------------------------------------------
+// Conforms reports whether the instance followed the modelled flow
+func (r Replay) Conforms() bool {
+	return r.Missing == 0 && r.Remaining == 0 && len(r.Unknown) == 0
+}
+
+// marking is the replay state, copied to undo failed gateway moves
+type marking struct {
+	tokens   map[string]int             // flow id -> tokens
+	opened   map[string]map[string]bool // inclusive split -> outgoing flows served since it fired
+	produced int
+	consumed int
+}
+
+// -----------------------------------------------------------------------
+func (m marking) copy() marking {
+	c := marking{
+		tokens:   make(map[string]int, len(m.tokens)),
+		opened:   make(map[string]map[string]bool, len(m.opened)),
+		produced: m.produced,
+		consumed: m.consumed,
+	}
+	for id, n := range m.tokens {
+		c.tokens[id] = n
+	}
+	for id, flows := range m.opened {
+		c.opened[id] = make(map[string]bool, len(flows))
+		for flow := range flows {
+			c.opened[id][flow] = true
+		}
+	}
+	return c
+}
+
+// replayer fires the activities of one instance on a model. Gateways are
+// not observed: they are fired silently when an activity needs a token
+// they can route to it.
+type replayer struct {
+	model *bpmn.Model
+	flows map[string]*bpmn.Flow
+	marking
+}
+
+// -----------------------------------------------------------------------
+func (r *replayer) produce(flowID string) {
+	r.tokens[flowID]++
+	r.produced++
+}
+
+// -----------------------------------------------------------------------
+func (r *replayer) take(flowID string) bool {
+	if r.tokens[flowID] == 0 {
+		return false
+	}
+	r.tokens[flowID]--
+	r.consumed++
+	return true
+}
+
+// -----------------------------------------------------------------------
+// enable tries to put a token on a flow by firing the gateways in front
+// of it, leaving the marking unchanged when it cannot
+func (r *replayer) enable(flowID string, visiting map[string]bool) bool {
+	if r.tokens[flowID] > 0 {
+		return true
+	}
+	flow := r.flows[flowID]
+	if flow == nil {
+		return false
+	}
+	gateway := r.model.Nodes[flow.Source]
+	if gateway == nil || !gateway.IsGateway() || visiting[gateway.ID] {
+		return false
+	}
+	visiting[gateway.ID] = true
+	defer delete(visiting, gateway.ID)
+
+	// an inclusive split serves each of its outgoing flows once per firing
+	if served, ok := r.opened[gateway.ID]; ok && !served[flowID] {
+		served[flowID] = true
+		r.produce(flowID)
+		return true
+	}
+
+	switch gateway.Kind {
+	case "parallelGateway":
+		saved := r.marking.copy()
+		for _, in := range gateway.Incoming {
+			if !r.enable(in.ID, visiting) {
+				r.marking = saved
+				return false
+			}
+		}
+		for _, in := range gateway.Incoming {
+			r.take(in.ID)
+		}
+		for _, out := range gateway.Outgoing {
+			r.produce(out.ID)
+		}
+		return true
+
+	case "inclusiveGateway":
+		if !r.enableAny(gateway, visiting) {
+			return false
+		}
+		for _, in := range gateway.Incoming {
+			r.take(in.ID)
+		}
+		r.produce(flowID)
+		r.opened[gateway.ID] = map[string]bool{flowID: true}
+		return true
+	}
+
+	if !r.enableAny(gateway, visiting) {
+		return false
+	}
+	for _, in := range gateway.Incoming {
+		if r.take(in.ID) {
+			break
+		}
+	}
+	r.produce(flowID)
+	return true
+}
+
+// -----------------------------------------------------------------------
+// enableAny makes sure one incoming flow of a node holds a token,
+// preferring flows that already do
+func (r *replayer) enableAny(node *bpmn.Node, visiting map[string]bool) bool {
+	for _, in := range node.Incoming {
+		if r.tokens[in.ID] > 0 {
+			return true
+		}
+	}
+	for _, in := range node.Incoming {
+		if r.enable(in.ID, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------
+// fire executes a node, reporting false when it was not enabled and a
+// missing token had to be created
+func (r *replayer) fire(node *bpmn.Node) bool {
+	enabled := true
+	switch {
+	case len(node.Incoming) == 0:
+		// start and boundary events; an interrupting boundary event
+		// withdraws the tokens its host produced when it started
+		if host := r.model.Nodes[node.AttachedTo]; host != nil && node.Interrupt {
+			for _, out := range host.Outgoing {
+				if r.tokens[out.ID] > 0 {
+					r.tokens[out.ID]--
+					r.produced--
+				}
+			}
+		}
+	case r.enableAny(node, map[string]bool{}):
+		for _, in := range node.Incoming {
+			if r.take(in.ID) {
+				break
+			}
+		}
+	default:
+		enabled = false
+		r.consumed++
+	}
+	for _, out := range node.Outgoing {
+		r.produce(out.ID)
+	}
+	return enabled
+}
+
+// -----------------------------------------------------------------------
+// ReplayInstance replays the activities of an instance on a model
+func ReplayInstance(model *bpmn.Model, process models.ProcessDetails) Replay {
+	r := &replayer{
+		model: model,
+		flows: make(map[string]*bpmn.Flow, len(model.Flows)),
+		marking: marking{
+			tokens: make(map[string]int),
+			opened: make(map[string]map[string]bool),
+		},
+	}
+	for _, flow := range model.Flows {
+		r.flows[flow.ID] = flow
+	}
+	result := Replay{Instance: process}
+
+	var nodes []*bpmn.Node
+	started := false
+	for _, activity := range Trace(process) {
+		node := model.Nodes[activity.ID]
+		if node == nil {
+			// multi-instance bodies are engine bookkeeping, not model nodes
+			if !isEngineActivity(activity.Type) {
+				result.Unknown = appendOnce(result.Unknown, activity.ID)
+			}
+			continue
+		}
+		if node.IsGateway() {
+			continue
+		}
+		// repeated executions of a loop activity use one token
+		if node.Loop && len(nodes) > 0 && nodes[len(nodes)-1] == node {
+			continue
+		}
+		if node.Kind == "startEvent" && node.Parent == "" {
+			started = true
+		}
+		nodes = append(nodes, node)
+	}
+
+	// histories may omit the start event; the instance started anyway
+	if !started {
+		for _, id := range model.Order {
+			node := model.Nodes[id]
+			if node.Kind == "startEvent" && node.Parent == "" && len(node.Incoming) == 0 {
+				r.fire(node)
+				break
+			}
+		}
+	}
+
+	missing := 0
+	for _, node := range nodes {
+		if !r.fire(node) {
+			missing++
+			result.Unexpected = appendOnce(result.Unexpected, node.Label())
+		}
+	}
+
+	for _, flow := range model.Flows {
+		if n := r.tokens[flow.ID]; n > 0 {
+			result.Remaining += n
+			if target := model.Nodes[flow.Target]; target != nil && !target.IsGateway() {
+				result.Skipped = appendOnce(result.Skipped, target.Label())
+			}
+		}
+	}
+	missing += len(result.Unknown)
+
+	result.Produced, result.Consumed, result.Missing = r.produced, r.consumed+len(result.Unknown), missing
+	result.Fitness = fitness(result.Produced, result.Consumed, result.Missing, result.Remaining)
+	return result
+}
+
+// -----------------------------------------------------------------------
+// fitness is the token-replay fitness: the share of consumed tokens that
+// were not missing averaged with the share of produced tokens that were
+// consumed
+func fitness(produced, consumed, missing, remaining int) float64 {
+	f := 1.0
+	if consumed > 0 {
+		f -= 0.5 * float64(missing) / float64(consumed)
+	}
+	if produced > 0 {
+		f -= 0.5 * float64(remaining) / float64(produced)
+	}
+	if f < 0 {
+		return 0
+	}
+	return f
+}
+
+// -----------------------------------------------------------------------
+func isEngineActivity(activityType string) bool {
+	return activityType == "multiInstanceBody"
+}
+
+// -----------------------------------------------------------------------
+func appendOnce(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// -----------------------------------------------------------------------
+// Check replays instances on the model of their definition, least
+// fitting first. byDefinition maps process definition ids to parsed
+// models; instances without a model are left out.
+func Check(byDefinition map[string]*bpmn.Model, processes []models.ProcessDetails) []Replay {
+	var replays []Replay
+	for _, process := range processes {
+		if model := byDefinition[process.ProcessDefinitionId]; model != nil {
+			replays = append(replays, ReplayInstance(model, process))
+		}
+	}
+	sort.SliceStable(replays, func(i, j int) bool {
+		return replays[i].Fitness < replays[j].Fitness
+	})
+	return replays
+}
+
+// -----------------------------------------------------------------------
+// MeanFitness averages the fitness of replays, 1 for none
+func MeanFitness(replays []Replay) float64 {
+	if len(replays) == 0 {
+		return 1
+	}
+	total := 0.0
+	for _, r := range replays {
+		total += r.Fitness
+	}
+	return total / float64(len(replays))
+}
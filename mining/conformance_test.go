@@ -0,0 +1,187 @@
+package mining
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+)
+
+// orderModel is start → Check → (Approve | Reject) → Pack and Invoice in
+// parallel → end, with a repeated Notify task after Check and a timer that
+// interrupts Pack
+const orderModel = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <process id="order" isExecutable="true">
+    <startEvent id="start"/>
+    <userTask id="check" name="Check"/>
+    <serviceTask id="notify" name="Notify">
+      <multiInstanceLoopCharacteristics/>
+    </serviceTask>
+    <exclusiveGateway id="decide" default="toReject"/>
+    <userTask id="approve" name="Approve"/>
+    <userTask id="reject" name="Reject"/>
+    <exclusiveGateway id="merge"/>
+    <parallelGateway id="fork"/>
+    <userTask id="pack" name="Pack"/>
+    <userTask id="invoice" name="Invoice"/>
+    <boundaryEvent id="late" name="Late" attachedToRef="pack">
+      <timerEventDefinition/>
+    </boundaryEvent>
+    <parallelGateway id="join"/>
+    <endEvent id="end" name="End"/>
+    <endEvent id="lateEnd" name="Late End"/>
+    <sequenceFlow id="f1" sourceRef="start" targetRef="check"/>
+    <sequenceFlow id="f2" sourceRef="check" targetRef="notify"/>
+    <sequenceFlow id="f3" sourceRef="notify" targetRef="decide"/>
+    <sequenceFlow id="toApprove" sourceRef="decide" targetRef="approve"/>
+    <sequenceFlow id="toReject" sourceRef="decide" targetRef="reject"/>
+    <sequenceFlow id="f4" sourceRef="approve" targetRef="merge"/>
+    <sequenceFlow id="f5" sourceRef="reject" targetRef="merge"/>
+    <sequenceFlow id="f6" sourceRef="merge" targetRef="fork"/>
+    <sequenceFlow id="f7" sourceRef="fork" targetRef="pack"/>
+    <sequenceFlow id="f8" sourceRef="fork" targetRef="invoice"/>
+    <sequenceFlow id="f9" sourceRef="pack" targetRef="join"/>
+    <sequenceFlow id="f10" sourceRef="invoice" targetRef="join"/>
+    <sequenceFlow id="f11" sourceRef="join" targetRef="end"/>
+    <sequenceFlow id="f12" sourceRef="late" targetRef="lateEnd"/>
+  </process>
+</definitions>`
+
+// -----------------------------------------------------------------------
+func parseOrderModel(t *testing.T) *bpmn.Model {
+	model, err := bpmn.Parse([]byte(orderModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// -----------------------------------------------------------------------
+// instance builds an instance history of the activities in order, with
+// gateways the engine would record left for the replay to skip
+func instance(id string, activityIDs ...string) models.ProcessDetails {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	process := models.ProcessDetails{ID: id, ProcessDefinitionId: "order:1"}
+	for i, activityID := range activityIDs {
+		activityType := "userTask"
+		if activityID == "body" {
+			activityType = "multiInstanceBody"
+		}
+		process.Activities = append(process.Activities, models.ProcessActivity{
+			ID:        activityID,
+			Type:      activityType,
+			StartTime: start.Add(time.Duration(i) * time.Minute),
+			Order:     i,
+		})
+	}
+	return process
+}
+
+func TestReplayInstance(t *testing.T) {
+	model := parseOrderModel(t)
+	tests := []struct {
+		name       string
+		activities []string
+		missing    int
+		remaining  int
+		unexpected []string
+		skipped    []string
+		unknown    []string
+	}{
+		{name: "happy path",
+			activities: []string{"start", "check", "notify", "decide", "approve", "merge", "fork", "pack", "invoice", "join", "end"}},
+		{name: "other branch, parallel tasks swapped",
+			activities: []string{"start", "check", "notify", "reject", "invoice", "pack", "end"}},
+		{name: "start event not recorded",
+			activities: []string{"check", "notify", "approve", "pack", "invoice", "end"}},
+		{name: "loop task repeated",
+			activities: []string{"start", "check", "notify", "notify", "notify", "body", "approve", "pack", "invoice", "end"}},
+		{name: "interrupting timer withdraws the host's token",
+			activities: []string{"start", "check", "notify", "approve", "pack", "late", "lateEnd"},
+			remaining:  1, skipped: []string{"Invoice"}},
+		{name: "parallel branch skipped",
+			activities: []string{"start", "check", "notify", "approve", "pack", "end"},
+			missing:    1, remaining: 2, unexpected: []string{"End"}, skipped: []string{"Invoice"}},
+		{name: "both exclusive branches",
+			activities: []string{"start", "check", "notify", "approve", "reject", "pack", "invoice", "end"},
+			missing:    1, remaining: 1, unexpected: []string{"Reject"}},
+		{name: "activity out of order",
+			activities: []string{"start", "approve", "check", "notify", "pack", "invoice", "end"},
+			missing:    1, remaining: 1, unexpected: []string{"Approve"}},
+		{name: "unknown activity",
+			activities: []string{"start", "check", "notify", "audit", "approve", "pack", "invoice", "end"},
+			missing:    1, unknown: []string{"audit"}},
+		{name: "still running",
+			activities: []string{"start", "check"},
+			remaining:  1, skipped: []string{"Notify"}},
+	}
+	for _, tt := range tests {
+		r := ReplayInstance(model, instance(tt.name, tt.activities...))
+		if r.Missing != tt.missing || r.Remaining != tt.remaining ||
+			!sameList(r.Unexpected, tt.unexpected) || !sameList(r.Skipped, tt.skipped) || !sameList(r.Unknown, tt.unknown) {
+			t.Errorf("%s: missing %d remaining %d unexpected %v skipped %v unknown %v; want %d %d %v %v %v",
+				tt.name, r.Missing, r.Remaining, r.Unexpected, r.Skipped, r.Unknown,
+				tt.missing, tt.remaining, tt.unexpected, tt.skipped, tt.unknown)
+		}
+		conforms := tt.missing == 0 && tt.remaining == 0 && len(tt.unknown) == 0
+		if r.Conforms() != conforms || (r.Fitness == 1) != conforms {
+			t.Errorf("%s: conforms %v with fitness %.3f, want %v", tt.name, r.Conforms(), r.Fitness, conforms)
+		}
+		if r.Fitness < 0 || r.Fitness > 1 {
+			t.Errorf("%s: fitness %.3f out of range", tt.name, r.Fitness)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------
+func sameList(got, want []string) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func TestFitness(t *testing.T) {
+	tests := []struct {
+		produced, consumed, missing, remaining int
+		want                                   float64
+	}{
+		{10, 10, 0, 0, 1},
+		{10, 10, 1, 0, 0.95},
+		{10, 10, 0, 2, 0.9},
+		{4, 4, 4, 4, 0},
+		{0, 0, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := fitness(tt.produced, tt.consumed, tt.missing, tt.remaining); got != tt.want {
+			t.Errorf("fitness(%d, %d, %d, %d) = %v, want %v", tt.produced, tt.consumed, tt.missing, tt.remaining, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	model := parseOrderModel(t)
+	processes := []models.ProcessDetails{
+		instance("ok", "start", "check", "notify", "approve", "pack", "invoice", "end"),
+		instance("skipped", "start", "check", "notify", "approve", "pack", "end"),
+		instance("other", "start", "check"),
+	}
+	processes[2].ProcessDefinitionId = "other:1"
+
+	replays := Check(map[string]*bpmn.Model{"order:1": model}, processes)
+	if len(replays) != 2 {
+		t.Fatalf("Check replayed %d instances, want 2", len(replays))
+	}
+	if replays[0].Instance.ID != "skipped" || replays[1].Instance.ID != "ok" {
+		t.Errorf("Check order = %s, %s; want the least fitting first", replays[0].Instance.ID, replays[1].Instance.ID)
+	}
+	if mean := MeanFitness(replays); mean != (replays[0].Fitness+1)/2 {
+		t.Errorf("MeanFitness = %v", mean)
+	}
+	if mean := MeanFitness(nil); mean != 1 {
+		t.Errorf("MeanFitness(nil) = %v, want 1", mean)
+	}
+}
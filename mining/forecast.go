@@ -0,0 +1,274 @@
+package mining
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// minSimilar is how many similar histories a forecast needs before it
+// stops falling back to a shorter common prefix
+const minSimilar = 3
+
+// Forecast estimates when a running instance will complete
+type Forecast struct {
+	Remaining  time.Duration // median estimate
+	Low, High  time.Duration // 10th to 90th percentile
+	Completion time.Time
+	Earliest   time.Time
+	Latest     time.Time
+	Samples    int    // histories the estimate is based on
+	Basis      string // which histories were compared, for the explanation
+	Steps      []StepEstimate
+}
+
+// StepEstimate is a step similar histories still took after the point a
+// running instance has reached
+type StepEstimate struct {
+	ActivityID  string
+	Name        string
+	Probability float64       // share of the similar histories that ran it
+	Mean        time.Duration // mean wait plus duration when it ran
+}
+
+// -----------------------------------------------------------------------
+// At returns the forecast as seen at now: the remaining times count down
+// towards the expected completion and stop at zero
+func (f Forecast) At(clock calendar.Clock, now time.Time) Forecast {
+	left := func(t time.Time) time.Duration {
+		if d := clock.Between(now, t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	f.Remaining, f.Low, f.High = left(f.Completion), left(f.Earliest), left(f.Latest)
+	return f
+}
+
+// -----------------------------------------------------------------------
+// Contribution is the expected time the step adds to the remaining time
+func (s StepEstimate) Contribution() time.Duration {
+	return time.Duration(s.Probability * float64(s.Mean))
+}
+
+// history is a completed instance prepared for prefix matching
+type history struct {
+	trace []models.ProcessActivity
+	start time.Time
+	end   time.Time
+}
+
+// Predictor forecasts running instances from the completed histories of
+// their process definition key
+type Predictor struct {
+	clock     calendar.Clock
+	histories map[string][]history // by process definition key
+}
+
+// -----------------------------------------------------------------------
+// NewPredictor prepares completed instances for forecasting; durations
+// are measured by clock
+func NewPredictor(completed []models.ProcessDetails, clock calendar.Clock) *Predictor {
+	p := &Predictor{clock: clock, histories: make(map[string][]history)}
+	for _, process := range completed {
+		h := history{trace: Trace(process)}
+		for _, activity := range h.trace {
+			if !activity.StartTime.IsZero() && (h.start.IsZero() || activity.StartTime.Before(h.start)) {
+				h.start = activity.StartTime
+			}
+			if activity.EndTime.After(h.end) {
+				h.end = activity.EndTime
+			}
+		}
+		if h.start.IsZero() || h.end.IsZero() {
+			continue
+		}
+		p.histories[process.ProcessDefinitionKey] = append(p.histories[process.ProcessDefinitionKey], h)
+	}
+	return p
+}
+
+// -----------------------------------------------------------------------
+// Predict forecasts an instance whose activity history is known. It
+// compares the instance with histories that executed the same first
+// steps, using fewer steps until enough histories match.
+func (p *Predictor) Predict(process models.ProcessDetails, now time.Time) (Forecast, bool) {
+	histories := p.histories[process.ProcessDefinitionKey]
+	trace := Trace(process)
+	for k := len(trace); k >= 0; k-- {
+		var anchor time.Time
+		if k > 0 {
+			anchor = trace[k-1].StartTime
+		} else if len(trace) > 0 {
+			anchor = trace[0].StartTime
+		}
+		if anchor.IsZero() {
+			continue
+		}
+		var similar []history
+		var anchors []time.Time
+		for _, h := range histories {
+			if k <= len(h.trace) && sameSteps(trace[:k], h.trace[:k]) {
+				similar = append(similar, h)
+				if k > 0 {
+					anchors = append(anchors, h.trace[k-1].StartTime)
+				} else {
+					anchors = append(anchors, h.start)
+				}
+			}
+		}
+		if len(similar) < minSimilar && k > 0 {
+			continue
+		}
+		basis := fmt.Sprintf("%d completed instances that ran the same %d steps", len(similar), k)
+		if k == 0 {
+			basis = fmt.Sprintf("all %d completed instances", len(similar))
+		}
+		return p.forecast(similar, anchors, k, anchor, now, basis)
+	}
+	return Forecast{}, false
+}
+
+// -----------------------------------------------------------------------
+// PredictRunning forecasts an instance from the running instance list,
+// which only tells its start and current activity. It compares it with
+// histories that passed the same activity.
+func (p *Predictor) PredictRunning(process models.RunningProcess, now time.Time) (Forecast, bool) {
+	if process.StartTime.IsZero() {
+		return Forecast{}, false
+	}
+	histories := p.histories[process.ProcessDefinitionKey]
+	var similar []history
+	for _, h := range histories {
+		for _, activity := range h.trace {
+			if process.CurrentActivity != "" &&
+				(activity.ID == process.CurrentActivity || activity.Name == process.CurrentActivity) {
+				similar = append(similar, h)
+				break
+			}
+		}
+	}
+	basis := fmt.Sprintf("%d completed instances that passed %s", len(similar), process.CurrentActivity)
+	if len(similar) < minSimilar {
+		similar = histories
+		basis = fmt.Sprintf("all %d completed instances", len(similar))
+	}
+	anchors := make([]time.Time, len(similar))
+	for i, h := range similar {
+		anchors[i] = h.start
+	}
+	return p.forecast(similar, anchors, 0, process.StartTime, now, basis)
+}
+
+// -----------------------------------------------------------------------
+// forecast estimates the remaining time from the time the histories took
+// after their anchors, the point matching anchor of the running instance.
+// Histories that were already done at the instance's elapsed time are
+// left out while others remain, since the instance is evidently slower.
+func (p *Predictor) forecast(similar []history, anchors []time.Time, k int, anchor, now time.Time, basis string) (Forecast, bool) {
+	if len(similar) == 0 {
+		return Forecast{}, false
+	}
+	elapsed := p.clock.Between(anchor, now)
+	var remaining, overdue []time.Duration
+	var survivors []history
+	for i, h := range similar {
+		left := p.clock.Between(anchors[i], h.end) - elapsed
+		if left > 0 {
+			remaining = append(remaining, left)
+			survivors = append(survivors, h)
+		} else {
+			overdue = append(overdue, 0)
+		}
+	}
+	if len(remaining) == 0 {
+		remaining, survivors = overdue, similar
+		basis += ", all faster than this one"
+	}
+
+	f := Forecast{
+		Remaining: Percentile(remaining, 50),
+		Low:       Percentile(remaining, 10),
+		High:      Percentile(remaining, 90),
+		Samples:   len(remaining),
+		Basis:     basis,
+		Steps:     p.remainingSteps(survivors, k),
+	}
+	f.Completion = p.clock.Add(now, f.Remaining)
+	f.Earliest = p.clock.Add(now, f.Low)
+	f.Latest = p.clock.Add(now, f.High)
+	return f, true
+}
+
+// -----------------------------------------------------------------------
+// remainingSteps averages the steps the histories ran after their first
+// k steps, largest contribution first
+func (p *Predictor) remainingSteps(histories []history, k int) []StepEstimate {
+	byID := make(map[string]*StepEstimate)
+	var ids []string
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, h := range histories {
+		seen := make(map[string]bool)
+		for i := k; i < len(h.trace); i++ {
+			activity := h.trace[i]
+			step, ok := byID[activity.ID]
+			if !ok {
+				step = &StepEstimate{ActivityID: activity.ID, Name: activity.Name}
+				if step.Name == "" {
+					step.Name = activity.ID
+				}
+				byID[activity.ID] = step
+				ids = append(ids, activity.ID)
+			}
+			// time since the previous step started covers its wait
+			from := activity.StartTime
+			if i > 0 && h.trace[i-1].EndTime.Before(from) && !h.trace[i-1].EndTime.IsZero() {
+				from = h.trace[i-1].EndTime
+			}
+			if !activity.EndTime.IsZero() {
+				totals[activity.ID] += p.clock.Between(from, activity.EndTime)
+			}
+			counts[activity.ID]++
+			if !seen[activity.ID] {
+				seen[activity.ID] = true
+				step.Probability++
+			}
+		}
+	}
+
+	steps := make([]StepEstimate, 0, len(ids))
+	for _, id := range ids {
+		step := byID[id]
+		step.Probability /= float64(len(histories))
+		step.Mean = totals[id] / time.Duration(counts[id])
+		steps = append(steps, *step)
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Contribution() > steps[j].Contribution()
+	})
+	return steps
+}
+
+// -----------------------------------------------------------------------
+// sameSteps reports whether two step sequences contain the same
+// activities, ignoring the order parallel branches happened to run in
+func sameSteps(a, b []models.ProcessActivity) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, activity := range a {
+		counts[activity.ID]++
+	}
+	for _, activity := range b {
+		if counts[activity.ID] == 0 {
+			return false
+		}
+		counts[activity.ID]--
+	}
+	return true
+}
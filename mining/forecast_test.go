@@ -0,0 +1,129 @@
+package mining
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// -----------------------------------------------------------------------
+// testPredictor learns from four check → approve → ship histories whose
+// steps took 10, 20, 30 and 40 minutes each
+func testPredictor() *Predictor {
+	var completed []models.ProcessDetails
+	for i, minutes := range []int{10, 20, 30, 40} {
+		process := sequential(string(rune('a'+i)), minutes, "check", "approve", "ship")
+		process.ProcessDefinitionKey = "order"
+		completed = append(completed, process)
+	}
+	return NewPredictor(completed, calendar.WallClock{})
+}
+
+// -----------------------------------------------------------------------
+func running(activities ...string) models.ProcessDetails {
+	process := models.ProcessDetails{ProcessDefinitionKey: "order"}
+	for i, activity := range activities {
+		process.Activities = append(process.Activities, models.ProcessActivity{ID: activity, Order: i, StartTime: day.Add(time.Duration(i) * time.Minute)})
+	}
+	return process
+}
+
+func TestPredict(t *testing.T) {
+	p := testPredictor()
+	tests := []struct {
+		name                 string
+		process              models.ProcessDetails
+		elapsed              time.Duration
+		remaining, low, high time.Duration
+		basis                string
+	}{
+		{"same first step", running("check"), 5 * time.Minute, 55 * time.Minute, 25 * time.Minute, 115 * time.Minute,
+			"4 completed instances that ran the same 1 steps"},
+		{"unknown step falls back", running("check", "escalate"), 5 * time.Minute, 55 * time.Minute, 25 * time.Minute, 115 * time.Minute,
+			"4 completed instances that ran the same 1 steps"},
+		{"slower than all", running("check"), 200 * time.Minute, 0, 0, 0,
+			"4 completed instances that ran the same 1 steps, all faster than this one"},
+		{"faster ones dropped", running("check"), 70 * time.Minute, 20 * time.Minute, 20 * time.Minute, 50 * time.Minute,
+			"4 completed instances that ran the same 1 steps"},
+	}
+	for _, tt := range tests {
+		now := day.Add(tt.elapsed)
+		f, ok := p.Predict(tt.process, now)
+		if !ok {
+			t.Errorf("%s: no forecast", tt.name)
+			continue
+		}
+		if f.Remaining != tt.remaining || f.Low != tt.low || f.High != tt.high || f.Basis != tt.basis {
+			t.Errorf("%s: %v (%v–%v) from %q; want %v (%v–%v) from %q",
+				tt.name, f.Remaining, f.Low, f.High, f.Basis, tt.remaining, tt.low, tt.high, tt.basis)
+		}
+		if !f.Completion.Equal(now.Add(tt.remaining)) || !f.Latest.Equal(now.Add(tt.high)) {
+			t.Errorf("%s: completion %v, latest %v", tt.name, f.Completion, f.Latest)
+		}
+	}
+
+	if _, ok := p.Predict(models.ProcessDetails{ProcessDefinitionKey: "other", Activities: running("check").Activities}, day); ok {
+		t.Error("forecast without histories of the process")
+	}
+}
+
+func TestPredictSteps(t *testing.T) {
+	f, _ := testPredictor().Predict(running("check"), day.Add(5*time.Minute))
+	var steps []string
+	for _, step := range f.Steps {
+		steps = append(steps, step.ActivityID)
+		if step.Probability != 1 || step.Mean != 25*time.Minute || step.Contribution() != 25*time.Minute {
+			t.Errorf("%s: probability %v mean %v", step.ActivityID, step.Probability, step.Mean)
+		}
+	}
+	if !reflect.DeepEqual(steps, []string{"approve", "ship"}) {
+		t.Errorf("steps = %v, want [approve ship]", steps)
+	}
+}
+
+func TestPredictRunning(t *testing.T) {
+	p := testPredictor()
+	process := models.RunningProcess{ProcessDefinitionKey: "order", CurrentActivity: "approve", StartTime: day}
+	f, ok := p.PredictRunning(process, day.Add(5*time.Minute))
+	if !ok || f.Remaining != 55*time.Minute || f.Basis != "4 completed instances that passed approve" {
+		t.Errorf("PredictRunning() = %v from %q, %v", f.Remaining, f.Basis, ok)
+	}
+
+	process.CurrentActivity = "escalate"
+	if f, _ := p.PredictRunning(process, day); !strings.HasPrefix(f.Basis, "all 4") {
+		t.Errorf("unknown activity basis = %q", f.Basis)
+	}
+	process.StartTime = time.Time{}
+	if _, ok := p.PredictRunning(process, day); ok {
+		t.Error("forecast for an instance without start time")
+	}
+}
+
+func TestForecastAt(t *testing.T) {
+	p := testPredictor()
+	process := models.RunningProcess{ProcessDefinitionKey: "order", CurrentActivity: "approve", StartTime: day}
+	f, _ := p.PredictRunning(process, day.Add(5*time.Minute))
+	tests := []struct {
+		later                time.Duration
+		remaining, low, high time.Duration
+	}{
+		{0, f.Remaining, f.Low, f.High},
+		{10 * time.Minute, f.Remaining - 10*time.Minute, f.Low - 10*time.Minute, f.High - 10*time.Minute},
+		{f.Remaining, 0, 0, f.High - f.Remaining},
+		{f.High + time.Hour, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		got := f.At(calendar.WallClock{}, day.Add(5*time.Minute+tt.later))
+		if got.Remaining != tt.remaining || got.Low != tt.low || got.High != tt.high {
+			t.Errorf("At(+%v) = %v (%v–%v), want %v (%v–%v)", tt.later,
+				got.Remaining, got.Low, got.High, tt.remaining, tt.low, tt.high)
+		}
+		if got.Completion != f.Completion {
+			t.Errorf("At(+%v) moved the completion to %v", tt.later, got.Completion)
+		}
+	}
+}
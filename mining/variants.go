@@ -0,0 +1,136 @@
+package mining
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// Variant is one path through a process: the instances that executed the
+// same sequence of activities
+type Variant struct {
+	Key          string   // activity ids joined by "→"
+	Activities   []string // activity names, ids when unnamed
+	Instances    []models.ProcessDetails
+	Share        float64 // fraction of all instances
+	MeanDuration time.Duration
+	MinDuration  time.Duration
+	MaxDuration  time.Duration
+}
+
+// Order is how variants are ranked
+type Order int
+
+const (
+	ByFrequency Order = iota // most instances first
+	ByDuration               // slowest first
+)
+
+// -----------------------------------------------------------------------
+func (o Order) String() string {
+	if o == ByDuration {
+		return "mean duration"
+	}
+	return "frequency"
+}
+
+// -----------------------------------------------------------------------
+// Trace returns the activities of an instance in execution order
+func Trace(process models.ProcessDetails) []models.ProcessActivity {
+	activities := append([]models.ProcessActivity(nil), process.Activities...)
+	sort.SliceStable(activities, func(i, j int) bool {
+		a, b := activities[i], activities[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.Order < b.Order
+	})
+	return activities
+}
+
+// -----------------------------------------------------------------------
+// Duration returns how long an instance ran, from its first activity's
+// start to its last activity's end measured by clock. Instances without
+// activity times fall back to the engine's duration in milliseconds.
+func Duration(process models.ProcessDetails, clock calendar.Clock) time.Duration {
+	var start, end time.Time
+	for _, activity := range process.Activities {
+		if !activity.StartTime.IsZero() && (start.IsZero() || activity.StartTime.Before(start)) {
+			start = activity.StartTime
+		}
+		if activity.EndTime.After(end) {
+			end = activity.EndTime
+		}
+	}
+	if start.IsZero() || end.IsZero() {
+		return time.Duration(process.Duration) * time.Millisecond
+	}
+	return clock.Between(start, end)
+}
+
+// -----------------------------------------------------------------------
+// Variants groups completed instances by their activity sequence
+func Variants(processes []models.ProcessDetails, clock calendar.Clock, order Order) []Variant {
+	byKey := make(map[string]*Variant)
+	var keys []string
+	totals := make(map[string]time.Duration)
+	for _, process := range processes {
+		trace := Trace(process)
+		ids := make([]string, len(trace))
+		for i, activity := range trace {
+			ids[i] = activity.ID
+		}
+		key := strings.Join(ids, "→")
+
+		variant, ok := byKey[key]
+		if !ok {
+			variant = &Variant{Key: key, Activities: make([]string, len(trace))}
+			for i, activity := range trace {
+				variant.Activities[i] = activity.Name
+				if variant.Activities[i] == "" {
+					variant.Activities[i] = activity.ID
+				}
+			}
+			byKey[key] = variant
+			keys = append(keys, key)
+		}
+
+		d := Duration(process, clock)
+		if len(variant.Instances) == 0 || d < variant.MinDuration {
+			variant.MinDuration = d
+		}
+		if d > variant.MaxDuration {
+			variant.MaxDuration = d
+		}
+		totals[key] += d
+		variant.Instances = append(variant.Instances, process)
+	}
+
+	variants := make([]Variant, 0, len(keys))
+	for _, key := range keys {
+		variant := byKey[key]
+		variant.MeanDuration = totals[key] / time.Duration(len(variant.Instances))
+		variant.Share = float64(len(variant.Instances)) / float64(len(processes))
+		variants = append(variants, *variant)
+	}
+	Sort(variants, order)
+	return variants
+}
+
+// -----------------------------------------------------------------------
+// Sort ranks variants, breaking ties by the other measure
+func Sort(variants []Variant, order Order) {
+	sort.SliceStable(variants, func(i, j int) bool {
+		a, b := variants[i], variants[j]
+		if order == ByDuration && a.MeanDuration != b.MeanDuration {
+			return a.MeanDuration > b.MeanDuration
+		}
+		if len(a.Instances) != len(b.Instances) {
+			return len(a.Instances) > len(b.Instances)
+		}
+		return a.MeanDuration < b.MeanDuration
+	})
+}
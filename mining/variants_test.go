@@ -0,0 +1,112 @@
+package mining
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+var day = time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
+
+// -----------------------------------------------------------------------
+// sequential runs the activities one after another, each taking minutes
+func sequential(id string, minutes int, activities ...string) models.ProcessDetails {
+	process := models.ProcessDetails{ID: id}
+	at := day
+	for i, activity := range activities {
+		end := at.Add(time.Duration(minutes) * time.Minute)
+		process.Activities = append(process.Activities, models.ProcessActivity{
+			ID: activity, Name: activity, Order: i, StartTime: at, EndTime: end,
+		})
+		at = end
+	}
+	return process
+}
+
+func TestTrace(t *testing.T) {
+	process := models.ProcessDetails{Activities: []models.ProcessActivity{
+		{ID: "c", StartTime: day.Add(time.Hour)},
+		{ID: "b", StartTime: day, Order: 2},
+		{ID: "a", StartTime: day, Order: 1},
+	}}
+	var ids []string
+	for _, activity := range Trace(process) {
+		ids = append(ids, activity.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+		t.Errorf("Trace() = %v, want [a b c]", ids)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		process models.ProcessDetails
+		want    time.Duration
+	}{
+		{"activities", sequential("p", 30, "a", "b"), time.Hour},
+		{"engine duration", models.ProcessDetails{Duration: 1500}, 1500 * time.Millisecond},
+		{"running", models.ProcessDetails{Duration: 10, Activities: []models.ProcessActivity{{ID: "a", StartTime: day}}}, 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.process, calendar.WallClock{}); got != tt.want {
+			t.Errorf("%s: Duration() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	processes := []models.ProcessDetails{
+		sequential("p1", 10, "check", "approve"),
+		sequential("p2", 60, "check", "reject"),
+		sequential("p3", 20, "check", "approve"),
+		sequential("p4", 30, "check", "approve"),
+	}
+	tests := []struct {
+		order Order
+		keys  []string
+	}{
+		{ByFrequency, []string{"check→approve", "check→reject"}},
+		{ByDuration, []string{"check→reject", "check→approve"}},
+	}
+	for _, tt := range tests {
+		variants := Variants(processes, calendar.WallClock{}, tt.order)
+		var keys []string
+		for _, variant := range variants {
+			keys = append(keys, variant.Key)
+		}
+		if !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("by %s: %v, want %v", tt.order, keys, tt.keys)
+		}
+	}
+
+	approve := Variants(processes, calendar.WallClock{}, ByFrequency)[0]
+	if len(approve.Instances) != 3 || approve.Share != 0.75 {
+		t.Errorf("approve: %d instances, share %v", len(approve.Instances), approve.Share)
+	}
+	if approve.MinDuration != 20*time.Minute || approve.MeanDuration != 40*time.Minute || approve.MaxDuration != time.Hour {
+		t.Errorf("approve durations: min %v mean %v max %v", approve.MinDuration, approve.MeanDuration, approve.MaxDuration)
+	}
+	if !reflect.DeepEqual(approve.Activities, []string{"check", "approve"}) {
+		t.Errorf("approve activities = %v", approve.Activities)
+	}
+}
+
+func TestSortTies(t *testing.T) {
+	variants := []Variant{
+		{Key: "slow", Instances: make([]models.ProcessDetails, 2), MeanDuration: time.Hour},
+		{Key: "fast", Instances: make([]models.ProcessDetails, 2), MeanDuration: time.Minute},
+		{Key: "rare", Instances: make([]models.ProcessDetails, 1), MeanDuration: time.Hour},
+	}
+	Sort(variants, ByFrequency)
+	if got := []string{variants[0].Key, variants[1].Key, variants[2].Key}; !reflect.DeepEqual(got, []string{"fast", "slow", "rare"}) {
+		t.Errorf("by frequency = %v", got)
+	}
+	Sort(variants, ByDuration)
+	if got := []string{variants[0].Key, variants[1].Key, variants[2].Key}; !reflect.DeepEqual(got, []string{"slow", "rare", "fast"}) {
+		t.Errorf("by duration = %v", got)
+	}
+}
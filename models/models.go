@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/xml"
+	"strings"
 	"time"
 )
 
@@ -78,6 +79,25 @@ type UserTask struct {
 	Priority          string    `json:"status"`
 	CreatedAt         time.Time `json:"created_at"`
 	TaskDefinitionKey string    `json:"taskDefinitionKey"`
+	FollowUpDate      time.Time `json:"followUp"`
+	FormKey           string    `json:"formKey"`
+	Description       string    `json:"description"`
+	CandidateUsers    []string  `json:"candidateUsers"`
+	CandidateGroups   []string  `json:"candidateGroups"`
+}
+
+// Claimable reports whether user may claim the task: it is unassigned
+// and the user is a candidate or belongs to a candidate group
+func (t UserTask) Claimable(user *User) bool {
+	if t.Assignee != "" || user == nil {
+		return false
+	}
+	for _, candidate := range t.CandidateUsers {
+		if candidate == user.ID {
+			return true
+		}
+	}
+	return user.IsMemberOf(t.CandidateGroups...)
 }
 
 type RunningProcess struct {
@@ -111,7 +131,7 @@ type ProcessDetails struct {
 
 type ProcessActivity struct {
 	ID          string    `json:"activityId"`
-	TaskId      string    `json:taskId`
+	TaskId      string    `json:"taskId"`
 	Name        string    `json:"activityName"`
 	Type        string    `json:"activityType"`
 	Description string    `json:"description"`
@@ -138,23 +158,307 @@ type TaskCompletionRequest struct {
 	DbDecision       bool   `json:"dbDecision"`
 }
 
-// BPMN XML Structure (simplified)
+// BPMN XML Structure
 type Definitions struct {
 	XMLName   xml.Name      `xml:"definitions"`
+	ID        string        `xml:"id,attr"`
 	Processes []BPMNProcess `xml:"process"`
+	Messages  []BPMNRef     `xml:"message"`
+	Signals   []BPMNRef     `xml:"signal"`
+	Diagrams  []BPMNDiagram `xml:"BPMNDiagram"`
 }
 
 type BPMNProcess struct {
-	XMLName xml.Name `xml:"process"`
-	ID      string   `xml:"id,attr"`
-	Name    string   `xml:"name,attr"`
-	Tasks   []Task   `xml:"task"`
+	XMLName      xml.Name      `xml:"process"`
+	ID           string        `xml:"id,attr"`
+	Name         string        `xml:"name,attr"`
+	IsExecutable bool          `xml:"isExecutable,attr"`
+	FlowElements []FlowElement `xml:",any"`
+}
+
+// FlowElement is any child of a process or sub process: tasks, events,
+// gateways and sequence flows. The element kind is XMLName.Local.
+type FlowElement struct {
+	XMLName             xml.Name
+	ID                  string           `xml:"id,attr"`
+	Name                string           `xml:"name,attr"`
+	SourceRef           string           `xml:"sourceRef,attr"`
+	TargetRef           string           `xml:"targetRef,attr"`
+	AttachedToRef       string           `xml:"attachedToRef,attr"`
+	CancelActivity      string           `xml:"cancelActivity,attr"`
+	Default             string           `xml:"default,attr"`
+	MessageRef          string           `xml:"messageRef,attr"`
+	ConditionExpression string           `xml:"conditionExpression"`
+	Incoming            []string         `xml:"incoming"`
+	Outgoing            []string         `xml:"outgoing"`
+	MessageDefinition   *EventDefinition `xml:"messageEventDefinition"`
+	SignalDefinition    *EventDefinition `xml:"signalEventDefinition"`
+	TimerDefinition     *EventDefinition `xml:"timerEventDefinition"`
+	ErrorDefinition     *EventDefinition `xml:"errorEventDefinition"`
+	Children            []FlowElement    `xml:",any"`
+}
+
+type EventDefinition struct {
+	MessageRef string `xml:"messageRef,attr"`
+	SignalRef  string `xml:"signalRef,attr"`
+	ErrorRef   string `xml:"errorRef,attr"`
+}
+
+type BPMNRef struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+}
+
+// BPMNDI (diagram interchange) elements
+type BPMNDiagram struct {
+	Plane BPMNPlane `xml:"BPMNPlane"`
+}
+
+type BPMNPlane struct {
+	BPMNElement string      `xml:"bpmnElement,attr"`
+	Shapes      []BPMNShape `xml:"BPMNShape"`
+	Edges       []BPMNEdge  `xml:"BPMNEdge"`
+}
+
+type BPMNShape struct {
+	ID          string `xml:"id,attr"`
+	BPMNElement string `xml:"bpmnElement,attr"`
+	Bounds      Bounds `xml:"Bounds"`
+}
+
+type BPMNEdge struct {
+	ID          string     `xml:"id,attr"`
+	BPMNElement string     `xml:"bpmnElement,attr"`
+	Waypoints   []Waypoint `xml:"waypoint"`
+}
+
+type Bounds struct {
+	X      float64 `xml:"x,attr"`
+	Y      float64 `xml:"y,attr"`
+	Width  float64 `xml:"width,attr"`
+	Height float64 `xml:"height,attr"`
+}
+
+type Waypoint struct {
+	X float64 `xml:"x,attr"`
+	Y float64 `xml:"y,attr"`
+}
+
+// ProcessDefinitionXML is the deployed BPMN 2.0 source of a definition
+type ProcessDefinitionXML struct {
+	ID        string `json:"id"`
+	BPMN20XML string `json:"bpmn20Xml"`
+}
+
+type User struct {
+	ID        string   `json:"id"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	Email     string   `json:"email"`
+	Groups    []string `json:"groups"`
+}
+
+// IsMemberOf reports whether the user belongs to any of the given groups
+func (u *User) IsMemberOf(groups ...string) bool {
+	if u == nil {
+		return false
+	}
+	for _, own := range u.Groups {
+		for _, group := range groups {
+			if own == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VariableValue is a typed process variable as accepted by the engine
+type VariableValue struct {
+	Value interface{} `json:"value"`
+	Type  string      `json:"type"`
+}
+
+// VariableUpdate is one historic write of a process variable
+type VariableUpdate struct {
+	ID                 string      `json:"id"`
+	VariableName       string      `json:"variableName"`
+	VariableType       string      `json:"variableType"`
+	Value              interface{} `json:"value"`
+	Time               time.Time   `json:"time"`
+	ActivityInstanceID string      `json:"activityInstanceId"`
+	TaskID             string      `json:"taskId"`
+	UserID             string      `json:"userId"`
+	Revision           int         `json:"revision"`
+}
+
+// ActivityID derives the BPMN activity id from the activity instance id,
+// which the engine builds as "<activityId>:<uuid>"
+func (u VariableUpdate) ActivityID() string {
+	if i := strings.Index(u.ActivityInstanceID, ":"); i > 0 {
+		return u.ActivityInstanceID[:i]
+	}
+	return u.ActivityInstanceID
+}
+
+type Incident struct {
+	ID                  string    `json:"id"`
+	ProcessDefinitionID string    `json:"processDefinitionId"`
+	ProcessInstanceID   string    `json:"processInstanceId"`
+	ExecutionID         string    `json:"executionId"`
+	ActivityID          string    `json:"activityId"`
+	IncidentType        string    `json:"incidentType"`
+	IncidentMessage     string    `json:"incidentMessage"`
+	Configuration       string    `json:"configuration"` // id of the failed job for failedJob incidents
+	Annotation          string    `json:"annotation"`
+	IncidentTimestamp   time.Time `json:"incidentTimestamp"`
+}
+
+type Job struct {
+	ID                   string    `json:"id"`
+	ProcessInstanceID    string    `json:"processInstanceId"`
+	ProcessDefinitionID  string    `json:"processDefinitionId"`
+	ProcessDefinitionKey string    `json:"processDefinitionKey"`
+	ActivityID           string    `json:"activityId"`
+	ExceptionMessage     string    `json:"exceptionMessage"`
+	Retries              int       `json:"retries"`
+	DueDate              time.Time `json:"dueDate"`
+	Suspended            bool      `json:"suspended"`
+}
+
+// MessageCorrelation delivers a message to the instances waiting for it
+type MessageCorrelation struct {
+	MessageName       string                   `json:"messageName"`
+	BusinessKey       string                   `json:"businessKey,omitempty"`
+	ProcessInstanceID string                   `json:"processInstanceId,omitempty"`
+	CorrelationKeys   map[string]VariableValue `json:"correlationKeys,omitempty"`
+	ProcessVariables  map[string]VariableValue `json:"processVariables,omitempty"`
+	All               bool                     `json:"all"`
+}
+
+// SignalBroadcast throws a signal to every instance subscribed to it
+type SignalBroadcast struct {
+	Name        string                   `json:"name"`
+	ExecutionID string                   `json:"executionId,omitempty"`
+	Variables   map[string]VariableValue `json:"variables,omitempty"`
+}
+
+// Execution is one path of execution within a process instance
+type Execution struct {
+	ID                string `json:"id"`
+	ProcessInstanceID string `json:"processInstanceId"`
+	Ended             bool   `json:"ended"`
+}
+
+// ActivityInstance is a node of the runtime activity instance tree
+type ActivityInstance struct {
+	ID                     string             `json:"id"`
+	ActivityID             string             `json:"activityId"`
+	ActivityName           string             `json:"activityName"`
+	ActivityType           string             `json:"activityType"`
+	ChildActivityInstances []ActivityInstance `json:"childActivityInstances"`
+}
+
+// Modification instruction types
+const (
+	ModificationCancel      = "cancel"
+	ModificationStartBefore = "startBeforeActivity"
+	ModificationStartAfter  = "startAfterActivity"
+)
+
+type ModificationInstruction struct {
+	Type               string                   `json:"type"`
+	ActivityID         string                   `json:"activityId,omitempty"`
+	ActivityInstanceID string                   `json:"activityInstanceId,omitempty"`
+	Variables          map[string]VariableValue `json:"variables,omitempty"`
+}
+
+// ProcessInstanceModification moves the tokens of a running instance
+type ProcessInstanceModification struct {
+	Instructions        []ModificationInstruction `json:"instructions"`
+	SkipCustomListeners bool                      `json:"skipCustomListeners"`
+	SkipIoMappings      bool                      `json:"skipIoMappings"`
+	Annotation          string                    `json:"annotation,omitempty"`
+}
+
+// ProcessDefinition is one deployed version of a process
+type ProcessDefinition struct {
+	ID           string `json:"id"`
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	Version      int    `json:"version"`
+	VersionTag   string `json:"versionTag"`
+	DeploymentID string `json:"deploymentId"`
+	Suspended    bool   `json:"suspended"`
+}
+
+// MigrationInstruction maps activities of the source definition onto
+// activities of the target definition
+type MigrationInstruction struct {
+	SourceActivityIDs  []string `json:"sourceActivityIds"`
+	TargetActivityIDs  []string `json:"targetActivityIds"`
+	UpdateEventTrigger bool     `json:"updateEventTrigger,omitempty"`
+}
+
+type MigrationPlan struct {
+	SourceProcessDefinitionID string                 `json:"sourceProcessDefinitionId"`
+	TargetProcessDefinitionID string                 `json:"targetProcessDefinitionId"`
+	Instructions              []MigrationInstruction `json:"instructions"`
+}
+
+// MigrationInstructionReport lists why the engine rejects an instruction
+type MigrationInstructionReport struct {
+	Instruction MigrationInstruction `json:"instruction"`
+	Failures    []string             `json:"failures"`
+}
+
+type MigrationValidationReport struct {
+	InstructionReports []MigrationInstructionReport `json:"instructionReports"`
+}
+
+// MigrationExecution migrates the given instances with a plan
+type MigrationExecution struct {
+	MigrationPlan       MigrationPlan `json:"migrationPlan"`
+	ProcessInstanceIDs  []string      `json:"processInstanceIds"`
+	SkipCustomListeners bool          `json:"skipCustomListeners"`
+	SkipIoMappings      bool          `json:"skipIoMappings"`
 }
 
-type Task struct {
-	XMLName xml.Name `xml:"task"`
-	ID      string   `xml:"id,attr"`
-	Name    string   `xml:"name,attr"`
+// Page is one slice of a paginated list together with the total count
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// SortKey orders table rows by one column
+type SortKey struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// TableLayout is the column order, visibility, widths and sorting of a
+// table
+type TableLayout struct {
+	Columns []string       `json:"columns,omitempty"`
+	Sort    []SortKey      `json:"sort,omitempty"`
+	Widths  map[string]int `json:"widths,omitempty"` // max width per column, 0 = auto
+}
+
+// SavedView is a named filter, sort and column configuration of a table
+type SavedView struct {
+	Name    string         `json:"name"`
+	Target  string         `json:"target"` // tasks, completed or instances
+	Filter  string         `json:"filter,omitempty"`
+	Search  string         `json:"search,omitempty"`
+	Sort    []SortKey      `json:"sort,omitempty"`
+	Columns []string       `json:"columns,omitempty"`
+	Widths  map[string]int `json:"widths,omitempty"`
+	Hotkey  string         `json:"hotkey,omitempty"`
+}
+
+// Layout returns the table layout part of the view
+func (v *SavedView) Layout() TableLayout {
+	return TableLayout{Columns: v.Columns, Sort: v.Sort, Widths: v.Widths}
 }
 
 type FormData struct {
@@ -166,3 +470,52 @@ type FormData struct {
 	Comment           string `json:"comment"`
 	Message           string `json:"message"`
 }
+
+// Comment is a note left on a task or process instance
+type Comment struct {
+	ID                string    `json:"id"`
+	TaskID            string    `json:"taskId"`
+	ProcessInstanceID string    `json:"processInstanceId"`
+	UserID            string    `json:"userId"`
+	Time              time.Time `json:"time"`
+	Message           string    `json:"message"`
+}
+
+// Identity link types
+const (
+	IdentityLinkAssignee  = "assignee"
+	IdentityLinkCandidate = "candidate"
+	IdentityLinkOwner     = "owner"
+)
+
+// IdentityLink relates a task to a user or group. Exactly one of UserID
+// and GroupID is set.
+type IdentityLink struct {
+	Type    string `json:"type"`
+	UserID  string `json:"userId"`
+	GroupID string `json:"groupId"`
+}
+
+// IdentityLinkLog is one historic change of a task's identity links
+type IdentityLinkLog struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	UserID     string    `json:"userId"`
+	GroupID    string    `json:"groupId"`
+	AssignerID string    `json:"assignerId"`
+	Operation  string    `json:"operationType"` // add or delete
+}
+
+// Attachment is a document attached to a task or process instance
+type Attachment struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Type              string    `json:"type"`
+	Size              int64     `json:"size"`
+	TaskID            string    `json:"taskId"`
+	ProcessInstanceID string    `json:"processInstanceId"`
+	UserID            string    `json:"userId"`
+	CreatedAt         time.Time `json:"createTime"`
+}
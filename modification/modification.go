@@ -0,0 +1,150 @@
+package modification
+
+import (
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+	"fmt"
+)
+
+// Token is an active activity instance of a process instance. Tokens
+// created by a preview have no InstanceID yet.
+type Token struct {
+	ActivityID string
+	InstanceID string
+}
+
+// -----------------------------------------------------------------------
+// CurrentTokens flattens an activity instance tree into its leaves, which
+// are the activities currently holding a token
+func CurrentTokens(root *models.ActivityInstance) []Token {
+	var tokens []Token
+	var walk func(node models.ActivityInstance, isRoot bool)
+	walk = func(node models.ActivityInstance, isRoot bool) {
+		if len(node.ChildActivityInstances) == 0 {
+			if !isRoot {
+				tokens = append(tokens, Token{ActivityID: node.ActivityID, InstanceID: node.ID})
+			}
+			return
+		}
+		for _, child := range node.ChildActivityInstances {
+			walk(child, false)
+		}
+	}
+	if root != nil {
+		walk(*root, true)
+	}
+	return tokens
+}
+
+// -----------------------------------------------------------------------
+// Validate checks an instruction against the model before it is queued.
+// A cancellation of one activity instance must name a token in current.
+func Validate(model *bpmn.Model, current []Token, instruction models.ModificationInstruction) error {
+	if instruction.Type == models.ModificationCancel && instruction.ActivityInstanceID != "" {
+		for _, token := range current {
+			if token.InstanceID == instruction.ActivityInstanceID {
+				if instruction.ActivityID != "" && token.ActivityID != instruction.ActivityID {
+					return fmt.Errorf("activity instance %s belongs to %s, not %s",
+						instruction.ActivityInstanceID, token.ActivityID, instruction.ActivityID)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("activity instance %s is not running", instruction.ActivityInstanceID)
+	}
+	node := model.Node(instruction.ActivityID)
+	if node == nil {
+		return fmt.Errorf("activity %s is not part of the process model", instruction.ActivityID)
+	}
+	switch instruction.Type {
+	case models.ModificationStartAfter:
+		if len(node.Outgoing) != 1 {
+			return fmt.Errorf("start after %s needs exactly one outgoing flow, it has %d", node.Label(), len(node.Outgoing))
+		}
+	case models.ModificationStartBefore:
+		if node.Kind == "boundaryEvent" {
+			return fmt.Errorf("cannot start before boundary event %s", node.Label())
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// Preview applies instructions to the current tokens and returns the
+// token state the instance would be left in. An empty result means the
+// instance would be cancelled.
+func Preview(model *bpmn.Model, current []Token, instructions []models.ModificationInstruction) ([]Token, error) {
+	tokens := append([]Token(nil), current...)
+	for i, instruction := range instructions {
+		if err := Validate(model, tokens, instruction); err != nil {
+			return nil, fmt.Errorf("instruction %d: %v", i+1, err)
+		}
+		switch instruction.Type {
+		case models.ModificationCancel:
+			kept := tokens[:0]
+			removed := 0
+			for _, token := range tokens {
+				if (instruction.ActivityInstanceID != "" && token.InstanceID == instruction.ActivityInstanceID) ||
+					(instruction.ActivityInstanceID == "" && token.ActivityID == instruction.ActivityID) {
+					removed++
+					continue
+				}
+				kept = append(kept, token)
+			}
+			if removed == 0 {
+				return nil, fmt.Errorf("instruction %d: nothing to cancel at %s", i+1, instruction.ActivityID)
+			}
+			tokens = kept
+		case models.ModificationStartBefore:
+			tokens = append(tokens, Token{ActivityID: instruction.ActivityID})
+		case models.ModificationStartAfter:
+			flow := model.Node(instruction.ActivityID).Outgoing[0]
+			tokens = append(tokens, Token{ActivityID: flow.Target})
+		default:
+			return nil, fmt.Errorf("instruction %d: unknown type %s", i+1, instruction.Type)
+		}
+	}
+	return tokens, nil
+}
+
+// -----------------------------------------------------------------------
+// CancelActivityInstance cancels a single token, leaving other instances
+// of the same activity running
+func CancelActivityInstance(token Token) models.ModificationInstruction {
+	return models.ModificationInstruction{
+		Type:               models.ModificationCancel,
+		ActivityID:         token.ActivityID,
+		ActivityInstanceID: token.InstanceID,
+	}
+}
+
+// -----------------------------------------------------------------------
+// Describe renders an instruction for display; format, when set, is
+// applied to the activity label only
+func Describe(model *bpmn.Model, instruction models.ModificationInstruction, format func(label string) string) string {
+	label := instruction.ActivityID
+	if node := model.Node(instruction.ActivityID); node != nil {
+		label = node.Label()
+	}
+	if format != nil {
+		label = format(label)
+	}
+	text := ""
+	switch instruction.Type {
+	case models.ModificationCancel:
+		text = "cancel " + label
+		if instruction.ActivityInstanceID != "" {
+			text += " (" + instruction.ActivityInstanceID + ")"
+		}
+	case models.ModificationStartBefore:
+		text = "start before " + label
+	case models.ModificationStartAfter:
+		text = "start after " + label
+	default:
+		text = instruction.Type + " " + label
+	}
+	if n := len(instruction.Variables); n > 0 {
+		text += fmt.Sprintf(" with %d variable(s)", n)
+	}
+	return text
+}
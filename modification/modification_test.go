@@ -0,0 +1,162 @@
+package modification
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bpmn-manager/bpmn"
+	"bpmn-manager/models"
+)
+
+// reviewModel is start → review → (approve, with a timer boundary) → end
+const reviewModel = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <process id="review" isExecutable="true">
+    <startEvent id="start"/>
+    <userTask id="review" name="Review"/>
+    <exclusiveGateway id="split"/>
+    <userTask id="approve" name="Approve"/>
+    <boundaryEvent id="late" attachedToRef="approve">
+      <timerEventDefinition/>
+    </boundaryEvent>
+    <endEvent id="end"/>
+    <sequenceFlow id="f1" sourceRef="start" targetRef="review"/>
+    <sequenceFlow id="f2" sourceRef="review" targetRef="split"/>
+    <sequenceFlow id="f3" sourceRef="split" targetRef="approve"/>
+    <sequenceFlow id="f4" sourceRef="split" targetRef="end"/>
+    <sequenceFlow id="f5" sourceRef="approve" targetRef="end"/>
+    <sequenceFlow id="f6" sourceRef="late" targetRef="end"/>
+  </process>
+</definitions>`
+
+// -----------------------------------------------------------------------
+func parseModel(t *testing.T) *bpmn.Model {
+	model, err := bpmn.Parse([]byte(reviewModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// -----------------------------------------------------------------------
+func instruction(typ, activity string) models.ModificationInstruction {
+	return models.ModificationInstruction{Type: typ, ActivityID: activity}
+}
+
+func TestCurrentTokens(t *testing.T) {
+	root := &models.ActivityInstance{ID: "pi", ActivityID: "review-process", ChildActivityInstances: []models.ActivityInstance{
+		{ID: "review:1", ActivityID: "review"},
+		{ID: "sub:1", ActivityID: "sub", ChildActivityInstances: []models.ActivityInstance{
+			{ID: "approve:1", ActivityID: "approve"},
+			{ID: "approve:2", ActivityID: "approve"},
+		}},
+	}}
+	want := []Token{{"review", "review:1"}, {"approve", "approve:1"}, {"approve", "approve:2"}}
+	if got := CurrentTokens(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("CurrentTokens() = %v, want %v", got, want)
+	}
+	if got := CurrentTokens(&models.ActivityInstance{ID: "pi"}); got != nil {
+		t.Errorf("CurrentTokens(no children) = %v", got)
+	}
+	if got := CurrentTokens(nil); got != nil {
+		t.Errorf("CurrentTokens(nil) = %v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	model := parseModel(t)
+	current := []Token{{"approve", "approve:1"}}
+	tests := []struct {
+		instruction models.ModificationInstruction
+		err         string
+	}{
+		{instruction(models.ModificationStartBefore, "approve"), ""},
+		{instruction(models.ModificationStartAfter, "review"), ""},
+		{instruction(models.ModificationCancel, "review"), ""},
+		{instruction(models.ModificationStartBefore, "missing"), "not part of the process model"},
+		{instruction(models.ModificationStartAfter, "split"), "exactly one outgoing flow"},
+		{instruction(models.ModificationStartBefore, "late"), "boundary event"},
+		{CancelActivityInstance(Token{"approve", "approve:1"}), ""},
+		{CancelActivityInstance(Token{"approve", "approve:9"}), "approve:9 is not running"},
+		{CancelActivityInstance(Token{"review", "approve:1"}), "belongs to approve"},
+	}
+	for _, tt := range tests {
+		err := Validate(model, current, tt.instruction)
+		if (tt.err == "" && err != nil) || (tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err))) {
+			t.Errorf("Validate(%s %s) = %v, want %q", tt.instruction.Type, tt.instruction.ActivityID, err, tt.err)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	model := parseModel(t)
+	current := []Token{{"approve", "approve:1"}, {"approve", "approve:2"}}
+	tests := []struct {
+		name         string
+		instructions []models.ModificationInstruction
+		want         []Token
+		err          string
+	}{
+		{"move back", []models.ModificationInstruction{
+			instruction(models.ModificationCancel, "approve"),
+			instruction(models.ModificationStartBefore, "review"),
+		}, []Token{{ActivityID: "review"}}, ""},
+		{"skip review", []models.ModificationInstruction{
+			instruction(models.ModificationStartAfter, "review"),
+		}, append(current, Token{ActivityID: "split"}), ""},
+		{"cancel one instance", []models.ModificationInstruction{
+			CancelActivityInstance(Token{"approve", "approve:2"}),
+		}, []Token{{"approve", "approve:1"}}, ""},
+		{"cancel everything", []models.ModificationInstruction{
+			instruction(models.ModificationCancel, "approve"),
+		}, []Token{}, ""},
+		{"nothing to cancel", []models.ModificationInstruction{
+			instruction(models.ModificationCancel, "review"),
+		}, nil, "instruction 1: nothing to cancel at review"},
+		{"cancel a cancelled instance", []models.ModificationInstruction{
+			instruction(models.ModificationCancel, "approve"),
+			CancelActivityInstance(Token{"approve", "approve:2"}),
+		}, nil, "instruction 2: activity instance approve:2 is not running"},
+		{"invalid", []models.ModificationInstruction{
+			instruction(models.ModificationCancel, "approve"),
+			instruction(models.ModificationStartAfter, "split"),
+		}, nil, "instruction 2:"},
+	}
+	for _, tt := range tests {
+		got, err := Preview(model, current, tt.instructions)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("%s: Preview() = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if current[0].InstanceID != "approve:1" || current[1].InstanceID != "approve:2" {
+		t.Errorf("Preview changed the current tokens: %v", current)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	model := parseModel(t)
+	withVariables := instruction(models.ModificationStartBefore, "review")
+	withVariables.Variables = map[string]models.VariableValue{"a": {Value: 1}, "b": {Value: 2}}
+	tests := []struct {
+		instruction models.ModificationInstruction
+		format      func(string) string
+		want        string
+	}{
+		{instruction(models.ModificationCancel, "approve"), nil, "cancel Approve"},
+		{CancelActivityInstance(Token{"approve", "approve:2"}), nil, "cancel Approve (approve:2)"},
+		{instruction(models.ModificationStartAfter, "missing"), nil, "start after missing"},
+		{withVariables, strings.ToUpper, "start before REVIEW with 2 variable(s)"},
+	}
+	for _, tt := range tests {
+		if got := Describe(model, tt.instruction, tt.format); got != tt.want {
+			t.Errorf("Describe() = %q, want %q", got, tt.want)
+		}
+	}
+}
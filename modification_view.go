@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/modification"
+	"bpmn-manager/variables"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// modificationPlan collects the instructions queued for one instance
+type modificationPlan struct {
+	diagram      *processDiagram
+	current      []modification.Token
+	instructions []models.ModificationInstruction
+}
+
+// -----------------------------------------------------------------------
+// showModification opens the token picker of a running instance, where
+// activities can be cancelled or started before executing all queued
+// instructions at once
+func (m *BPMNManager) showModification(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	go func() {
+		diagram, err := loadInstanceDiagram(m.apiClient, instanceID)
+		var tree *models.ActivityInstance
+		if err == nil {
+			tree, err = m.apiClient.GetActivityInstances(instanceID)
+		}
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load instance: " + err.Error())
+				return
+			}
+			plan := &modificationPlan{diagram: diagram, current: modification.CurrentTokens(tree)}
+			m.pages.AddPage("modification", m.createModificationView(plan), true, true)
+			m.pages.SwitchToPage("modification")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createModificationView(plan *modificationPlan) tview.Primitive {
+	model := plan.diagram.model
+	instanceID := plan.diagram.details.ID
+
+	table := tview.NewTable().
+		SetBorders(false).
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetTitle(" Activities ").SetBorderColor(tcell.Color102)
+
+	headers := []string{"Tokens", "|Activity", "|Type", "|ID"}
+	for i, header := range headers {
+		table.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	counts := make(map[string]int)
+	for _, token := range plan.current {
+		counts[token.ActivityID]++
+	}
+	for row, id := range model.Order {
+		node := model.Node(id)
+		tokens := ""
+		if n := counts[id]; n > 0 {
+			tokens = fmt.Sprintf("[green]● %d", n)
+		}
+		indent := ""
+		for parent := node.Parent; parent != ""; parent = model.Node(parent).Parent {
+			indent += "  "
+		}
+		table.SetCell(row+1, 0, tview.NewTableCell(tokens))
+		table.SetCell(row+1, 1, tview.NewTableCell("|"+indent+rtl(node.Label())))
+		table.SetCell(row+1, 2, tview.NewTableCell("|"+node.Kind).SetTextColor(tcell.ColorGray))
+		table.SetCell(row+1, 3, tview.NewTableCell("|"+id).SetReference(id))
+	}
+	table.Select(1, 0)
+
+	instructionsView := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	instructionsView.SetBorder(true).SetTitle(" Instructions ").SetBorderColor(tcell.Color102)
+	previewView := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	previewView.SetBorder(true).SetTitle(" Resulting tokens ").SetBorderColor(tcell.Color102)
+
+	refresh := func() {
+		if len(plan.instructions) == 0 {
+			instructionsView.SetText("[gray]No instructions queued")
+		} else {
+			lines := make([]string, len(plan.instructions))
+			for i, instruction := range plan.instructions {
+				lines[i] = fmt.Sprintf("%d. %s", i+1, tview.Escape(modification.Describe(model, instruction, rtl)))
+			}
+			instructionsView.SetText(strings.Join(lines, "\n"))
+		}
+		previewView.SetText(describeTokens(plan))
+	}
+	refresh()
+
+	selected := func() string {
+		row, _ := table.GetSelection()
+		if id, ok := table.GetCell(row, 3).GetReference().(string); ok {
+			return id
+		}
+		return ""
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c':
+			if id := selected(); id != "" {
+				m.queueModification(plan, models.ModificationInstruction{Type: models.ModificationCancel, ActivityID: id}, refresh)
+			}
+			return nil
+		case 'i':
+			if id := selected(); id != "" {
+				m.cancelActivityInstance(plan, id, refresh)
+			}
+			return nil
+		case 'b', 'a':
+			if id := selected(); id != "" {
+				typ := models.ModificationStartBefore
+				if event.Rune() == 'a' {
+					typ = models.ModificationStartAfter
+				}
+				m.showModificationStartForm(plan, models.ModificationInstruction{Type: typ, ActivityID: id}, refresh)
+			}
+			return nil
+		case 'u':
+			if n := len(plan.instructions); n > 0 {
+				plan.instructions = plan.instructions[:n-1]
+				refresh()
+			}
+			return nil
+		case 'x':
+			m.confirmModification(plan)
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("c cancel tokens • i cancel one instance • b start before • a start after • u undo • x execute • Esc back")
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(instructionsView, 0, 1, false).
+		AddItem(previewView, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(table, 0, 3, true).
+		AddItem(right, 0, 2, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(fmt.Sprintf(" Modify Instance • %s ", instanceID)).SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, "main")
+	return layout
+}
+
+// -----------------------------------------------------------------------
+// describeTokens renders the preview of the queued instructions
+func describeTokens(plan *modificationPlan) string {
+	model := plan.diagram.model
+	tokens, err := modification.Preview(model, plan.current, plan.instructions)
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error())
+	}
+	if len(tokens) == 0 {
+		return "[orange]⚠️ No tokens left, the instance will be cancelled"
+	}
+
+	var lines []string
+	for _, token := range tokens {
+		label := token.ActivityID
+		if node := model.Node(token.ActivityID); node != nil {
+			label = rtl(node.Label())
+		}
+		state := "[green]new"
+		if token.InstanceID != "" {
+			state = "[gray]" + token.InstanceID
+		}
+		lines = append(lines, fmt.Sprintf("● %s %s", tview.Escape(label), state))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// -----------------------------------------------------------------------
+// queueModification appends an instruction if the preview still holds
+func (m *BPMNManager) queueModification(plan *modificationPlan, instruction models.ModificationInstruction, refresh func()) {
+	instructions := append(append([]models.ModificationInstruction(nil), plan.instructions...), instruction)
+	if _, err := modification.Preview(plan.diagram.model, plan.current, instructions); err != nil {
+		m.showError(err.Error())
+		return
+	}
+	plan.instructions = instructions
+	refresh()
+}
+
+// -----------------------------------------------------------------------
+// cancelActivityInstance queues the cancellation of one token of an
+// activity, asking which one when the activity holds several
+func (m *BPMNManager) cancelActivityInstance(plan *modificationPlan, activityID string, refresh func()) {
+	var tokens []modification.Token
+	for _, token := range plan.current {
+		if token.ActivityID == activityID {
+			tokens = append(tokens, token)
+		}
+	}
+	switch len(tokens) {
+	case 0:
+		m.showError("The activity has no running instance to cancel")
+		return
+	case 1:
+		m.queueModification(plan, modification.CancelActivityInstance(tokens[0]), refresh)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, token := range tokens {
+		token := token
+		list.AddItem(token.InstanceID, "", 0, func() {
+			m.pages.SwitchToPage("modification")
+			m.queueModification(plan, modification.CancelActivityInstance(token), refresh)
+		})
+	}
+	list.SetDoneFunc(func() {
+		m.pages.SwitchToPage("modification")
+	})
+	list.SetBorder(true).SetTitle(" Cancel which instance? ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("modification_instances", list, true, true)
+	m.pages.SwitchToPage("modification_instances")
+}
+
+// -----------------------------------------------------------------------
+// showModificationStartForm asks for the variables of a start instruction
+func (m *BPMNManager) showModificationStartForm(plan *modificationPlan, instruction models.ModificationInstruction, refresh func()) {
+	if err := modification.Validate(plan.diagram.model, plan.current, instruction); err != nil {
+		m.showError(err.Error())
+		return
+	}
+
+	form := tview.NewForm().
+		AddTextView("Instruction", modification.Describe(plan.diagram.model, instruction, rtl), 60, 1, true, false).
+		AddTextArea("Variables", "", 60, 6, 0, nil)
+
+	form.AddButton("Add", func() {
+		vars, err := variables.ParseAssignments(form.GetFormItem(1).(*tview.TextArea).GetText())
+		if err != nil {
+			m.showError(err.Error())
+			return
+		}
+		instruction.Variables = variables.ToEngineMap(vars)
+		m.pages.SwitchToPage("modification")
+		m.queueModification(plan, instruction, refresh)
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("modification")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("modification")
+	})
+	form.SetBorder(true).SetTitle(" Start Activity • one name = value per line ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("modification_start", form, true, true)
+	m.pages.SwitchToPage("modification_start")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) confirmModification(plan *modificationPlan) {
+	if len(plan.instructions) == 0 {
+		m.showError("Queue at least one instruction first")
+		return
+	}
+	if _, err := modification.Preview(plan.diagram.model, plan.current, plan.instructions); err != nil {
+		m.showError(err.Error())
+		return
+	}
+
+	instanceID := plan.diagram.details.ID
+	lines := make([]string, len(plan.instructions))
+	for i, instruction := range plan.instructions {
+		lines[i] = modification.Describe(plan.diagram.model, instruction, rtl)
+	}
+
+	modal := tview.NewModal().
+		SetText("Modify instance " + instanceID + "?\n\n" + strings.Join(lines, "\n")).
+		AddButtons([]string{"Execute", "Back"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel != "Execute" {
+				m.pages.SwitchToPage("modification")
+				return
+			}
+			request := models.ProcessInstanceModification{Instructions: plan.instructions}
+			go func() {
+				err := m.apiClient.ModifyProcessInstance(instanceID, request)
+				m.app.QueueUpdateDraw(func() {
+					if err != nil {
+						m.showError(err.Error())
+						return
+					}
+					m.showModification(instanceID)
+				})
+			}()
+		})
+	m.pages.AddPage("modification_confirm", modal, true, true)
+	m.pages.SwitchToPage("modification_confirm")
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"bpmn-manager/paging"
+	"bpmn-manager/query"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Lists with more items than largeResultThreshold are browsed page by
+// page instead of being loaded into a dataTable
+const (
+	largeResultThreshold = 2000
+	pagedPageSize        = 200
+	pagedCachedPages     = 10
+)
+
+// pageLoader fetches limit items of a list starting at offset and the
+// total number of items matching params
+type pageLoader[T any] func(params url.Values, offset, limit int) ([]T, int, error)
+
+// -----------------------------------------------------------------------
+// openLargeList decides from the first page of a list whether it is
+// browsed page by page. For a large list it returns a loader that answers
+// that first page without asking the engine again, and the total. A list
+// small enough to load whole is remembered for the session, so later
+// calls decide without a request.
+func openLargeList[T any](m *BPMNManager, list string, load pageLoader[T]) (pageLoader[T], int, error) {
+	if m.smallLists[list] {
+		return nil, 0, nil
+	}
+	items, total, err := load(nil, 0, pagedPageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	// a backend that does not page the endpoint sent everything already
+	if total <= largeResultThreshold || len(items) >= total {
+		if m.smallLists == nil {
+			m.smallLists = map[string]bool{}
+		}
+		m.smallLists[list] = true
+		return nil, total, nil
+	}
+
+	var mu sync.Mutex
+	first := items
+	return func(params url.Values, offset, limit int) ([]T, int, error) {
+		mu.Lock()
+		seeded := first
+		if len(params) == 0 && offset == 0 && limit == pagedPageSize {
+			first = nil
+		} else {
+			seeded = nil
+		}
+		mu.Unlock()
+		if seeded != nil {
+			return seeded, total, nil
+		}
+		return load(params, offset, limit)
+	}, total, nil
+}
+
+// -----------------------------------------------------------------------
+// pagedTable is a virtual table over a server-side paginated list. Only
+// the pages around the visible rows are kept in memory; filtering and
+// sorting are done by the engine.
+type pagedTable[T any] struct {
+	tview.TableContentReadOnly
+
+	app     *tview.Application
+	title   string
+	columns []dataColumn
+	index   []int // position of each shown column in the row values
+	row     func(item T) []string
+	color   func(item T) tcell.Color // optional row color
+	load    func(params url.Values, offset, limit int) ([]T, int, error)
+	schema  *query.Schema
+	cache   *paging.Cache[T]
+
+	filterParams url.Values
+	sortBy       string
+	descending   bool
+	active       int // shown column that sorting acts on
+	message      string
+
+	table  *tview.Table
+	filter *tview.InputField
+	jump   *tview.InputField
+	layout *tview.Flex
+	keys   func(item T, event *tcell.EventKey) *tcell.EventKey
+}
+
+// -----------------------------------------------------------------------
+func newPagedTable[T any](app *tview.Application, title string, columns []dataColumn, schema *query.Schema,
+	row func(item T) []string, load func(params url.Values, offset, limit int) ([]T, int, error)) *pagedTable[T] {
+	p := &pagedTable[T]{
+		app:     app,
+		title:   title,
+		row:     row,
+		load:    load,
+		schema:  schema,
+		table:   tview.NewTable().SetFixed(1, 0).SetSelectable(true, false),
+		filter:  tview.NewInputField().SetLabel(": ").SetFieldWidth(0),
+		jump:    tview.NewInputField().SetLabel("page ").SetFieldWidth(8).SetAcceptanceFunc(tview.InputFieldInteger),
+		message: "loading...",
+	}
+	for i, column := range columns {
+		if !column.Hidden {
+			p.columns = append(p.columns, column)
+			p.index = append(p.index, i)
+		}
+	}
+	p.table.SetContent(p)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	p.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			p.setQuery(p.filter.GetText())
+		}
+		p.app.SetFocus(p.table)
+	})
+	p.jump.SetDoneFunc(func(key tcell.Key) {
+		if page, err := strconv.Atoi(p.jump.GetText()); key == tcell.KeyEnter && err == nil {
+			p.jumpToPage(page)
+		}
+		p.jump.SetText("")
+		p.app.SetFocus(p.table)
+	})
+	p.table.SetSelectionChangedFunc(func(row, column int) {
+		p.updateTitle()
+	})
+
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case ':':
+			p.app.SetFocus(p.filter)
+			return nil
+		case 'g':
+			p.app.SetFocus(p.jump)
+			return nil
+		case '[':
+			p.active = (p.active - 1 + len(p.columns)) % len(p.columns)
+			return nil
+		case ']':
+			p.active = (p.active + 1) % len(p.columns)
+			return nil
+		case 's':
+			p.toggleSort()
+			return nil
+		}
+		if item, ok := p.selected(); ok && p.keys != nil {
+			return p.keys(item, event)
+		}
+		return event
+	})
+
+	bar := tview.NewFlex().
+		AddItem(p.filter, 0, 1, false).
+		AddItem(p.jump, 14, 0, false)
+	p.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(bar, 1, 0, false).
+		AddItem(p.table, 0, 1, true)
+	p.layout.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	p.reload()
+	return p
+}
+
+// -----------------------------------------------------------------------
+// setInputCapture installs the screen's key handler for loaded rows
+func (p *pagedTable[T]) setInputCapture(keys func(item T, event *tcell.EventKey) *tcell.EventKey) {
+	p.keys = keys
+}
+
+// -----------------------------------------------------------------------
+// reload drops all cached pages and fetches the first page with the
+// current filter and sort order
+func (p *pagedTable[T]) reload() {
+	params := url.Values{}
+	for key, values := range p.filterParams {
+		params[key] = values
+	}
+	if p.sortBy != "" {
+		params.Set("sortBy", p.sortBy)
+		params.Set("sortOrder", "asc")
+		if p.descending {
+			params.Set("sortOrder", "desc")
+		}
+	}
+
+	cache := paging.NewCache(pagedPageSize, pagedCachedPages, func(offset, limit int) ([]T, int, error) {
+		return p.load(params, offset, limit)
+	}, func() {
+		p.app.QueueUpdateDraw(p.updateTitle)
+	})
+	p.cache = cache
+	p.message = "loading..."
+	p.updateTitle()
+
+	go func() {
+		err := cache.Load(0)
+		p.app.QueueUpdateDraw(func() {
+			if p.cache != cache {
+				return
+			}
+			p.message = ""
+			if err != nil {
+				p.message = err.Error()
+			}
+			p.table.Select(1, 0)
+			p.table.ScrollToBeginning()
+			p.updateTitle()
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// setQuery applies a filter expression on the server. Only expressions
+// the engine can evaluate completely are accepted, since a paginated
+// list cannot be narrowed further on the client.
+func (p *pagedTable[T]) setQuery(text string) {
+	q, err := query.Parse(text, p.schema)
+	if err != nil {
+		p.message = err.Error()
+		p.filter.SetLabel("[red]: ")
+		p.updateTitle()
+		return
+	}
+	params, complete := q.ServerSide()
+	if !complete {
+		p.message = "large lists only support and-ed terms the engine matches exactly, such as time bounds"
+		p.filter.SetLabel("[red]: ")
+		p.updateTitle()
+		return
+	}
+	p.filter.SetLabel(": ")
+	p.filterParams = params
+	p.reload()
+}
+
+// -----------------------------------------------------------------------
+// toggleSort sorts by the active column: ascending, descending, off
+func (p *pagedTable[T]) toggleSort() {
+	key := p.columns[p.active].Key
+	switch {
+	case p.sortBy != key:
+		p.sortBy, p.descending = key, false
+	case !p.descending:
+		p.descending = true
+	default:
+		p.sortBy, p.descending = "", false
+	}
+	p.reload()
+}
+
+// -----------------------------------------------------------------------
+func (p *pagedTable[T]) jumpToPage(page int) {
+	pages := p.cache.Pages()
+	if page < 1 || pages == 0 {
+		page = 1
+	}
+	if page > pages && pages > 0 {
+		page = pages
+	}
+	p.table.Select((page-1)*p.cache.PageSize()+1, 0)
+}
+
+// -----------------------------------------------------------------------
+// selected returns the item of the selected row if its page is loaded
+func (p *pagedTable[T]) selected() (T, bool) {
+	row, _ := p.table.GetSelection()
+	if row < 1 {
+		var zero T
+		return zero, false
+	}
+	return p.cache.Get(row - 1)
+}
+
+// -----------------------------------------------------------------------
+func (p *pagedTable[T]) updateTitle() {
+	row, _ := p.table.GetSelection()
+	page := 1
+	if row > 0 {
+		page = (row-1)/p.cache.PageSize() + 1
+	}
+	title := fmt.Sprintf(" %s • %d total • page %d/%d ", p.title, p.cache.Total(), page, p.cache.Pages())
+	message := p.message
+	if err := p.cache.Err(); err != nil && message == "" {
+		message = err.Error()
+	}
+	if message != "" {
+		title += "• [red]" + tview.Escape(message) + " "
+	}
+	p.layout.SetTitle(title)
+}
+
+// -----------------------------------------------------------------------
+// GetCell implements tview.TableContent, loading pages on demand
+func (p *pagedTable[T]) GetCell(row, column int) *tview.TableCell {
+	if column >= len(p.columns) {
+		return nil
+	}
+	col := p.columns[column]
+	if row == 0 {
+		header := col.Header
+		if p.sortBy == col.Key && p.descending {
+			header += " ▼"
+		} else if p.sortBy == col.Key {
+			header += " ▲"
+		}
+		cell := tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAlign(col.Align).
+			SetSelectable(false)
+		if column == p.active {
+			cell.SetAttributes(tcell.AttrUnderline)
+		}
+		return cell
+	}
+
+	item, ok := p.cache.Get(row - 1)
+	if !ok {
+		if column == 0 {
+			return tview.NewTableCell("…").SetTextColor(tcell.ColorGray)
+		}
+		return tview.NewTableCell("")
+	}
+	values := p.row(item)
+	value := ""
+	if index := p.index[column]; index < len(values) {
+		value = values[index]
+	}
+	cell := tview.NewTableCell(tview.Escape(col.Prefix + rtl(value) + col.Suffix)).SetAlign(col.Align)
+	if col.Color != 0 {
+		cell.SetTextColor(col.Color)
+	}
+	if p.color != nil {
+		if color := p.color(item); color != 0 {
+			cell.SetTextColor(color)
+		}
+	}
+	return cell
+}
+
+// -----------------------------------------------------------------------
+func (p *pagedTable[T]) GetRowCount() int {
+	return p.cache.Total() + 1
+}
+
+// -----------------------------------------------------------------------
+func (p *pagedTable[T]) GetColumnCount() int {
+	return len(p.columns)
+}
@@ -0,0 +1,154 @@
+package paging
+
+import (
+	"sync"
+)
+
+// Fetch loads limit items starting at offset and returns them with the
+// total number of items
+type Fetch[T any] func(offset, limit int) ([]T, int, error)
+
+// Cache holds the recently used pages of a paginated list and loads
+// missing pages in the background
+type Cache[T any] struct {
+	mu       sync.Mutex
+	fetch    Fetch[T]
+	pageSize int
+	maxPages int
+	pages    map[int][]T
+	used     map[int]int64
+	loading  map[int]bool
+	failed   map[int]bool // not retried until the next Load
+	tick     int64
+	total    int
+	err      error
+	onLoad   func()
+}
+
+// -----------------------------------------------------------------------
+// NewCache creates a cache keeping at most maxPages pages of pageSize
+// items. onLoad is called from the loading goroutine after each
+// background fetch.
+func NewCache[T any](pageSize, maxPages int, fetch Fetch[T], onLoad func()) *Cache[T] {
+	if pageSize < 1 {
+		pageSize = 100
+	}
+	if maxPages < 2 {
+		maxPages = 2
+	}
+	return &Cache[T]{
+		fetch:    fetch,
+		pageSize: pageSize,
+		maxPages: maxPages,
+		pages:    map[int][]T{},
+		used:     map[int]int64{},
+		loading:  map[int]bool{},
+		failed:   map[int]bool{},
+		onLoad:   onLoad,
+	}
+}
+
+// -----------------------------------------------------------------------
+// Load fetches a page synchronously, which also refreshes the total
+func (c *Cache[T]) Load(page int) error {
+	items, total, err := c.fetch(page*c.pageSize, c.pageSize)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+	if err != nil {
+		return err
+	}
+	c.failed = map[int]bool{}
+	c.store(page, items, total)
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// store saves a page and evicts the least recently used pages; callers
+// hold the lock
+func (c *Cache[T]) store(page int, items []T, total int) {
+	c.pages[page] = items
+	c.total = total
+	c.tick++
+	c.used[page] = c.tick
+	for len(c.pages) > c.maxPages {
+		oldest, oldestTick := -1, int64(0)
+		for p, t := range c.used {
+			if p != page && (oldest < 0 || t < oldestTick) {
+				oldest, oldestTick = p, t
+			}
+		}
+		delete(c.pages, oldest)
+		delete(c.used, oldest)
+	}
+}
+
+// -----------------------------------------------------------------------
+// Get returns the item at index. When its page is not cached it starts
+// loading it and reports false.
+func (c *Cache[T]) Get(index int) (T, bool) {
+	var zero T
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page := index / c.pageSize
+	if items, ok := c.pages[page]; ok {
+		c.tick++
+		c.used[page] = c.tick
+		offset := index - page*c.pageSize
+		if offset < len(items) {
+			return items[offset], true
+		}
+		return zero, false
+	}
+	if !c.loading[page] && !c.failed[page] {
+		c.loading[page] = true
+		go c.loadAsync(page)
+	}
+	return zero, false
+}
+
+// -----------------------------------------------------------------------
+func (c *Cache[T]) loadAsync(page int) {
+	items, total, err := c.fetch(page*c.pageSize, c.pageSize)
+	c.mu.Lock()
+	delete(c.loading, page)
+	c.err = err
+	if err == nil {
+		c.store(page, items, total)
+	} else {
+		c.failed[page] = true
+	}
+	c.mu.Unlock()
+	if c.onLoad != nil {
+		c.onLoad()
+	}
+}
+
+// -----------------------------------------------------------------------
+// Total returns the item count reported by the last fetch
+func (c *Cache[T]) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// -----------------------------------------------------------------------
+// Err returns the error of the last fetch, if it failed
+func (c *Cache[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// -----------------------------------------------------------------------
+func (c *Cache[T]) PageSize() int {
+	return c.pageSize
+}
+
+// -----------------------------------------------------------------------
+// Pages returns the number of pages of the list
+func (c *Cache[T]) Pages() int {
+	total := c.Total()
+	return (total + c.pageSize - 1) / c.pageSize
+}
@@ -0,0 +1,111 @@
+package paging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// list serves the numbers 0..total-1 and records the requested offsets
+type list struct {
+	mu      sync.Mutex
+	total   int
+	offsets []int
+	fail    bool
+}
+
+// -----------------------------------------------------------------------
+func (l *list) fetch(offset, limit int) ([]int, int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.offsets = append(l.offsets, offset)
+	if l.fail {
+		return nil, 0, errors.New("unavailable")
+	}
+	var items []int
+	for i := offset; i < offset+limit && i < l.total; i++ {
+		items = append(items, i)
+	}
+	return items, l.total, nil
+}
+
+// -----------------------------------------------------------------------
+// get waits for the background load of index
+func get(t *testing.T, c *Cache[int], loaded chan struct{}, index int) int {
+	if value, ok := c.Get(index); ok {
+		return value
+	}
+	select {
+	case <-loaded:
+	case <-time.After(time.Second):
+		t.Fatalf("page of %d never loaded", index)
+	}
+	value, ok := c.Get(index)
+	if !ok {
+		t.Fatalf("item %d missing after load", index)
+	}
+	return value
+}
+
+func TestCache(t *testing.T) {
+	l := &list{total: 25}
+	loaded := make(chan struct{}, 10)
+	c := NewCache(10, 2, l.fetch, func() { loaded <- struct{}{} })
+
+	if err := c.Load(0); err != nil {
+		t.Fatal(err)
+	}
+	if c.Total() != 25 || c.Pages() != 3 || c.PageSize() != 10 {
+		t.Errorf("total %d, pages %d, page size %d", c.Total(), c.Pages(), c.PageSize())
+	}
+
+	tests := []struct {
+		index, want int
+	}{
+		{3, 3},
+		{24, 24},
+		{12, 12},
+	}
+	for _, tt := range tests {
+		if got := get(t, c, loaded, tt.index); got != tt.want {
+			t.Errorf("Get(%d) = %d, want %d", tt.index, got, tt.want)
+		}
+	}
+	if _, ok := c.Get(25); ok {
+		t.Error("Get past the end succeeded")
+	}
+
+	// only two pages are kept: loading page 1 evicted page 0, the least
+	// recently used one
+	before := len(l.offsets)
+	get(t, c, loaded, 0)
+	if len(l.offsets) != before+1 || l.offsets[len(l.offsets)-1] != 0 {
+		t.Errorf("offsets = %v, want page 0 fetched again", l.offsets)
+	}
+}
+
+func TestCacheFailure(t *testing.T) {
+	l := &list{total: 25, fail: true}
+	loaded := make(chan struct{}, 10)
+	c := NewCache(10, 4, l.fetch, func() { loaded <- struct{}{} })
+
+	if err := c.Load(0); err == nil || c.Err() == nil {
+		t.Fatal("Load succeeded against a failing list")
+	}
+	c.Get(15)
+	<-loaded
+	c.Get(15)
+	if len(l.offsets) != 2 {
+		t.Errorf("failed page was retried: offsets %v", l.offsets)
+	}
+
+	// a successful Load clears the failures
+	l.fail = false
+	if err := c.Load(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, c, loaded, 15); got != 15 || c.Err() != nil {
+		t.Errorf("Get(15) = %d, err %v", got, c.Err())
+	}
+}
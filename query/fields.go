@@ -0,0 +1,152 @@
+package query
+
+import (
+	"bpmn-manager/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the value type of a field
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindTime
+	KindNumber
+)
+
+// Field is a queryable attribute. Params maps operators to the engine
+// query parameter that evaluates them server side.
+type Field struct {
+	Name   string
+	Kind   Kind
+	Params map[string]string
+}
+
+// Record holds the field values of one row by field name
+type Record map[string]interface{}
+
+// Schema lists the fields a query may refer to
+type Schema struct {
+	Name   string
+	fields map[string]Field
+	alias  map[string]string
+}
+
+// -----------------------------------------------------------------------
+func newSchema(name string, fields []Field, alias map[string]string) *Schema {
+	s := &Schema{Name: name, fields: map[string]Field{}, alias: map[string]string{}}
+	for _, f := range fields {
+		s.fields[strings.ToLower(f.Name)] = f
+	}
+	for from, to := range alias {
+		s.alias[strings.ToLower(from)] = to
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------
+// Field looks up a field by name or alias, ignoring case
+func (s *Schema) Field(name string) (Field, bool) {
+	key := strings.ToLower(name)
+	if to, ok := s.alias[key]; ok {
+		key = strings.ToLower(to)
+	}
+	f, ok := s.fields[key]
+	return f, ok
+}
+
+// -----------------------------------------------------------------------
+// Names returns the field names in alphabetical order
+func (s *Schema) Names() []string {
+	names := make([]string, 0, len(s.fields))
+	for _, f := range s.fields {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TaskSchema describes models.UserTask
+var TaskSchema = newSchema("task", []Field{
+	{Name: "id", Kind: KindString, Params: map[string]string{"=": "taskId"}},
+	{Name: "name", Kind: KindString, Params: map[string]string{"=": "name", "~": "nameLike"}},
+	{Name: "processInstanceId", Kind: KindString, Params: map[string]string{"=": "processInstanceId"}},
+	{Name: "processName", Kind: KindString},
+	{Name: "activityName", Kind: KindString},
+	{Name: "assignee", Kind: KindString, Params: map[string]string{"=": "assignee", "~": "assigneeLike"}},
+	{Name: "due", Kind: KindTime, Params: map[string]string{"<": "dueBefore", ">": "dueAfter"}},
+	{Name: "status", Kind: KindString},
+	{Name: "priority", Kind: KindNumber, Params: map[string]string{"=": "priority"}},
+	{Name: "created", Kind: KindTime, Params: map[string]string{"<": "createdBefore", ">": "createdAfter"}},
+	{Name: "taskDefinitionKey", Kind: KindString, Params: map[string]string{"=": "taskDefinitionKey", "~": "taskDefinitionKeyLike"}},
+	{Name: "candidateUser", Kind: KindString, Params: map[string]string{"=": "candidateUser"}},
+	{Name: "candidateGroup", Kind: KindString, Params: map[string]string{"=": "candidateGroup"}},
+}, map[string]string{
+	"processId": "processInstanceId",
+	"dueDate":   "due",
+	"createdAt": "created",
+	"key":       "taskDefinitionKey",
+	"group":     "candidateGroup",
+})
+
+// ProcessSchema describes models.RunningProcess
+var ProcessSchema = newSchema("instance", []Field{
+	{Name: "id", Kind: KindString, Params: map[string]string{"=": "processInstanceId"}},
+	{Name: "instanceId", Kind: KindString},
+	{Name: "processDefinitionId", Kind: KindString, Params: map[string]string{"=": "processDefinitionId"}},
+	{Name: "processDefinitionKey", Kind: KindString, Params: map[string]string{"=": "processDefinitionKey", "~": "processDefinitionKeyLike"}},
+	{Name: "processName", Kind: KindString},
+	{Name: "currentActivity", Kind: KindString, Params: map[string]string{"=": "activityId"}},
+	{Name: "started", Kind: KindTime, Params: map[string]string{"<": "startedBefore", ">": "startedAfter"}},
+	{Name: "duration", Kind: KindString},
+	{Name: "status", Kind: KindString},
+}, map[string]string{
+	"processId":  "id",
+	"key":        "processDefinitionKey",
+	"activity":   "currentActivity",
+	"startTime":  "started",
+	"definition": "processDefinitionId",
+})
+
+// -----------------------------------------------------------------------
+func TaskRecord(t models.UserTask) Record {
+	return Record{
+		"id":                t.ID,
+		"name":              t.Name,
+		"processInstanceId": t.ProcessID,
+		"processName":       t.ProcessName,
+		"activityName":      t.ActivityName,
+		"assignee":          t.Assignee,
+		"due":               t.DueDate,
+		"status":            t.Status,
+		"priority":          t.Priority,
+		"created":           t.CreatedAt,
+		"taskDefinitionKey": t.TaskDefinitionKey,
+		"candidateUser":     t.CandidateUsers,
+		"candidateGroup":    t.CandidateGroups,
+	}
+}
+
+// -----------------------------------------------------------------------
+func ProcessRecord(p models.RunningProcess) Record {
+	return Record{
+		"id":                   p.ProcessID,
+		"instanceId":           p.InstanceID,
+		"processDefinitionId":  p.ProcessDefinitionId,
+		"processDefinitionKey": p.ProcessDefinitionKey,
+		"processName":          p.ProcessName,
+		"currentActivity":      p.CurrentActivity,
+		"started":              p.StartTime,
+		"duration":             p.Duration,
+		"status":               p.Status,
+	}
+}
+
+// -----------------------------------------------------------------------
+// timeValue returns the time of a record value, false when unset
+func timeValue(value interface{}) (time.Time, bool) {
+	t, ok := value.(time.Time)
+	return t, ok && !t.IsZero()
+}
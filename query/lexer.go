@@ -0,0 +1,156 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenDuration
+	tokenDate
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column
+}
+
+// SyntaxError reports where a filter expression could not be parsed
+type SyntaxError struct {
+	Pos int // 1-based column
+	Msg string
+}
+
+// -----------------------------------------------------------------------
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+// -----------------------------------------------------------------------
+// Pointer renders the expression with a caret under the error position
+func (e *SyntaxError) Pointer(text string) string {
+	pos := e.Pos
+	if pos < 1 {
+		pos = 1
+	}
+	return text + "\n" + strings.Repeat(" ", pos-1) + "^ " + e.Msg
+}
+
+// -----------------------------------------------------------------------
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// -----------------------------------------------------------------------
+// lex splits a filter expression into tokens
+func lex(text string) ([]token, error) {
+	runes := []rune(text)
+	var tokens []token
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, &SyntaxError{pos, "unterminated string"}
+			}
+			tokens = append(tokens, token{tokenString, b.String(), pos})
+			i = j + 1
+		case strings.ContainsRune("=!~<>", r):
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, &SyntaxError{pos, "unknown operator \"!\", use != or !~"}
+			}
+			tokens = append(tokens, token{tokenOp, op, pos})
+			i += len([]rune(op))
+		case r == '-' || r == '+' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == '-' || unicode.IsLetter(runes[j])) {
+				j++
+			}
+			word := string(runes[i:j])
+			kind, err := classifyLiteral(word)
+			if err != "" {
+				return nil, &SyntaxError{pos, err}
+			}
+			tokens = append(tokens, token{kind, word, pos})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.' || runes[j] == '*') {
+				j++
+			}
+			word := string(runes[i:j])
+			kind := tokenIdent
+			switch strings.ToLower(word) {
+			case "and":
+				kind = tokenAnd
+			case "or":
+				kind = tokenOr
+			case "not":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind, word, pos})
+			i = j
+		default:
+			return nil, &SyntaxError{pos, fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes) + 1}), nil
+}
+
+// -----------------------------------------------------------------------
+// classifyLiteral tells numbers, relative durations like -2d and dates
+// like 2024-05-01 apart
+func classifyLiteral(word string) (tokenKind, string) {
+	if _, err := parseNumber(word); err == nil {
+		return tokenNumber, ""
+	}
+	if _, err := parseDuration(word); err == nil {
+		return tokenDuration, ""
+	}
+	if _, err := parseDate(word); err == nil {
+		return tokenDate, ""
+	}
+	return tokenEOF, fmt.Sprintf("%q is not a number, duration (-2d, 3h) or date (2006-01-02)", word)
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		text  string
+		kinds []tokenKind
+		texts []string
+	}{
+		{`assignee = "ali"`, []tokenKind{tokenIdent, tokenOp, tokenString}, []string{"assignee", "=", "ali"}},
+		{`name ~ 'Rev*'`, []tokenKind{tokenIdent, tokenOp, tokenString}, []string{"name", "~", "Rev*"}},
+		{`created < -2d`, []tokenKind{tokenIdent, tokenOp, tokenDuration}, []string{"created", "<", "-2d"}},
+		{`due >= 2024-05-01`, []tokenKind{tokenIdent, tokenOp, tokenDate}, []string{"due", ">=", "2024-05-01"}},
+		{`priority != 50`, []tokenKind{tokenIdent, tokenOp, tokenNumber}, []string{"priority", "!=", "50"}},
+		{`name !~ "x"`, []tokenKind{tokenIdent, tokenOp, tokenString}, []string{"name", "!~", "x"}},
+		{`not (a = "1" OR b = "2") and c = "3"`,
+			[]tokenKind{tokenNot, tokenLParen, tokenIdent, tokenOp, tokenString, tokenOr, tokenIdent, tokenOp, tokenString, tokenRParen, tokenAnd, tokenIdent, tokenOp, tokenString},
+			[]string{"not", "(", "a", "=", "1", "OR", "b", "=", "2", ")", "and", "c", "=", "3"}},
+		{`name = "say \"hi\""`, []tokenKind{tokenIdent, tokenOp, tokenString}, []string{"name", "=", `say "hi"`}},
+		{`assignee = "علی"`, []tokenKind{tokenIdent, tokenOp, tokenString}, []string{"assignee", "=", "علی"}},
+	}
+	for _, tt := range tests {
+		tokens, err := lex(tt.text)
+		if err != nil {
+			t.Errorf("lex(%q): %v", tt.text, err)
+			continue
+		}
+		if last := tokens[len(tokens)-1]; last.kind != tokenEOF {
+			t.Errorf("lex(%q) does not end with EOF: %v", tt.text, last)
+			continue
+		}
+		var kinds []tokenKind
+		var texts []string
+		for _, tok := range tokens[:len(tokens)-1] {
+			kinds = append(kinds, tok.kind)
+			texts = append(texts, tok.text)
+		}
+		if !reflect.DeepEqual(kinds, tt.kinds) || !reflect.DeepEqual(texts, tt.texts) {
+			t.Errorf("lex(%q) = %v %q, want %v %q", tt.text, kinds, texts, tt.kinds, tt.texts)
+		}
+	}
+}
+
+func TestLexPositions(t *testing.T) {
+	tokens, err := lex(`a = "علی" and b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// columns count runes, not bytes
+	want := []int{1, 3, 5, 11, 15, 16}
+	for i, tok := range tokens {
+		if tok.pos != want[i] {
+			t.Errorf("token %d %q at column %d, want %d", i, tok.text, tok.pos, want[i])
+		}
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		pos  int
+	}{
+		{`name = "open`, 8},
+		{`name ! "x"`, 6},
+		{`name = @`, 8},
+		{`created < -2x`, 11},
+	}
+	for _, tt := range tests {
+		_, err := lex(tt.text)
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("lex(%q) error = %v, want a SyntaxError", tt.text, err)
+			continue
+		}
+		if syntax.Pos != tt.pos {
+			t.Errorf("lex(%q) error at column %d, want %d: %v", tt.text, syntax.Pos, tt.pos, err)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package query
+
+import (
+	"bpmn-manager/models"
+	"bpmn-manager/search"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// node is a parsed filter expression
+type node interface {
+	eval(r Record) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+
+// comparison is "field op value" with the value already converted to
+// the field's kind
+type comparison struct {
+	field  Field
+	op     string
+	text   string
+	number float64
+	time   time.Time
+}
+
+// Query is a compiled filter expression. The zero-text query matches
+// everything.
+type Query struct {
+	Text   string
+	schema *Schema
+	root   node
+}
+
+// -----------------------------------------------------------------------
+func (n andNode) eval(r Record) bool { return n.left.eval(r) && n.right.eval(r) }
+func (n orNode) eval(r Record) bool  { return n.left.eval(r) || n.right.eval(r) }
+func (n notNode) eval(r Record) bool { return !n.inner.eval(r) }
+
+// -----------------------------------------------------------------------
+// Parse compiles a filter expression against a schema. Relative times
+// such as -2d are resolved against the current time.
+func Parse(text string, schema *Schema) (*Query, error) {
+	return parseAt(text, schema, time.Now())
+}
+
+// -----------------------------------------------------------------------
+func parseAt(text string, schema *Schema, now time.Time) (*Query, error) {
+	q := &Query{Text: text, schema: schema}
+	if strings.TrimSpace(text) == "" {
+		return q, nil
+	}
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, schema: schema, now: now}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{t.pos, fmt.Sprintf("unexpected %s, expected and/or", describe(t))}
+	}
+	q.root = root
+	return q, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	schema *Schema
+	now    time.Time
+}
+
+// -----------------------------------------------------------------------
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+// -----------------------------------------------------------------------
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// -----------------------------------------------------------------------
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// -----------------------------------------------------------------------
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+// -----------------------------------------------------------------------
+func (p *parser) parseUnary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNot:
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{closing.pos, fmt.Sprintf("expected ) to close ( at column %d, found %s", t.pos, describe(closing))}
+		}
+		return inner, nil
+	case tokenIdent:
+		return p.parseComparison(t)
+	}
+	return nil, &SyntaxError{t.pos, fmt.Sprintf("expected a field name, found %s", describe(t))}
+}
+
+// -----------------------------------------------------------------------
+func (p *parser) parseComparison(name token) (node, error) {
+	field, ok := p.schema.Field(name.text)
+	if !ok {
+		return nil, &SyntaxError{name.pos, fmt.Sprintf("unknown %s field %q (fields: %s)",
+			p.schema.Name, name.text, strings.Join(p.schema.Names(), ", "))}
+	}
+
+	op := p.next()
+	if op.kind != tokenOp {
+		return nil, &SyntaxError{op.pos, fmt.Sprintf("expected an operator (= != ~ !~ < <= > >=) after %s, found %s", name.text, describe(op))}
+	}
+	if (op.text == "~" || op.text == "!~") && field.Kind != KindString {
+		return nil, &SyntaxError{op.pos, fmt.Sprintf("%s only works on text fields", op.text)}
+	}
+
+	value := p.next()
+	c := comparison{field: field, op: op.text, text: value.text}
+	var err error
+	switch field.Kind {
+	case KindString:
+		if value.kind == tokenIdent {
+			return nil, &SyntaxError{value.pos, fmt.Sprintf("unquoted value %s, write \"%s\"", value.text, value.text)}
+		}
+		if value.kind != tokenString && value.kind != tokenNumber && value.kind != tokenDate {
+			return nil, &SyntaxError{value.pos, fmt.Sprintf("expected a quoted value for %s, found %s", field.Name, describe(value))}
+		}
+		if c.op == "~" || c.op == "!~" {
+			if _, err := path.Match(c.text, ""); err != nil {
+				return nil, &SyntaxError{value.pos, fmt.Sprintf("invalid pattern %q", c.text)}
+			}
+		}
+	case KindNumber:
+		if c.number, err = parseNumber(value.text); err != nil || (value.kind != tokenNumber && value.kind != tokenString) {
+			return nil, &SyntaxError{value.pos, fmt.Sprintf("%s expects a number, found %s", field.Name, describe(value))}
+		}
+	case KindTime:
+		switch value.kind {
+		case tokenDuration:
+			d, _ := parseDuration(value.text)
+			c.time = p.now.Add(d)
+		case tokenDate, tokenString:
+			if c.time, err = parseDate(value.text); err != nil {
+				return nil, &SyntaxError{value.pos, fmt.Sprintf("%s expects a date (2006-01-02) or relative time (-2d), found %s", field.Name, describe(value))}
+			}
+		default:
+			return nil, &SyntaxError{value.pos, fmt.Sprintf("%s expects a date (2006-01-02) or relative time (-2d), found %s", field.Name, describe(value))}
+		}
+	}
+	return c, nil
+}
+
+// -----------------------------------------------------------------------
+func (c comparison) eval(r Record) bool {
+	value := r[c.field.Name]
+	switch c.field.Kind {
+	case KindTime:
+		t, ok := timeValue(value)
+		if !ok {
+			return c.op == "!="
+		}
+		return compare(t.Compare(c.time), c.op)
+	case KindNumber:
+		n, err := parseNumber(fmt.Sprint(value))
+		if err != nil {
+			return c.op == "!="
+		}
+		switch {
+		case n < c.number:
+			return compare(-1, c.op)
+		case n > c.number:
+			return compare(1, c.op)
+		}
+		return compare(0, c.op)
+	}
+
+	// Lists match when any element does; negations when none does
+	if values, ok := value.([]string); ok {
+		positive := c
+		switch c.op {
+		case "!=":
+			positive.op = "="
+		case "!~":
+			positive.op = "~"
+		}
+		for _, v := range values {
+			if positive.matchText(v) {
+				return positive.op == c.op
+			}
+		}
+		return positive.op != c.op
+	}
+	return c.matchText(fmt.Sprint(value))
+}
+
+// -----------------------------------------------------------------------
+func (c comparison) matchText(value string) bool {
+	text := search.Normalize(value)
+	want := search.Normalize(c.text)
+	switch c.op {
+	case "~", "!~":
+		matched, _ := path.Match(want, text)
+		return matched == (c.op == "~")
+	}
+	return compare(strings.Compare(text, want), c.op)
+}
+
+// -----------------------------------------------------------------------
+func compare(order int, op string) bool {
+	switch op {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------
+// Match reports whether a record satisfies the query
+func (q *Query) Match(r Record) bool {
+	return q == nil || q.root == nil || q.root.eval(r)
+}
+
+// -----------------------------------------------------------------------
+func (q *Query) Empty() bool {
+	return q == nil || q.root == nil
+}
+
+// -----------------------------------------------------------------------
+func FilterTasks(q *Query, tasks []models.UserTask) []models.UserTask {
+	if q.Empty() {
+		return tasks
+	}
+	var matched []models.UserTask
+	for _, task := range tasks {
+		if q.Match(TaskRecord(task)) {
+			matched = append(matched, task)
+		}
+	}
+	return matched
+}
+
+// -----------------------------------------------------------------------
+func FilterProcesses(q *Query, processes []models.RunningProcess) []models.RunningProcess {
+	if q.Empty() {
+		return processes
+	}
+	var matched []models.RunningProcess
+	for _, process := range processes {
+		if q.Match(ProcessRecord(process)) {
+			matched = append(matched, process)
+		}
+	}
+	return matched
+}
+
+// -----------------------------------------------------------------------
+// Params translates the comparisons the engine can evaluate into query
+// parameters. Only terms joined by "and" at the top level are translated
+// since the engine ANDs its parameters; the full query must still be
+// applied to the results.
+func (q *Query) Params() url.Values {
+	params, _ := q.ServerSide()
+	return params
+}
+
+// -----------------------------------------------------------------------
+// ServerSide is Params that also reports whether the parameters express
+// the whole query, so results need no client-side filtering. String
+// terms the engine would match differently from Match are left to the
+// client.
+func (q *Query) ServerSide() (url.Values, bool) {
+	params := url.Values{}
+	if q.Empty() {
+		return params, true
+	}
+	complete := true
+	var collect func(n node)
+	collect = func(n node) {
+		switch v := n.(type) {
+		case andNode:
+			collect(v.left)
+			collect(v.right)
+		case comparison:
+			op, bound := v.op, v.time
+			if v.field.Kind == KindTime {
+				op, bound = strictBound(v.op, v.time)
+			}
+			name, ok := v.field.Params[op]
+			if !ok || params.Has(name) || (v.field.Kind == KindString && !engineExact(v.op, v.text)) {
+				complete = false
+				return
+			}
+			switch v.field.Kind {
+			case KindTime:
+				params.Set(name, bound.Format(engineTimeLayout))
+			case KindString:
+				if op == "~" {
+					params.Set(name, strings.NewReplacer("*", "%", "?", "_").Replace(v.text))
+				} else {
+					params.Set(name, v.text)
+				}
+			default:
+				params.Set(name, v.text)
+			}
+		default:
+			complete = false
+		}
+	}
+	collect(q.root)
+	return params, complete
+}
+
+// -----------------------------------------------------------------------
+// engineExact reports whether the engine, which compares text as typed,
+// matches a string comparison exactly like the client, which ignores
+// case and Arabic/Persian letter forms. Patterns must also be free of
+// the SQL wildcards and the glob syntax that have no LIKE equivalent.
+func engineExact(op, text string) bool {
+	if op == "~" && strings.ContainsAny(text, `%_[\`) {
+		return false
+	}
+	return search.Exact(text)
+}
+
+// engineTimeLayout is how times are sent to the engine, which stores them
+// with millisecond precision
+const engineTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// -----------------------------------------------------------------------
+// strictBound turns a time comparison into the strict before/after bound
+// the engine understands. Since the engine keeps milliseconds, "<= t"
+// is "< t+1ms" and ">= t" is "> t-1ms" once t is rounded to a whole
+// millisecond in the direction that keeps the bound exact.
+func strictBound(op string, t time.Time) (string, time.Time) {
+	floor := t.Truncate(time.Millisecond)
+	ceil := floor
+	if ceil.Before(t) {
+		ceil = ceil.Add(time.Millisecond)
+	}
+	switch op {
+	case "<":
+		return "<", ceil
+	case "<=":
+		return "<", floor.Add(time.Millisecond)
+	case ">":
+		return ">", floor
+	case ">=":
+		return ">", ceil.Add(-time.Millisecond)
+	}
+	return op, t
+}
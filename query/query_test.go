@@ -0,0 +1,228 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"bpmn-manager/models"
+)
+
+var testNow = time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+
+func testTask() models.UserTask {
+	return models.UserTask{
+		ID:                "t1",
+		Name:              "Review invoice",
+		Assignee:          "ali",
+		TaskDefinitionKey: "Activity_0abc",
+		Priority:          "50",
+		CreatedAt:         time.Date(2024, 5, 7, 9, 0, 0, 0, time.Local),
+		CandidateGroups:   []string{"finance", "audit"},
+	}
+}
+
+func TestParseAndMatch(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{``, true},
+		{`assignee = "ali"`, true},
+		{`assignee = "ALI"`, true},
+		{`assignee != "ali"`, false},
+		{`name ~ "review*"`, true},
+		{`name !~ "review*"`, false},
+		{`taskDefinitionKey ~ "Activity_0*"`, true},
+		{`key = "Activity_0abc"`, true},
+		{`created < -2d`, true},
+		{`created < -4d`, false},
+		{`created >= 2024-05-07`, true},
+		{`created > 2024-05-08`, false},
+		{`priority >= 50`, true},
+		{`priority > 50`, false},
+		{`candidateGroup = "audit"`, true},
+		{`candidateGroup != "audit"`, false},
+		{`candidateGroup = "hr"`, false},
+		{`due = 2024-05-01`, false},
+		{`due != 2024-05-01`, true},
+		{`assignee = "bob" or name ~ "*invoice"`, true},
+		{`assignee = "bob" or assignee = "sara"`, false},
+		{`not assignee = "bob" and (priority < 10 or created < -1d)`, true},
+		{`NOT (assignee = "ali")`, false},
+	}
+	record := TaskRecord(testTask())
+	for _, tt := range tests {
+		q, err := parseAt(tt.text, TaskSchema, testNow)
+		if err != nil {
+			t.Errorf("parse %q: %v", tt.text, err)
+			continue
+		}
+		if got := q.Match(record); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		pos  int
+	}{
+		{`owner = "ali"`, 1},
+		{`assignee "ali"`, 10},
+		{`assignee = ali`, 12},
+		{`created < "soon"`, 11},
+		{`priority = "high"`, 12},
+		{`created ~ -2d`, 9},
+		{`(assignee = "ali"`, 18},
+		{`assignee = "ali" assignee = "bob"`, 18},
+		{`and assignee = "ali"`, 1},
+		{`name ~ "[a"`, 8},
+	}
+	for _, tt := range tests {
+		_, err := parseAt(tt.text, TaskSchema, testNow)
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("parse %q: error %v, want a SyntaxError", tt.text, err)
+			continue
+		}
+		if syntax.Pos != tt.pos {
+			t.Errorf("parse %q: error at column %d, want %d: %v", tt.text, syntax.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestServerSide(t *testing.T) {
+	day := "2024-05-07T00:00:00.000" + testNow.Format("-0700")
+	tests := []struct {
+		text     string
+		params   url.Values
+		complete bool
+	}{
+		{``, url.Values{}, true},
+		// only text without case or letter variants is sent as typed
+		{`assignee = "王"`, url.Values{"assignee": {"王"}}, true},
+		{`name ~ "審?*"`, url.Values{"nameLike": {"審_%"}}, true},
+		{`assignee = "王" and key = "甲"`, url.Values{"assignee": {"王"}, "taskDefinitionKey": {"甲"}}, true},
+		{`assignee = "王" or key = "甲"`, url.Values{}, false},
+		{`assignee = "王" and not key = "甲"`, url.Values{"assignee": {"王"}}, false},
+		{`status = "open"`, url.Values{}, false},
+		{`assignee = "王" and assignee = "李"`, url.Values{"assignee": {"王"}}, false},
+		{`assignee = "ali"`, url.Values{}, false},
+		{`key ~ "activity_0*"`, url.Values{}, false},
+		{`name = "كتاب"`, url.Values{}, false},
+		{`key ~ "王_*"`, url.Values{}, false},
+		{`created < 2024-05-07`, url.Values{"createdBefore": {day}}, true},
+		{`created > 2024-05-07`, url.Values{"createdAfter": {day}}, true},
+		// inclusive bounds move by the engine's millisecond precision
+		{`created <= 2024-05-07`, url.Values{"createdBefore": {"2024-05-07T00:00:00.001" + testNow.Format("-0700")}}, true},
+		{`created >= 2024-05-07`, url.Values{"createdAfter": {"2024-05-06T23:59:59.999" + testNow.Format("-0700")}}, true},
+	}
+	for _, tt := range tests {
+		q, err := parseAt(tt.text, TaskSchema, testNow)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.text, err)
+		}
+		params, complete := q.ServerSide()
+		if !reflect.DeepEqual(params, tt.params) || complete != tt.complete {
+			t.Errorf("%q: ServerSide() = %v, %v; want %v, %v", tt.text, params, complete, tt.params, tt.complete)
+		}
+	}
+}
+
+func TestStrictBound(t *testing.T) {
+	base := time.Date(2024, 5, 7, 10, 0, 0, 0, time.UTC)
+	fraction := base.Add(1500 * time.Microsecond) // 1.5ms past base
+	ms := time.Millisecond
+	tests := []struct {
+		op     string
+		t      time.Time
+		wantOp string
+		want   time.Time
+	}{
+		{"<", base, "<", base},
+		{"<", fraction, "<", base.Add(2 * ms)},
+		{"<=", base, "<", base.Add(ms)},
+		{"<=", fraction, "<", base.Add(2 * ms)},
+		{">", base, ">", base},
+		{">", fraction, ">", base.Add(ms)},
+		{">=", base, ">", base.Add(-ms)},
+		{">=", fraction, ">", base.Add(ms)},
+	}
+	for _, tt := range tests {
+		op, got := strictBound(tt.op, tt.t)
+		if op != tt.wantOp || !got.Equal(tt.want) {
+			t.Errorf("strictBound(%s, %v) = %s %v, want %s %v", tt.op, tt.t, op, got, tt.wantOp, tt.want)
+		}
+	}
+}
+
+func TestServerSideAgreesWithMatch(t *testing.T) {
+	// a record exactly on an inclusive bound must pass both the engine
+	// parameter and the client-side matcher
+	created := time.Date(2024, 5, 7, 0, 0, 0, 0, time.Local)
+	for _, text := range []string{`created <= 2024-05-07`, `created >= 2024-05-07`} {
+		q, err := parseAt(text, TaskSchema, testNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Match(Record{"created": created}) {
+			t.Errorf("%q does not match its bound", text)
+		}
+		params, _ := q.ServerSide()
+		for name, values := range params {
+			bound, err := time.Parse(engineTimeLayout, values[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (name == "createdBefore" && !created.Before(bound)) || (name == "createdAfter" && !created.After(bound)) {
+				t.Errorf("%q: engine parameter %s=%s excludes the bound", text, name, values[0])
+			}
+		}
+	}
+
+	// whenever a text term is sent to the engine, which compares as
+	// typed, it must keep exactly the values the client-side matcher does
+	values := []string{"Activity_0abc", "activity_0abc", "Activity_1", "کتاب", "كتاب", "王", "王二", "王_二", "۱۲", "12"}
+	for _, text := range []string{
+		`key = "Activity_0abc"`, `key ~ "activity_0*"`, `key ~ "*_1"`, `key = "12"`,
+		`name = "كتاب"`, `name = "کتاب"`, `name = "王"`, `name ~ "王*"`, `name ~ "王?二"`,
+	} {
+		q, err := parseAt(text, TaskSchema, testNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params, complete := q.ServerSide()
+		if !complete {
+			continue
+		}
+		for name, sent := range params {
+			like := strings.HasSuffix(name, "Like")
+			field := "name"
+			if strings.HasPrefix(name, "taskDefinitionKey") {
+				field = "taskDefinitionKey"
+			}
+			for _, value := range values {
+				engine := value == sent[0]
+				if like {
+					engine = likeMatch(sent[0], value)
+				}
+				if client := q.Match(Record{field: value}); client != engine {
+					t.Errorf("%q on %q: engine %s=%s matches %v, client %v", text, value, name, sent[0], engine, client)
+				}
+			}
+		}
+	}
+}
+
+// likeMatch evaluates a SQL LIKE pattern as the engine does
+func likeMatch(pattern, value string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.NewReplacer("%", ".*", "_", ".").Replace(expr)
+	return regexp.MustCompile("^" + expr + "$").MatchString(value)
+}
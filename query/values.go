@@ -0,0 +1,48 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationUnits are the units accepted in relative times like -2d
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// -----------------------------------------------------------------------
+func parseNumber(text string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(text), 64)
+}
+
+// -----------------------------------------------------------------------
+// parseDuration reads a signed count followed by a unit, e.g. -2d or 90m
+func parseDuration(text string) (time.Duration, error) {
+	if len(text) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	unit, ok := durationUnits[strings.ToLower(text[len(text)-1:])]
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	count, err := strconv.Atoi(strings.TrimPrefix(text[:len(text)-1], "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+// -----------------------------------------------------------------------
+func parseDate(text string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", text)
+}
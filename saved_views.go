@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Tables a saved view can target
+const (
+	viewTasks     = "tasks"
+	viewCompleted = "completed"
+	viewInstances = "instances"
+)
+
+// viewsShortcut opens the view manager from the navigation panel
+const viewsShortcut = 'w'
+
+// -----------------------------------------------------------------------
+// viewsDir returns where saved views are stored
+func viewsDir() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "bpmn-manager")
+	}
+	return "data"
+}
+
+// -----------------------------------------------------------------------
+// attachViews restores the remembered layout of a table, lets it save
+// its configuration as a view and applies the view being opened, if it
+// targets this table
+func (m *BPMNManager) attachViews(table *dataTable, target string) {
+	if layout, err := m.storage.LoadLayout(target); err == nil {
+		table.applyLayout(*layout)
+	}
+	table.onLayout = func(layout models.TableLayout) {
+		m.storage.SaveLayout(target, layout)
+	}
+	table.onChoose = func() {
+		m.showColumnChooser(table)
+	}
+	table.onSave = func() {
+		m.showSaveViewForm(table, target)
+	}
+	if m.pendingView != nil && m.pendingView.Target == target {
+		table.applyView(m.pendingView)
+		m.pendingView = nil
+	}
+}
+
+// -----------------------------------------------------------------------
+// showColumnChooser shows and hides table columns and changes their
+// order. Changes apply immediately.
+func (m *BPMNManager) showColumnChooser(table *dataTable) {
+	order := append([]int(nil), table.shownColumns()...)
+	shown := make(map[int]bool, len(order))
+	for _, i := range order {
+		shown[i] = true
+	}
+	for i := range table.columns {
+		if !shown[i] {
+			order = append(order, i)
+		}
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	apply := func() {
+		var columns []int
+		for _, i := range order {
+			if shown[i] {
+				columns = append(columns, i)
+			}
+		}
+		table.setShown(columns)
+	}
+	rebuild := func(current int) {
+		list.Clear()
+		for _, i := range order {
+			mark := "[ ]"
+			if shown[i] {
+				mark = "[x]"
+			}
+			label := strings.Trim(table.columns[i].Header, "| ")
+			list.AddItem(tview.Escape(mark)+" "+label, "", 0, nil)
+		}
+		list.SetCurrentItem(current)
+	}
+
+	list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		column := order[index]
+		if shown[column] && len(table.shownColumns()) == 1 {
+			return
+		}
+		shown[column] = !shown[column]
+		apply()
+		rebuild(index)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		switch event.Rune() {
+		case 'K':
+			if index > 0 {
+				order[index-1], order[index] = order[index], order[index-1]
+				apply()
+				rebuild(index - 1)
+			}
+			return nil
+		case 'J':
+			if index < len(order)-1 {
+				order[index+1], order[index] = order[index], order[index+1]
+				apply()
+				rebuild(index + 1)
+			}
+			return nil
+		}
+		return event
+	})
+	rebuild(0)
+
+	footer := tview.NewTextView().SetText("Enter show/hide • K/J move up/down • Esc done")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(" Columns • " + table.title + " ").SetBorderColor(tcell.Color102)
+	// Esc returns to the page the table is on
+	back, _ := m.pages.GetFrontPage()
+	m.backOnEsc(layout, back)
+
+	m.pages.AddPage("columns", layout, true, true)
+	m.pages.SwitchToPage("columns")
+}
+
+// -----------------------------------------------------------------------
+// openView shows the table a view targets and applies the view to it
+func (m *BPMNManager) openView(view *models.SavedView) {
+	m.pages.SwitchToPage("main")
+	m.pendingView = view
+	switch view.Target {
+	case viewTasks:
+		m.showDashboard()
+	case viewCompleted:
+		m.showCompletedTaskDetails()
+	case viewInstances:
+		m.createRunningProcesses()
+	default:
+		m.pendingView = nil
+		m.showError(fmt.Sprintf("View %q targets unknown table %q", view.Name, view.Target))
+	}
+}
+
+// -----------------------------------------------------------------------
+// refreshViewNav rebuilds the "Views" section at the end of the
+// navigation panel
+func (m *BPMNManager) refreshViewNav(nav *tview.List) {
+	for nav.GetItemCount() > m.navBase {
+		nav.RemoveItem(nav.GetItemCount() - 1)
+	}
+
+	nav.AddItem("🗂  Views", "Manage, import and export views", viewsShortcut, func() {
+		m.showViewManager()
+	})
+	views, _ := m.storage.ListViews()
+	for _, view := range views {
+		view := view
+		var shortcut rune
+		if view.Hotkey != "" {
+			shortcut = []rune(view.Hotkey)[0]
+		}
+		nav.AddItem("   👁  "+tview.Escape(view.Name), "   "+view.Target, shortcut, func() {
+			m.openView(view)
+		})
+	}
+}
+
+// -----------------------------------------------------------------------
+// describeView summarises a view configuration on a few lines
+func describeView(view models.SavedView) string {
+	var sortKeys []string
+	for _, key := range view.Sort {
+		direction := "asc"
+		if key.Descending {
+			direction = "desc"
+		}
+		sortKeys = append(sortKeys, key.Column+" "+direction)
+	}
+	orNone := func(text string) string {
+		if text == "" {
+			return "—"
+		}
+		return text
+	}
+	return fmt.Sprintf("Filter:  %s\nSearch:  %s\nSort:    %s\nColumns: %s",
+		orNone(view.Filter), orNone(view.Search), orNone(strings.Join(sortKeys, ", ")), orNone(strings.Join(view.Columns, ", ")))
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showSaveViewForm(table *dataTable, target string) {
+	view := table.view()
+	view.Target = target
+
+	form := tview.NewForm().
+		AddInputField("Name", "", 40, nil, nil).
+		AddInputField("Hotkey", "", 3, func(text string, last rune) bool {
+			return len([]rune(text)) <= 1
+		}, nil).
+		AddTextView("Table", target, 40, 1, false, false).
+		AddTextView("View", tview.Escape(describeView(view)), 60, 4, false, false)
+
+	form.AddButton("Save", func() {
+		view.Name = strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		view.Hotkey = strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		if view.Name == "" {
+			m.showError("View name is required")
+			return
+		}
+		if err := m.storage.CheckHotkeys([]*models.SavedView{&view}, m.navKeys); err != nil {
+			m.showError(err.Error())
+			return
+		}
+		if err := m.storage.SaveView(&view); err != nil {
+			m.showError("Failed to save view: " + err.Error())
+			return
+		}
+		m.refreshViewNav(m.nav)
+		m.showMessage(fmt.Sprintf("View %q saved", view.Name))
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("main")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	form.SetBorder(true).SetTitle(" Save View ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("view_save", form, true, true)
+	m.pages.SwitchToPage("view_save")
+}
+
+// -----------------------------------------------------------------------
+// showViewManager lists the saved views for opening, deleting and
+// sharing them as JSON
+func (m *BPMNManager) showViewManager() {
+	views, err := m.storage.ListViews()
+	if err != nil {
+		m.showError("Failed to load views: " + err.Error())
+		return
+	}
+
+	list := tview.NewList()
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	details := tview.NewTextView().SetDynamicColors(false).SetWordWrap(true)
+	details.SetBorder(true).SetTitle(" View ").SetBorderColor(tcell.Color102)
+
+	for _, view := range views {
+		view := view
+		title := view.Name
+		if view.Hotkey != "" {
+			title += " [" + view.Hotkey + "]"
+		}
+		list.AddItem(tview.Escape(title), view.Target, 0, func() {
+			m.openView(view)
+		})
+	}
+	list.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if index < len(views) {
+			details.SetText(describeView(*views[index]))
+		}
+	})
+	if len(views) > 0 {
+		details.SetText(describeView(*views[0]))
+	} else {
+		details.SetText("No saved views yet. Press S in a task or process table to save one.")
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'd':
+			index := list.GetCurrentItem()
+			if index >= len(views) {
+				return nil
+			}
+			if err := m.storage.DeleteView(views[index].Name); err != nil {
+				m.showError("Failed to delete view: " + err.Error())
+				return nil
+			}
+			m.refreshViewNav(m.nav)
+			m.showViewManager()
+			return nil
+		case 'x':
+			m.showViewFileForm("Export Views", func(path string) (string, error) {
+				n, err := m.storage.ExportViews(path)
+				return fmt.Sprintf("%d view(s) exported to %s", n, path), err
+			})
+			return nil
+		case 'i':
+			m.showViewFileForm("Import Views", func(path string) (string, error) {
+				n, err := m.storage.ImportViews(path, m.navKeys)
+				m.refreshViewNav(m.nav)
+				return fmt.Sprintf("%d view(s) imported from %s", n, path), err
+			})
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Enter open • d delete • x export all • i import • Esc back")
+	body := tview.NewFlex().
+		AddItem(list, 0, 1, true).
+		AddItem(details, 0, 1, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(fmt.Sprintf(" Saved Views (%d) ", len(views))).SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, "main")
+
+	m.pages.AddPage("views", layout, true, true)
+	m.pages.SwitchToPage("views")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showViewFileForm(title string, run func(path string) (string, error)) {
+	form := tview.NewForm().
+		AddInputField("File", "bpmn-views.json", 50, nil, nil)
+	form.AddButton("OK", func() {
+		message, err := run(strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()))
+		if err != nil {
+			m.showError(err.Error())
+			return
+		}
+		m.showMessage(message)
+	}).
+		AddButton("Cancel", func() {
+			m.showViewManager()
+		})
+	form.SetCancelFunc(m.showViewManager)
+	form.SetBorder(true).SetTitle(" " + title + " ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("views_file", form, true, true)
+	m.pages.SwitchToPage("views_file")
+}
@@ -0,0 +1,165 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// zwnj is the zero width non-joiner used inside Persian words
+const zwnj = '‌'
+
+// runeFold maps Arabic letter forms onto their Persian equivalents so
+// text typed on either keyboard layout matches
+var runeFold = map[rune]rune{
+	'ي': 'ی',
+	'ى': 'ی',
+	'ك': 'ک',
+	'ة': 'ه',
+	'أ': 'ا',
+	'إ': 'ا',
+	'آ': 'ا',
+	'٠': '0', '١': '1', '٢': '2', '٣': '3', '٤': '4',
+	'٥': '5', '٦': '6', '٧': '7', '٨': '8', '٩': '9',
+	'۰': '0', '۱': '1', '۲': '2', '۳': '3', '۴': '4',
+	'۵': '5', '۶': '6', '۷': '7', '۸': '8', '۹': '9',
+}
+
+// -----------------------------------------------------------------------
+// Normalize folds case, Arabic/Persian letter variants and digits and
+// drops zero width non-joiners
+func Normalize(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if n, keep := fold(r); keep {
+			b.WriteRune(n)
+		}
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+// Exact reports whether text is the only string that normalizes to
+// Normalize(text), so comparing it exactly matches the same values as
+// comparing normalized text. Arabic script is excluded as a whole since
+// zero width non-joiners may sit between its letters.
+func Exact(text string) bool {
+	for _, r := range text {
+		if r == zwnj || unicode.SimpleFold(r) != r || unicode.Is(unicode.Arabic, r) {
+			return false
+		}
+		if _, ok := runeFold[r]; ok {
+			return false
+		}
+		for _, folded := range runeFold {
+			if r == folded {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------
+// fold normalizes one rune, returning 0 for runes that are dropped
+func fold(r rune) (rune, bool) {
+	if r == zwnj {
+		return 0, false
+	}
+	if mapped, ok := runeFold[r]; ok {
+		return mapped, true
+	}
+	return unicode.ToLower(r), true
+}
+
+// -----------------------------------------------------------------------
+// Match fuzzily matches pattern against text: every rune of the pattern
+// must appear in order. It returns the rune positions in text that
+// matched and a score where consecutive and word-start matches rank
+// higher.
+func Match(pattern, text string) (bool, int, []int) {
+	want := []rune(Normalize(pattern))
+	if len(want) == 0 {
+		return true, 0, nil
+	}
+
+	runes := []rune(text)
+	var positions []int
+	score, next, last := 0, 0, -2
+	for i, r := range runes {
+		if next == len(want) {
+			break
+		}
+		folded, keep := fold(r)
+		if !keep || folded != want[next] {
+			continue
+		}
+		score++
+		if i == last+1 {
+			score += 3
+		}
+		if i == 0 || unicode.IsSpace(runes[i-1]) || unicode.IsPunct(runes[i-1]) {
+			score += 2
+		}
+		positions = append(positions, i)
+		last = i
+		next++
+	}
+	if next < len(want) {
+		return false, 0, nil
+	}
+	return true, score, positions
+}
+
+// Result is a matching row with the matched rune positions per column
+type Result struct {
+	Row       int
+	Score     int
+	Positions map[int][]int
+}
+
+// -----------------------------------------------------------------------
+// Rows matches a query against table rows. Every whitespace separated
+// term of the query must match at least one column; the results keep the
+// original row order.
+func Rows(query string, rows [][]string) []Result {
+	terms := strings.Fields(query)
+	var results []Result
+	for i, row := range rows {
+		result := Result{Row: i, Positions: map[int][]int{}}
+		matched := true
+		for _, term := range terms {
+			best, bestColumn := -1, -1
+			var bestPositions []int
+			for column, text := range row {
+				if ok, score, positions := Match(term, text); ok && score > best {
+					best, bestColumn, bestPositions = score, column, positions
+				}
+			}
+			if bestColumn < 0 {
+				matched = false
+				break
+			}
+			result.Score += best
+			result.Positions[bestColumn] = mergePositions(result.Positions[bestColumn], bestPositions)
+		}
+		if matched {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// -----------------------------------------------------------------------
+func mergePositions(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	var merged []int
+	for _, p := range append(append([]int(nil), a...), b...) {
+		if !seen[p] {
+			seen[p] = true
+			merged = append(merged, p)
+		}
+	}
+	sort.Ints(merged)
+	return merged
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Review Invoice", "review invoice"},
+		{"كتاب", "کتاب"},
+		{"علي", "علی"},
+		{"آب", "اب"},
+		{"می‌خواهم", "میخواهم"},
+		{"۱۴۰۳", "1403"},
+		{"١٢", "12"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.text); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		ok            bool
+		positions     []int
+	}{
+		{"", "anything", true, nil},
+		{"rvw", "Review", true, []int{0, 2, 5}},
+		{"INV", "Approve invoice", true, []int{8, 9, 10}},
+		{"cab", "abc", false, nil},
+		{"كتاب", "کتاب", true, []int{0, 1, 2, 3}},
+		{"12", "Task ۱۲", true, []int{5, 6}},
+		{"ab", "a‌b", true, []int{0, 2}},
+	}
+	for _, tt := range tests {
+		ok, _, positions := Match(tt.pattern, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("Match(%q, %q) = %v, %v; want %v, %v", tt.pattern, tt.text, ok, positions, tt.ok, tt.positions)
+		}
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	// consecutive and word-start matches rank above scattered ones
+	tests := []struct {
+		pattern, better, worse string
+	}{
+		{"inv", "invoice", "in review"},
+		{"rev", "the review", "three vote"},
+		{"ap", "approve", "a map"},
+	}
+	for _, tt := range tests {
+		_, better, _ := Match(tt.pattern, tt.better)
+		_, worse, _ := Match(tt.pattern, tt.worse)
+		if better <= worse {
+			t.Errorf("%q scores %d on %q and %d on %q", tt.pattern, better, tt.better, worse, tt.worse)
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows := [][]string{
+		{"t1", "Review invoice", "ali"},
+		{"t2", "Approve order", "sara"},
+		{"t3", "Review order", "ali"},
+	}
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2}},
+		{"review", []int{0, 2}},
+		{"order ali", []int{2}},
+		{"ALI", []int{0, 2}},
+		{"bob", nil},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, result := range Rows(tt.query, rows) {
+			got = append(got, result.Row)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Rows(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	results := Rows("rev ali", rows)
+	if want := map[int][]int{1: {0, 1, 2}, 2: {0, 1, 2}}; !reflect.DeepEqual(results[0].Positions, want) {
+		t.Errorf("positions = %v, want %v", results[0].Positions, want)
+	}
+}
@@ -0,0 +1,231 @@
+package sla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// Status is the SLA state of a task
+type Status int
+
+const (
+	StatusNone    Status = iota // no due date and no target
+	StatusOK                    // on time
+	StatusWarning               // most of the allowed time is used up
+	StatusOverdue               // past its deadline
+)
+
+// -----------------------------------------------------------------------
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusWarning:
+		return "warning"
+	case StatusOverdue:
+		return "overdue"
+	}
+	return "none"
+}
+
+// Duration is a time.Duration that reads "90m", "8h" or "3d" from JSON
+type Duration time.Duration
+
+// -----------------------------------------------------------------------
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(formatDuration(time.Duration(d)))
+}
+
+// -----------------------------------------------------------------------
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Config holds the target durations per task definition key. A task is
+// due Target after it was created unless it has its own due date.
+type Config struct {
+	Default Duration            `json:"default,omitempty"` // zero: only keys listed in Targets
+	Targets map[string]Duration `json:"targets"`
+	WarnAt  float64             `json:"warnAt,omitempty"` // fraction of the time used, default 0.8
+
+	Clock calendar.Clock `json:"-"` // nil counts wall-clock time
+}
+
+// Evaluation is the SLA state of one task at a point in time
+type Evaluation struct {
+	Task      models.UserTask
+	Status    Status
+	Deadline  time.Time
+	Remaining time.Duration // negative when overdue
+	Target    time.Duration // zero when the deadline is the task's due date
+}
+
+// -----------------------------------------------------------------------
+// ParseDuration reads a duration in Go syntax or as a count of days,
+// e.g. "2d" or "1d12h"
+func ParseDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	var days time.Duration
+	if i := strings.Index(text, "d"); i > 0 {
+		n, err := strconv.Atoi(text[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		text = text[i+1:]
+		if text == "" {
+			return days, nil
+		}
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	return days + d, nil
+}
+
+// -----------------------------------------------------------------------
+func formatDuration(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// -----------------------------------------------------------------------
+// Load reads a config file. A missing file yields an empty config, in
+// which only tasks with a due date are tracked.
+func Load(path string) (*Config, error) {
+	config := &Config{Targets: map[string]Duration{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid SLA config %s: %w", path, err)
+	}
+	if config.WarnAt < 0 || config.WarnAt >= 1 {
+		return nil, fmt.Errorf("invalid SLA config %s: warnAt must be between 0 and 1", path)
+	}
+	return config, nil
+}
+
+// -----------------------------------------------------------------------
+func (c *Config) clock() calendar.Clock {
+	if c.Clock == nil {
+		return calendar.WallClock{}
+	}
+	return c.Clock
+}
+
+// -----------------------------------------------------------------------
+// Target returns the target duration of a task definition key, zero if
+// it has none
+func (c *Config) Target(taskDefinitionKey string) time.Duration {
+	if target, ok := c.Targets[taskDefinitionKey]; ok {
+		return time.Duration(target)
+	}
+	return time.Duration(c.Default)
+}
+
+// -----------------------------------------------------------------------
+// Evaluate computes the deadline and remaining time of a task
+func (c *Config) Evaluate(task models.UserTask, now time.Time) Evaluation {
+	e := Evaluation{Task: task}
+	clock := c.clock()
+
+	var allowed time.Duration
+	switch {
+	case !task.DueDate.IsZero():
+		e.Deadline = task.DueDate
+		if !task.CreatedAt.IsZero() {
+			allowed = clock.Between(task.CreatedAt, task.DueDate)
+		}
+	case c.Target(task.TaskDefinitionKey) > 0 && !task.CreatedAt.IsZero():
+		e.Target = c.Target(task.TaskDefinitionKey)
+		e.Deadline = clock.Add(task.CreatedAt, e.Target)
+		allowed = e.Target
+	default:
+		return e
+	}
+
+	// a deadline passed outside working hours leaves no working time
+	// behind it, so lateness is decided on the wall clock
+	overdue := now.After(e.Deadline)
+	e.Remaining = clock.Between(now, e.Deadline)
+	if overdue && e.Remaining >= 0 {
+		e.Remaining = e.Deadline.Sub(now)
+	}
+	warnAt := c.WarnAt
+	if warnAt == 0 {
+		warnAt = 0.8
+	}
+	switch {
+	case overdue:
+		e.Status = StatusOverdue
+	case allowed > 0 && float64(allowed-e.Remaining) >= warnAt*float64(allowed):
+		e.Status = StatusWarning
+	default:
+		e.Status = StatusOK
+	}
+	return e
+}
+
+// -----------------------------------------------------------------------
+// EvaluateAll evaluates tasks in order
+func (c *Config) EvaluateAll(tasks []models.UserTask, now time.Time) []Evaluation {
+	evaluations := make([]Evaluation, len(tasks))
+	for i, task := range tasks {
+		evaluations[i] = c.Evaluate(task, now)
+	}
+	return evaluations
+}
+
+// -----------------------------------------------------------------------
+// Breaches returns the overdue tasks, and the ones at risk when warnings
+// is set, most overdue first
+func (c *Config) Breaches(tasks []models.UserTask, now time.Time, warnings bool) []Evaluation {
+	var breaches []Evaluation
+	for _, e := range c.EvaluateAll(tasks, now) {
+		if e.Status == StatusOverdue || (warnings && e.Status == StatusWarning) {
+			breaches = append(breaches, e)
+		}
+	}
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].Remaining < breaches[j].Remaining
+	})
+	return breaches
+}
+
+// -----------------------------------------------------------------------
+// Overdue counts the overdue tasks
+func (c *Config) Overdue(tasks []models.UserTask, now time.Time) int {
+	n := 0
+	for _, task := range tasks {
+		if c.Evaluate(task, now).Status == StatusOverdue {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,150 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+var created = time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+
+func TestEvaluate(t *testing.T) {
+	config := &Config{
+		Default: Duration(8 * time.Hour),
+		Targets: map[string]Duration{"review": Duration(2 * time.Hour), "untracked": 0},
+	}
+	tests := []struct {
+		name      string
+		task      models.UserTask
+		now       time.Time
+		status    Status
+		deadline  time.Time
+		remaining time.Duration
+		target    time.Duration
+	}{
+		{"default target", models.UserTask{TaskDefinitionKey: "other", CreatedAt: created},
+			created.Add(time.Hour), StatusOK, created.Add(8 * time.Hour), 7 * time.Hour, 8 * time.Hour},
+		{"key target", models.UserTask{TaskDefinitionKey: "review", CreatedAt: created},
+			created.Add(time.Hour), StatusOK, created.Add(2 * time.Hour), time.Hour, 2 * time.Hour},
+		{"warning at 80%", models.UserTask{TaskDefinitionKey: "review", CreatedAt: created},
+			created.Add(96 * time.Minute), StatusWarning, created.Add(2 * time.Hour), 24 * time.Minute, 2 * time.Hour},
+		{"on the deadline", models.UserTask{TaskDefinitionKey: "review", CreatedAt: created},
+			created.Add(2 * time.Hour), StatusWarning, created.Add(2 * time.Hour), 0, 2 * time.Hour},
+		{"overdue", models.UserTask{TaskDefinitionKey: "review", CreatedAt: created},
+			created.Add(3 * time.Hour), StatusOverdue, created.Add(2 * time.Hour), -time.Hour, 2 * time.Hour},
+		{"due date wins", models.UserTask{TaskDefinitionKey: "review", CreatedAt: created, DueDate: created.Add(10 * time.Hour)},
+			created.Add(3 * time.Hour), StatusOK, created.Add(10 * time.Hour), 7 * time.Hour, 0},
+		{"due date without creation", models.UserTask{DueDate: created},
+			created.Add(time.Minute), StatusOverdue, created, -time.Minute, 0},
+		{"zero key target", models.UserTask{TaskDefinitionKey: "untracked", CreatedAt: created},
+			created.Add(30 * 24 * time.Hour), StatusNone, time.Time{}, 0, 0},
+		{"no creation time", models.UserTask{TaskDefinitionKey: "review"},
+			created, StatusNone, time.Time{}, 0, 0},
+	}
+	for _, tt := range tests {
+		e := config.Evaluate(tt.task, tt.now)
+		if e.Status != tt.status || !e.Deadline.Equal(tt.deadline) || e.Remaining != tt.remaining || e.Target != tt.target {
+			t.Errorf("%s: got %v deadline %v remaining %v target %v; want %v deadline %v remaining %v target %v",
+				tt.name, e.Status, e.Deadline, e.Remaining, e.Target, tt.status, tt.deadline, tt.remaining, tt.target)
+		}
+	}
+}
+
+func TestEvaluateWarnAt(t *testing.T) {
+	config := &Config{Default: Duration(10 * time.Hour), WarnAt: 0.5}
+	task := models.UserTask{CreatedAt: created}
+	if s := config.Evaluate(task, created.Add(4*time.Hour)).Status; s != StatusOK {
+		t.Errorf("40%% used: %v, want ok", s)
+	}
+	if s := config.Evaluate(task, created.Add(5*time.Hour)).Status; s != StatusWarning {
+		t.Errorf("50%% used: %v, want warning", s)
+	}
+}
+
+func TestEvaluateBusinessTime(t *testing.T) {
+	// Monday to Friday 09:00–17:00; created is Monday 09:00
+	clock := &calendar.Calendar{Location: time.UTC}
+	for day := time.Monday; day <= time.Friday; day++ {
+		clock.Hours[day] = []calendar.Span{{Start: 9 * time.Hour, End: 17 * time.Hour}}
+	}
+	config := &Config{Default: Duration(40 * time.Hour), Clock: clock}
+	task := models.UserTask{CreatedAt: created}
+	friday := created.AddDate(0, 0, 4)
+	saturday := created.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name      string
+		task      models.UserTask
+		now       time.Time
+		status    Status
+		remaining time.Duration
+	}{
+		{"working time left", task, created.AddDate(0, 0, 1), StatusOK, 32 * time.Hour},
+		{"weekend before the deadline", task, created.AddDate(0, 0, -1), StatusOK, 40 * time.Hour},
+		{"overdue in working time", task, created.AddDate(0, 0, 7).Add(2 * time.Hour), StatusOverdue, -2 * time.Hour},
+		// no working time passes between a deadline on Friday evening and
+		// Saturday, yet the task is late
+		{"overdue at the weekend", task, saturday.Add(3 * time.Hour), StatusOverdue, -19 * time.Hour},
+		{"due date passed at the weekend", models.UserTask{CreatedAt: created, DueDate: saturday},
+			saturday.Add(time.Hour), StatusOverdue, -time.Hour},
+	}
+	for _, tt := range tests {
+		e := config.Evaluate(tt.task, tt.now)
+		if e.Status != tt.status || e.Remaining != tt.remaining {
+			t.Errorf("%s: got %v remaining %v, want %v remaining %v", tt.name, e.Status, e.Remaining, tt.status, tt.remaining)
+		}
+	}
+	if e := config.Evaluate(task, friday.Add(17*time.Hour)); !e.Deadline.Equal(friday.Add(8 * time.Hour)) {
+		t.Errorf("deadline = %v, want Friday 17:00", e.Deadline)
+	}
+}
+
+func TestBreaches(t *testing.T) {
+	config := &Config{Default: Duration(time.Hour)}
+	tasks := []models.UserTask{
+		{ID: "ok", CreatedAt: created.Add(2 * time.Hour)},
+		{ID: "late", CreatedAt: created},
+		{ID: "later", CreatedAt: created.Add(-time.Hour)},
+		{ID: "warning", CreatedAt: created.Add(70 * time.Minute)},
+	}
+	now := created.Add(2 * time.Hour)
+
+	var ids []string
+	for _, e := range config.Breaches(tasks, now, false) {
+		ids = append(ids, e.Task.ID)
+	}
+	if len(ids) != 2 || ids[0] != "later" || ids[1] != "late" {
+		t.Errorf("Breaches = %v, want [later late]", ids)
+	}
+	if n := len(config.Breaches(tasks, now, true)); n != 3 {
+		t.Errorf("Breaches with warnings = %d tasks, want 3", n)
+	}
+	if n := config.Overdue(tasks, now); n != 2 {
+		t.Errorf("Overdue = %d, want 2", n)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"90m", 90 * time.Minute},
+		{"8h", 8 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.text, got, err, tt.want)
+		}
+	}
+	for _, text := range []string{"", "xd", "3 days", "d"} {
+		if _, err := ParseDuration(text); err == nil {
+			t.Errorf("ParseDuration(%q) accepted", text)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"bpmn-manager/models"
+	"bpmn-manager/sla"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// -----------------------------------------------------------------------
+// slaConfigPath returns where the SLA targets are configured
+func slaConfigPath() string {
+	return filepath.Join(viewsDir(), "sla.json")
+}
+
+// -----------------------------------------------------------------------
+// loadSLA reads the SLA targets. A broken file disables the targets but
+// keeps tracking due dates; the error is shown on the dashboard.
+func loadSLA() (*sla.Config, error) {
+	config, err := sla.Load(slaConfigPath())
+	if err != nil {
+		return &sla.Config{}, err
+	}
+	return config, nil
+}
+
+// -----------------------------------------------------------------------
+// slaText describes the remaining time of a task for the SLA column
+func slaText(e sla.Evaluation) string {
+	switch e.Status {
+	case sla.StatusNone:
+		return ""
+	case sla.StatusOverdue:
+		return "overdue " + formatAge(-e.Remaining)
+	}
+	return formatAge(e.Remaining) + " left"
+}
+
+// -----------------------------------------------------------------------
+// slaColor returns the row color of a task, zero when it is on time
+func slaColor(e sla.Evaluation) tcell.Color {
+	switch e.Status {
+	case sla.StatusOverdue:
+		return tcell.ColorRed
+	case sla.StatusWarning:
+		return tcell.ColorOrange
+	}
+	return 0
+}
+
+// -----------------------------------------------------------------------
+// taskSLARow is taskRow with the SLA column filled in
+func (m *BPMNManager) taskSLARow(task models.UserTask) []string {
+	return append(taskRow(task), slaText(m.sla.Evaluate(task, time.Now())))
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) taskSLAColor(task models.UserTask) tcell.Color {
+	return slaColor(m.sla.Evaluate(task, time.Now()))
+}
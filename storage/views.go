@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func viewFileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	return "view_" + slug + ".json"
+}
+
+// viewPath returns the file of the named view. Different names can share
+// a file, e.g. "a b" and "a_b", so a file holding another view is an
+// error rather than being overwritten or deleted.
+func (s *Storage) viewPath(name string) (string, error) {
+	path := filepath.Join(s.dataDir, viewFileName(name))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var existing models.SavedView
+	if err := json.Unmarshal(data, &existing); err == nil && existing.Name != name {
+		return "", sharedFileError(name, existing.Name)
+	}
+	return path, nil
+}
+
+func sharedFileError(name, other string) error {
+	return fmt.Errorf("view %q would share a file with view %q, choose another name", name, other)
+}
+
+func (s *Storage) SaveView(view *models.SavedView) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return fmt.Errorf("view name is required")
+	}
+	path, err := s.viewPath(view.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *Storage) DeleteView(name string) error {
+	path, err := s.viewPath(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// ListViews returns the saved views sorted by name
+func (s *Storage) ListViews() ([]*models.SavedView, error) {
+	files, err := filepath.Glob(filepath.Join(s.dataDir, "view_*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var views []*models.SavedView
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var view models.SavedView
+		if err := json.Unmarshal(data, &view); err == nil {
+			views = append(views, &view)
+		}
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+// ExportViews writes all saved views into one JSON file to share
+func (s *Storage) ExportViews(path string) (int, error) {
+	views, err := s.ListViews()
+	if err != nil {
+		return 0, err
+	}
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return len(views), os.WriteFile(path, data, 0644)
+}
+
+// CheckHotkeys reports the first of views whose hotkey is reserved or
+// taken by another view, either among views or among the saved views
+// they do not replace
+func (s *Storage) CheckHotkeys(views []*models.SavedView, reserved map[rune]bool) error {
+	names := make(map[string]bool)
+	for _, view := range views {
+		names[view.Name] = true
+	}
+	owners := make(map[string]string)
+	saved, err := s.ListViews()
+	if err != nil {
+		return err
+	}
+	for _, view := range saved {
+		if view.Hotkey != "" && !names[view.Name] {
+			owners[view.Hotkey] = view.Name
+		}
+	}
+
+	for _, view := range views {
+		if view.Hotkey == "" {
+			continue
+		}
+		if len([]rune(view.Hotkey)) != 1 {
+			return fmt.Errorf("view %q: hotkey %q must be a single character", view.Name, view.Hotkey)
+		}
+		if reserved[[]rune(view.Hotkey)[0]] {
+			return fmt.Errorf("view %q: hotkey %q is used by the navigation panel", view.Name, view.Hotkey)
+		}
+		if owner, ok := owners[view.Hotkey]; ok && owner != view.Name {
+			return fmt.Errorf("view %q: hotkey %q is used by view %q", view.Name, view.Hotkey, owner)
+		}
+		owners[view.Hotkey] = view.Name
+	}
+	return nil
+}
+
+// ImportViews saves the views of an exported file, replacing views with
+// the same name. Nothing is saved when a hotkey is reserved or taken or
+// when two names share a file.
+func (s *Storage) ImportViews(path string, reserved map[rune]bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var views []*models.SavedView
+	if err := json.Unmarshal(data, &views); err != nil {
+		return 0, fmt.Errorf("failed to parse views: %v", err)
+	}
+	if err := s.CheckHotkeys(views, reserved); err != nil {
+		return 0, err
+	}
+	files := make(map[string]string)
+	for _, view := range views {
+		if _, err := s.viewPath(view.Name); err != nil {
+			return 0, err
+		}
+		file := viewFileName(view.Name)
+		if other, ok := files[file]; ok && other != view.Name {
+			return 0, sharedFileError(view.Name, other)
+		}
+		files[file] = view.Name
+	}
+	for i, view := range views {
+		if err := s.SaveView(view); err != nil {
+			return i, err
+		}
+	}
+	return len(views), nil
+}
+
+// SaveLayout remembers the layout of the named table
+func (s *Storage) SaveLayout(table string, layout models.TableLayout) error {
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dataDir, "layout_"+table+".json"), data, 0644)
+}
+
+func (s *Storage) LoadLayout(table string) (*models.TableLayout, error) {
+	data, err := os.ReadFile(filepath.Join(s.dataDir, "layout_"+table+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var layout models.TableLayout
+	err = json.Unmarshal(data, &layout)
+	return &layout, err
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// taskColumns lists every field of models.UserTask. Keys match the
+// query field names; columns not listed in shown start hidden.
+func taskColumns(shown ...string) []dataColumn {
+	columns := []dataColumn{
+		{Key: "id", Header: "TaskID|", Suffix: " |"},
+		{Key: "name", Header: "TaskName", Align: tview.AlignRight},
+		{Key: "taskDefinitionKey", Header: "|TaskDefinitionKey", Prefix: "|"},
+		{Key: "processInstanceId", Header: "|ProcessID", Prefix: "|"},
+		{Key: "assignee", Header: "|Assignee", Prefix: "|", Color: tcell.ColorYellow},
+		{Key: "processName", Header: "|ProcessName", Prefix: "|"},
+		{Key: "activityName", Header: "|ActivityName", Prefix: "|"},
+		{Key: "status", Header: "|Status", Prefix: "|"},
+		{Key: "priority", Header: "|Priority", Prefix: "|", Number: true},
+		{Key: "created", Header: "|CreatedAt", Prefix: "|"},
+		{Key: "due", Header: "|DueDate", Prefix: "|"},
+		{Key: "candidateUser", Header: "|CandidateUsers", Prefix: "|"},
+		{Key: "candidateGroup", Header: "|CandidateGroups", Prefix: "|"},
+		{Key: "sla", Header: "|SLA", Prefix: "|"}, // filled by callers that track SLAs
+	}
+	return showOnly(columns, shown)
+}
+
+// -----------------------------------------------------------------------
+func taskRow(task models.UserTask) []string {
+	return []string{task.ID, task.Name, task.TaskDefinitionKey, task.ProcessID, task.Assignee,
+		task.ProcessName, task.ActivityName, task.Status, task.Priority,
+		formatCellTime(task.CreatedAt), formatCellTime(task.DueDate),
+		strings.Join(task.CandidateUsers, ","), strings.Join(task.CandidateGroups, ",")}
+}
+
+// -----------------------------------------------------------------------
+// processColumns lists every field of models.RunningProcess
+func processColumns(shown ...string) []dataColumn {
+	columns := []dataColumn{
+		{Key: "id", Header: "ProcessID"},
+		{Key: "status", Header: "| ProcessStatus", Prefix: "| "},
+		{Key: "processDefinitionKey", Header: "| ProcessDefKey", Prefix: "| "},
+		{Key: "started", Header: "| StartTime", Prefix: "| "},
+		{Key: "instanceId", Header: "| InstanceID", Prefix: "| "},
+		{Key: "processDefinitionId", Header: "| ProcessDefID", Prefix: "| "},
+		{Key: "processName", Header: "| ProcessName", Prefix: "| "},
+		{Key: "currentActivity", Header: "| CurrentActivity", Prefix: "| "},
+		{Key: "duration", Header: "| Duration", Prefix: "| "},
+		{Key: "forecast", Header: "| Forecast", Prefix: "| "}, // filled by callers that forecast
+	}
+	return showOnly(columns, shown)
+}
+
+// -----------------------------------------------------------------------
+func processRow(process models.RunningProcess) []string {
+	return []string{process.ProcessID, process.Status, process.ProcessDefinitionKey,
+		formatCellTime(process.StartTime), process.InstanceID, process.ProcessDefinitionId,
+		process.ProcessName, process.CurrentActivity, process.Duration}
+}
+
+// -----------------------------------------------------------------------
+func showOnly(columns []dataColumn, shown []string) []dataColumn {
+	if len(shown) == 0 {
+		return columns
+	}
+	visible := make(map[string]bool, len(shown))
+	for _, key := range shown {
+		visible[key] = true
+	}
+	for i := range columns {
+		columns[i].Hidden = !visible[columns[i].Key]
+	}
+	return columns
+}
+
+// -----------------------------------------------------------------------
+// formatCellTime formats times so they sort as text, blank when unset
+func formatCellTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// taskDetail is everything the task detail panel shows. Only the task
+// itself is required; the other parts report their own load errors.
+type taskDetail struct {
+	task        models.UserTask
+	links       []models.IdentityLink
+	linksErr    error
+	history     []models.IdentityLinkLog
+	historyErr  error
+	comments    []models.Comment
+	commentsErr error
+	attachments []models.Attachment
+	attachErr   error
+	process     *models.ProcessDetails
+	processErr  error
+}
+
+// -----------------------------------------------------------------------
+// loadTaskDetail fetches the task and its surroundings. It runs in a
+// background goroutine.
+func (m *BPMNManager) loadTaskDetail(task models.UserTask) *taskDetail {
+	detail := &taskDetail{task: task}
+	if fresh, err := m.apiClient.GetTask(task.ID); err == nil {
+		detail.task = *fresh
+	}
+	detail.links, detail.linksErr = m.apiClient.GetTaskIdentityLinks(task.ID)
+	detail.history, detail.historyErr = m.apiClient.GetTaskIdentityLinkHistory(task.ID)
+	detail.comments, detail.commentsErr = m.apiClient.GetTaskComments(task.ID)
+	detail.attachments, detail.attachErr = m.apiClient.GetTaskAttachments(task.ID)
+	if task.ProcessID != "" {
+		detail.process, detail.processErr = m.apiClient.GetProcessDetails(task.ProcessID)
+	}
+	return detail
+}
+
+// -----------------------------------------------------------------------
+// showTaskDetail opens the detail panel of a task, from where it can be
+// completed or its instance inspected
+func (m *BPMNManager) showTaskDetail(task models.UserTask) {
+	go func() {
+		detail := m.loadTaskDetail(task)
+		m.app.QueueUpdateDraw(func() {
+			m.pages.AddPage("task_detail", m.createTaskDetailView(detail), true, true)
+			m.pages.SwitchToPage("task_detail")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createTaskDetailView(detail *taskDetail) tview.Primitive {
+	task := detail.task
+
+	newSection := func(title, text string) *tview.TextView {
+		view := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true).SetScrollable(true)
+		view.SetBorder(true).SetTitle(" " + title + " ").SetBorderColor(tcell.Color102)
+		view.SetText(text)
+		return view
+	}
+
+	fields := newSection("Task", describeTask(detail))
+	vars := newSection("Instance Variables", describeTaskVariables(detail))
+	comments := newSection(fmt.Sprintf("Comments (%d)", len(detail.comments)), describeComments(detail.comments, detail.commentsErr))
+	history := newSection("Assignment History", describeLinkHistory(detail.history, detail.historyErr))
+	attachments := newSection(fmt.Sprintf("Attachments (%d)", len(detail.attachments)), describeAttachments(detail.attachments, detail.attachErr))
+
+	sections := []*tview.TextView{fields, vars, comments, attachments, history}
+	focused := 0
+	for _, section := range sections {
+		section.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyTab:
+				focused = (focused + 1) % len(sections)
+				m.app.SetFocus(sections[focused])
+				return nil
+			case tcell.KeyBacktab:
+				focused = (focused - 1 + len(sections)) % len(sections)
+				m.app.SetFocus(sections[focused])
+				return nil
+			case tcell.KeyF2:
+				m.CreateModalForTaskCompletion(task.ID)
+				return nil
+			}
+			switch event.Rune() {
+			case 'c':
+				m.showCompletionForm(task)
+			case 'v':
+				m.showVariables(task.ProcessID)
+			case 'h':
+				m.showVariableHistory(task.ProcessID)
+			case 't':
+				m.showTimeline(task.ProcessID)
+			case 'r':
+				m.showTaskDetail(task)
+			case 'a':
+				m.showCommentForm("Comment on "+task.ID, func(message string) error {
+					_, err := m.apiClient.AddTaskComment(task.ID, message)
+					return err
+				}, func() {
+					m.showTaskDetail(task)
+				})
+			case 'u':
+				m.showUploadAttachment(task)
+			case 'd':
+				m.showDownloadAttachment(task, detail.attachments)
+			default:
+				return event
+			}
+			return nil
+		})
+	}
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("c complete • F2 quick complete • a comment • u upload • d download • v variables • h variable history • t timeline • r reload • Tab next section • Esc back")
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(vars, 0, 2, false).
+		AddItem(comments, 0, 2, false).
+		AddItem(attachments, 0, 1, false).
+		AddItem(history, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(fields, 0, 1, true).
+		AddItem(right, 0, 1, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(fmt.Sprintf(" Task • %s ", tview.Escape(rtl(task.Name)))).SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, "main")
+	return layout
+}
+
+// -----------------------------------------------------------------------
+// describeTask lists every field of the task and its identity links
+func describeTask(detail *taskDetail) string {
+	task := detail.task
+	orNone := func(text string) string {
+		if text == "" {
+			return "[gray]—[white]"
+		}
+		return tview.Escape(text)
+	}
+
+	candidateUsers := append([]string(nil), task.CandidateUsers...)
+	candidateGroups := append([]string(nil), task.CandidateGroups...)
+	var owner []string
+	for _, link := range detail.links {
+		switch {
+		case link.Type == models.IdentityLinkCandidate && link.GroupID != "":
+			candidateGroups = appendUnique(candidateGroups, link.GroupID)
+		case link.Type == models.IdentityLinkCandidate:
+			candidateUsers = appendUnique(candidateUsers, link.UserID)
+		case link.Type == models.IdentityLinkOwner:
+			owner = append(owner, link.UserID)
+		}
+	}
+
+	rows := [][2]string{
+		{"ID", orNone(task.ID)},
+		{"Name", orNone(rtl(task.Name))},
+		{"Description", orNone(rtl(task.Description))},
+		{"Definition Key", orNone(task.TaskDefinitionKey)},
+		{"Form Key", orNone(task.FormKey)},
+		{"Process", orNone(rtl(task.ProcessName))},
+		{"Process ID", orNone(task.ProcessID)},
+		{"Activity", orNone(rtl(task.ActivityName))},
+		{"Status", orNone(task.Status)},
+		{"Priority", orNone(task.Priority)},
+		{"Assignee", orNone(task.Assignee)},
+		{"Owner", orNone(strings.Join(owner, ", "))},
+		{"Candidate Users", orNone(strings.Join(candidateUsers, ", "))},
+		{"Candidate Groups", orNone(strings.Join(candidateGroups, ", "))},
+		{"Created", orNone(formatCellTime(task.CreatedAt))},
+		{"Due", orNone(formatCellTime(task.DueDate))},
+		{"Follow Up", orNone(formatCellTime(task.FollowUpDate))},
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&b, "[yellow]%-17s[white] %s\n", row[0]+":", row[1])
+	}
+	if detail.linksErr != nil {
+		fmt.Fprintf(&b, "\n[red]Identity links unavailable: %s", tview.Escape(detail.linksErr.Error()))
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+func describeTaskVariables(detail *taskDetail) string {
+	if detail.processErr != nil {
+		return "[red]" + tview.Escape(detail.processErr.Error())
+	}
+	if detail.process == nil || len(detail.process.CurrentVariables) == 0 {
+		return "[gray]No variables"
+	}
+
+	var b strings.Builder
+	for _, variable := range variables.FromMap(detail.process.CurrentVariables) {
+		fmt.Fprintf(&b, "[yellow]%s[gray] %s[white] = %s\n",
+			tview.Escape(variable.Name), variable.Type, tview.Escape(rtl(variables.Summary(variable.Value, 60))))
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+func describeComments(comments []models.Comment, err error) string {
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error())
+	}
+	if len(comments) == 0 {
+		return "[gray]No comments"
+	}
+
+	var b strings.Builder
+	for _, comment := range comments {
+		fmt.Fprintf(&b, "[yellow]%s[gray] %s[white]\n%s\n\n",
+			orUnknown(comment.UserID), formatCellTime(comment.Time), tview.Escape(rtl(comment.Message)))
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+func describeAttachments(attachments []models.Attachment, err error) string {
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error())
+	}
+	if len(attachments) == 0 {
+		return "[gray]No attachments"
+	}
+
+	var b strings.Builder
+	for _, attachment := range attachments {
+		fmt.Fprintf(&b, "📎 %s [gray]%s • %s • %s[white]\n", tview.Escape(attachment.Name),
+			formatSize(attachment.Size), orUnknown(attachment.UserID), formatCellTime(attachment.CreatedAt))
+		if attachment.Description != "" {
+			fmt.Fprintf(&b, "   %s\n", tview.Escape(rtl(attachment.Description)))
+		}
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+func describeLinkHistory(history []models.IdentityLinkLog, err error) string {
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error())
+	}
+	if len(history) == 0 {
+		return "[gray]No assignment changes"
+	}
+
+	var b strings.Builder
+	for _, entry := range history {
+		target := entry.UserID
+		if entry.GroupID != "" {
+			target = "group " + entry.GroupID
+		}
+		sign := "[green]+"
+		if entry.Operation == "delete" {
+			sign = "[red]-"
+		}
+		fmt.Fprintf(&b, "[gray]%s %s %s[white] %s", formatCellTime(entry.Time), sign, entry.Type, tview.Escape(target))
+		if entry.AssignerID != "" && entry.AssignerID != entry.UserID {
+			fmt.Fprintf(&b, " [gray]by %s", tview.Escape(entry.AssignerID))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// -----------------------------------------------------------------------
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// -----------------------------------------------------------------------
+func orUnknown(user string) string {
+	if user == "" {
+		return "unknown"
+	}
+	return tview.Escape(user)
+}
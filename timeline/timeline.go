@@ -0,0 +1,199 @@
+package timeline
+
+import (
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bar is one activity execution placed on the time axis
+type Bar struct {
+	Activity models.ProcessActivity
+	Start    time.Time
+	End      time.Time
+	Lane     int           // parallel branch the bar was placed in
+	Wait     time.Duration // idle time since the activity that released it ended
+	Waiting  bool          // the activity itself is a wait state (event, receive task)
+	Running  bool          // no end time yet, End is "now"
+	Critical bool          // on the chain that determines the overall end time
+	Pred     int           // index of the releasing bar, -1 if none
+}
+
+// Timeline is the execution of one instance laid out for a Gantt chart
+type Timeline struct {
+	Start time.Time
+	End   time.Time
+	Bars  []Bar
+	Lanes int
+}
+
+// Scale is the length of one chart column
+type Scale int
+
+const (
+	ScaleFit Scale = iota
+	ScaleMinute
+	ScaleHour
+	ScaleDay
+)
+
+// -----------------------------------------------------------------------
+func (s Scale) String() string {
+	switch s {
+	case ScaleMinute:
+		return "minute"
+	case ScaleHour:
+		return "hour"
+	case ScaleDay:
+		return "day"
+	}
+	return "fit"
+}
+
+// -----------------------------------------------------------------------
+// Unit returns the duration of one column, fitting the whole timeline into
+// width columns for ScaleFit
+func (s Scale) Unit(total time.Duration, width int) time.Duration {
+	switch s {
+	case ScaleMinute:
+		return time.Minute
+	case ScaleHour:
+		return time.Hour
+	case ScaleDay:
+		return 24 * time.Hour
+	}
+	if width < 1 {
+		width = 1
+	}
+	unit := total / time.Duration(width)
+	if total%time.Duration(width) != 0 {
+		unit++
+	}
+	if unit < time.Second {
+		unit = time.Second
+	}
+	return unit
+}
+
+// -----------------------------------------------------------------------
+// Build lays out the activities of an instance. Activities without an end
+// time are drawn up to now.
+func Build(activities []models.ProcessActivity, now time.Time) *Timeline {
+	t := &Timeline{}
+	for _, activity := range activities {
+		if activity.StartTime.IsZero() {
+			continue
+		}
+		bar := Bar{
+			Activity: activity,
+			Start:    activity.StartTime,
+			End:      activity.EndTime,
+			Waiting:  isWaitState(activity.Type),
+			Pred:     -1,
+		}
+		if bar.End.IsZero() {
+			bar.End = now
+			bar.Running = true
+		}
+		if bar.End.Before(bar.Start) {
+			bar.End = bar.Start
+		}
+		t.Bars = append(t.Bars, bar)
+	}
+	if len(t.Bars) == 0 {
+		return t
+	}
+
+	sort.SliceStable(t.Bars, func(i, j int) bool {
+		return t.Bars[i].Start.Before(t.Bars[j].Start)
+	})
+
+	t.Start, t.End = t.Bars[0].Start, t.Bars[0].End
+	var laneEnds []time.Time
+	for i := range t.Bars {
+		bar := &t.Bars[i]
+		if bar.End.After(t.End) {
+			t.End = bar.End
+		}
+
+		// first lane that is free again, so overlapping bars end up in
+		// separate lanes
+		bar.Lane = len(laneEnds)
+		for lane, end := range laneEnds {
+			if !end.After(bar.Start) {
+				bar.Lane = lane
+				break
+			}
+		}
+		if bar.Lane == len(laneEnds) {
+			laneEnds = append(laneEnds, bar.End)
+		} else {
+			laneEnds[bar.Lane] = bar.End
+		}
+
+		// the releasing predecessor is the latest activity that finished
+		// before this one started
+		for j := 0; j < i; j++ {
+			other := t.Bars[j]
+			if other.Running || other.End.After(bar.Start) {
+				continue
+			}
+			if bar.Pred < 0 || other.End.After(t.Bars[bar.Pred].End) {
+				bar.Pred = j
+			}
+		}
+		if bar.Pred >= 0 {
+			bar.Wait = bar.Start.Sub(t.Bars[bar.Pred].End)
+		}
+	}
+	t.Lanes = len(laneEnds)
+
+	last := 0
+	for i, bar := range t.Bars {
+		if bar.End.After(t.Bars[last].End) {
+			last = i
+		}
+	}
+	for i := last; i >= 0; i = t.Bars[i].Pred {
+		t.Bars[i].Critical = true
+	}
+	return t
+}
+
+// -----------------------------------------------------------------------
+// Duration is the wall-clock span of the whole timeline
+func (t *Timeline) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// -----------------------------------------------------------------------
+// CriticalPath splits the time on the critical path into work (activity
+// durations) and wait (idle gaps plus wait-state activities), measured by
+// clock
+func (t *Timeline) CriticalPath(clock calendar.Clock) (work, wait time.Duration) {
+	for _, bar := range t.Bars {
+		if !bar.Critical {
+			continue
+		}
+		if bar.Pred >= 0 {
+			wait += clock.Between(t.Bars[bar.Pred].End, bar.Start)
+		}
+		if bar.Waiting {
+			wait += clock.Between(bar.Start, bar.End)
+		} else {
+			work += clock.Between(bar.Start, bar.End)
+		}
+	}
+	return work, wait
+}
+
+// -----------------------------------------------------------------------
+func isWaitState(activityType string) bool {
+	// history reports catch events as e.g. intermediateMessageCatch or
+	// intermediateTimer rather than the BPMN element name
+	kind := strings.ToLower(activityType)
+	return strings.Contains(kind, "event") || strings.Contains(kind, "catch") ||
+		strings.Contains(kind, "timer") || kind == "receivetask"
+}
@@ -0,0 +1,110 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+var base = time.Date(2024, 5, 7, 9, 0, 0, 0, time.UTC)
+
+// at returns the time minutes after base
+func at(minutes int) time.Time {
+	return base.Add(time.Duration(minutes) * time.Minute)
+}
+
+// -----------------------------------------------------------------------
+func activity(id, kind string, start, end int) models.ProcessActivity {
+	a := models.ProcessActivity{ID: id, Type: kind, StartTime: at(start)}
+	if end >= 0 {
+		a.EndTime = at(end)
+	}
+	return a
+}
+
+func TestBuild(t *testing.T) {
+	// start → check, then pack and invoice in parallel, then a message
+	// wait that is still running at minute 100
+	timeline := Build([]models.ProcessActivity{
+		activity("invoice", "serviceTask", 40, 50),
+		activity("start", "startEvent", 0, 0),
+		activity("check", "userTask", 10, 30),
+		activity("pack", "userTask", 35, 70),
+		activity("wait", "intermediateMessageCatch", 80, -1),
+		{ID: "never", Type: "userTask"},
+	}, at(100))
+
+	var ids []string
+	for _, bar := range timeline.Bars {
+		ids = append(ids, bar.Activity.ID)
+	}
+	if want := []string{"start", "check", "pack", "invoice", "wait"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("bars = %v, want %v", ids, want)
+	}
+	if !timeline.Start.Equal(at(0)) || !timeline.End.Equal(at(100)) || timeline.Duration() != 100*time.Minute {
+		t.Errorf("span = %v–%v", timeline.Start, timeline.End)
+	}
+	if timeline.Lanes != 2 {
+		t.Errorf("lanes = %d, want 2", timeline.Lanes)
+	}
+
+	tests := []struct {
+		lane     int
+		pred     int
+		wait     time.Duration
+		critical bool
+	}{
+		{0, -1, 0, true},
+		{0, 0, 10 * time.Minute, true},
+		{0, 1, 5 * time.Minute, true},
+		{1, 1, 10 * time.Minute, false},
+		{0, 2, 10 * time.Minute, true},
+	}
+	for i, tt := range tests {
+		bar := timeline.Bars[i]
+		if bar.Lane != tt.lane || bar.Pred != tt.pred || bar.Wait != tt.wait || bar.Critical != tt.critical {
+			t.Errorf("%s: lane %d pred %d wait %v critical %v; want %d %d %v %v",
+				bar.Activity.ID, bar.Lane, bar.Pred, bar.Wait, bar.Critical, tt.lane, tt.pred, tt.wait, tt.critical)
+		}
+	}
+	if last := timeline.Bars[4]; !last.Running || !last.Waiting {
+		t.Errorf("wait: running %v waiting %v", last.Running, last.Waiting)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	timeline := Build([]models.ProcessActivity{
+		activity("start", "startEvent", 0, 0),
+		activity("check", "userTask", 10, 30),
+		activity("timer", "intermediateTimer", 30, 90),
+		activity("approve", "userTask", 95, 100),
+	}, at(200))
+	work, wait := timeline.CriticalPath(calendar.WallClock{})
+	if work != 25*time.Minute || wait != 75*time.Minute {
+		t.Errorf("CriticalPath() = %v work, %v wait; want 25m0s, 1h15m0s", work, wait)
+	}
+}
+
+func TestScaleUnit(t *testing.T) {
+	tests := []struct {
+		scale Scale
+		total time.Duration
+		width int
+		want  time.Duration
+	}{
+		{ScaleMinute, time.Hour, 10, time.Minute},
+		{ScaleDay, time.Hour, 10, 24 * time.Hour},
+		{ScaleFit, time.Hour, 60, time.Minute},
+		{ScaleFit, time.Hour + time.Nanosecond, 60, time.Minute + 1},
+		{ScaleFit, time.Millisecond, 10, time.Second},
+		{ScaleFit, time.Minute, 0, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.scale.Unit(tt.total, tt.width); got != tt.want {
+			t.Errorf("%s.Unit(%v, %d) = %v, want %v", tt.scale, tt.total, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/timeline"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const timelineLabelWidth = 32
+
+// timelineView draws the activities of an instance as a Gantt chart
+type timelineView struct {
+	*tview.Box
+	timeline *timeline.Timeline
+	clock    calendar.Clock // measures the durations in the summary
+	scale    timeline.Scale
+	offset   int // first visible column
+	top      int // first visible bar
+	selected int
+}
+
+// -----------------------------------------------------------------------
+func newTimelineView(t *timeline.Timeline, clock calendar.Clock) *timelineView {
+	view := &timelineView{
+		Box:      tview.NewBox(),
+		timeline: t,
+		clock:    clock,
+		scale:    timeline.ScaleFit,
+	}
+	view.SetBorder(true).SetBorderColor(tcell.Color102)
+	return view
+}
+
+// -----------------------------------------------------------------------
+// rtl reverses Persian/Arabic text for terminals without bidi support
+func rtl(text string) string {
+	if containsPersian(text) {
+		return reverseString(text)
+	}
+	return text
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) unit(chartWidth int) time.Duration {
+	return v.scale.Unit(v.timeline.Duration(), chartWidth)
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) column(t time.Time, unit time.Duration) int {
+	return int(t.Sub(v.timeline.Start)/unit) - v.offset
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) Draw(screen tcell.Screen) {
+	v.DrawForSubclass(screen, v)
+	x, y, width, height := v.GetInnerRect()
+	if width <= timelineLabelWidth+10 || height < 6 {
+		return
+	}
+
+	t := v.timeline
+	if len(t.Bars) == 0 {
+		tview.Print(screen, "⚠️ No activities to show", x, y, width, tview.AlignLeft, tcell.ColorYellow)
+		return
+	}
+
+	chartX := x + timelineLabelWidth + 1
+	chartWidth := width - timelineLabelWidth - 1
+	unit := v.unit(chartWidth)
+
+	work, wait := t.CriticalPath(v.clock)
+	summary := fmt.Sprintf("[yellow]Scale:[white] %s (1 col = %s)  [yellow]Cycle:[white] %s  [yellow]Critical path:[white] work %s / wait %s  [yellow]Branches:[white] %d",
+		v.scale, unit, formatDuration(int(v.clock.Between(t.Start, t.End).Seconds())),
+		formatDuration(int(work.Seconds())), formatDuration(int(wait.Seconds())), t.Lanes)
+	tview.Print(screen, summary, x, y, width, tview.AlignLeft, tcell.ColorWhite)
+
+	v.drawAxis(screen, chartX, y+1, chartWidth, unit)
+
+	rows := height - 5
+	if v.selected < v.top {
+		v.top = v.selected
+	}
+	if v.selected >= v.top+rows {
+		v.top = v.selected - rows + 1
+	}
+	for i := 0; i < rows && v.top+i < len(t.Bars); i++ {
+		index := v.top + i
+		v.drawBar(screen, index, x, chartX, y+2+i, chartWidth, unit)
+	}
+
+	bar := t.Bars[v.selected]
+	end := formatEndTime(bar.End)
+	if bar.Running {
+		end = "running"
+	}
+	waited := time.Duration(0)
+	if bar.Pred >= 0 {
+		waited = v.clock.Between(t.Bars[bar.Pred].End, bar.Start)
+	}
+	details := fmt.Sprintf("[orange]%s[white] (%s)  %s → %s  took %s, waited %s before start",
+		tview.Escape(rtl(bar.Activity.Name)), bar.Activity.Type,
+		bar.Start.Format("2006-01-02 15:04:05"), end,
+		formatDuration(int(v.clock.Between(bar.Start, bar.End).Seconds())), formatDuration(int(waited.Seconds())))
+	tview.Print(screen, details, x, y+height-2, width, tview.AlignLeft, tcell.ColorWhite)
+
+	legend := "[green]█[white] work  [darkcyan]▒[white] wait state  [gray]░[white] idle  [red]█[white] critical path  •  +/- zoom  f fit  ←/→ scroll  ↑/↓ select  Esc back"
+	tview.Print(screen, legend, x, y+height-1, width, tview.AlignLeft, tcell.ColorWhite)
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) drawAxis(screen tcell.Screen, x, y, width int, unit time.Duration) {
+	layout := "01-02"
+	switch {
+	case unit < time.Hour:
+		layout = "15:04"
+	case unit < 24*time.Hour:
+		layout = "01-02 15h"
+	}
+	spacing := len(layout) + 3
+	for col := 0; col < width; col += spacing {
+		at := v.timeline.Start.Add(time.Duration(col+v.offset) * unit)
+		screen.SetContent(x+col, y, '┬', nil, tcell.StyleDefault.Foreground(tcell.ColorGray))
+		tview.Print(screen, at.Format(layout), x+col+1, y, spacing-1, tview.AlignLeft, tcell.ColorGray)
+	}
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) drawBar(screen tcell.Screen, index, labelX, chartX, y, chartWidth int, unit time.Duration) {
+	bar := v.timeline.Bars[index]
+
+	labelColor := tcell.ColorWhite
+	if bar.Critical {
+		labelColor = tcell.ColorRed
+	}
+	name := rtl(bar.Activity.Name)
+	if name == "" {
+		name = bar.Activity.ID
+	}
+	label := strings.Repeat("│", bar.Lane) + "├ " + name
+	if index == v.selected {
+		for i := 0; i < timelineLabelWidth; i++ {
+			screen.SetContent(labelX+i, y, ' ', nil, tcell.StyleDefault.Background(tcell.ColorGreen))
+		}
+	}
+	tview.Print(screen, tview.Escape(label), labelX, y, timelineLabelWidth, tview.AlignLeft, labelColor)
+
+	fill := func(from, to int, ch rune, color tcell.Color) {
+		if from < 0 {
+			from = 0
+		}
+		if to >= chartWidth {
+			to = chartWidth - 1
+		}
+		for col := from; col <= to; col++ {
+			screen.SetContent(chartX+col, y, ch, nil, tcell.StyleDefault.Foreground(color))
+		}
+	}
+
+	if bar.Pred >= 0 && bar.Wait > 0 {
+		pred := v.timeline.Bars[bar.Pred]
+		fill(v.column(pred.End, unit), v.column(bar.Start, unit)-1, '░', tcell.ColorGray)
+	}
+
+	ch, color := '█', tcell.ColorGreen
+	if bar.Waiting {
+		ch, color = '▒', tcell.ColorDarkCyan
+	}
+	if bar.Critical {
+		color = tcell.ColorRed
+	}
+	start, end := v.column(bar.Start, unit), v.column(bar.End, unit)
+	fill(start, end, ch, color)
+	if bar.Running && end+1 < chartWidth && end+1 >= 0 {
+		screen.SetContent(chartX+end+1, y, '▶', nil, tcell.StyleDefault.Foreground(tcell.ColorYellow))
+	}
+}
+
+// -----------------------------------------------------------------------
+func (v *timelineView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return v.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if v.selected > 0 {
+				v.selected--
+			}
+		case tcell.KeyDown:
+			if v.selected < len(v.timeline.Bars)-1 {
+				v.selected++
+			}
+		case tcell.KeyLeft:
+			v.offset -= 5
+			if v.offset < 0 {
+				v.offset = 0
+			}
+		case tcell.KeyRight:
+			v.offset += 5
+		case tcell.KeyHome:
+			v.offset = 0
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case '+', '=':
+				v.zoom(-1)
+			case '-':
+				v.zoom(1)
+			case 'f':
+				v.scale, v.offset = timeline.ScaleFit, 0
+			case 'c':
+				v.centerSelected()
+			}
+		}
+	})
+}
+
+// -----------------------------------------------------------------------
+// zoom steps between minute, hour and day columns; direction -1 is finer
+func (v *timelineView) zoom(direction int) {
+	scale := v.scale
+	if scale == timeline.ScaleFit {
+		scale = timeline.ScaleHour
+		if v.timeline.Duration() < 6*time.Hour {
+			scale = timeline.ScaleMinute
+		}
+		if direction > 0 && v.timeline.Duration() > 7*24*time.Hour {
+			scale = timeline.ScaleDay
+		}
+	} else {
+		scale += timeline.Scale(direction)
+	}
+	if scale < timeline.ScaleMinute {
+		scale = timeline.ScaleMinute
+	}
+	if scale > timeline.ScaleDay {
+		scale = timeline.ScaleDay
+	}
+	v.scale = scale
+	v.centerSelected()
+}
+
+// -----------------------------------------------------------------------
+// centerSelected scrolls so the selected bar starts near the left edge
+func (v *timelineView) centerSelected() {
+	if len(v.timeline.Bars) == 0 || v.scale == timeline.ScaleFit {
+		v.offset = 0
+		return
+	}
+	_, _, width, _ := v.GetInnerRect()
+	unit := v.unit(width - timelineLabelWidth - 1)
+	bar := v.timeline.Bars[v.selected]
+	v.offset = int(bar.Start.Sub(v.timeline.Start)/unit) - 5
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// -----------------------------------------------------------------------
+// showTimeline loads an instance and opens its activity timeline
+func (m *BPMNManager) showTimeline(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	go func() {
+		process, err := m.apiClient.GetProcessDetails(instanceID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load process details: " + err.Error())
+				return
+			}
+			view := newTimelineView(timeline.Build(process.Activities, time.Now()), m.clock())
+			view.SetTitle(fmt.Sprintf(" Timeline • %s • %s ", process.ID, process.ProcessDefinitionKey))
+			m.backOnEsc(view, "main")
+			m.pages.AddPage("timeline", view, true, true)
+			m.pages.SwitchToPage("timeline")
+			m.app.SetFocus(view)
+		})
+	}()
+}
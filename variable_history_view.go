@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showVariableHistory shows how each variable of an instance changed over
+// time and which activity wrote it, with a diff between two chosen points
+func (m *BPMNManager) showVariableHistory(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	go func() {
+		updates, err := m.apiClient.GetVariableHistory(instanceID)
+		process, detailsErr := m.apiClient.GetProcessDetails(instanceID)
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load variable history: " + err.Error())
+				return
+			}
+			activityNames := make(map[string]string)
+			if detailsErr == nil {
+				for _, activity := range process.Activities {
+					activityNames[activity.ID] = activity.Name
+					if activity.TaskId != "" {
+						activityNames["task:"+activity.TaskId] = activity.Name
+					}
+				}
+			}
+			m.pages.AddPage("variable_history", m.createVariableHistory(instanceID, updates, activityNames), true, true)
+			m.pages.SwitchToPage("variable_history")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createVariableHistory(instanceID string, updates []models.VariableUpdate, activityNames map[string]string) tview.Primitive {
+	variables.SortUpdates(updates)
+	byName := variables.ByName(updates)
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(" Variables ").SetBorderColor(tcell.Color102)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+
+	table := tview.NewTable().
+		SetBorders(false).
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	diffView := tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	diffView.SetBorder(true).SetTitle(" Diff ").SetBorderColor(tcell.Color102)
+	diffView.SetText("[gray]Select a change and press 'a' / 'b' to mark two points in time")
+
+	var shown []models.VariableUpdate
+	var pointA, pointB *models.VariableUpdate
+
+	activityLabel := func(update models.VariableUpdate) string {
+		if name, ok := activityNames["task:"+update.TaskID]; ok && update.TaskID != "" {
+			return rtl(name)
+		}
+		if name, ok := activityNames[update.ActivityID()]; ok && name != "" {
+			return rtl(name)
+		}
+		return update.ActivityID()
+	}
+
+	showVariable := func(name string) {
+		shown = byName[name]
+		table.Clear()
+		headers := []string{"Time", "|Value", "|Type", "|Activity", "|Task", "|User", "|Rev"}
+		for i, header := range headers {
+			table.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		for row, update := range shown {
+			value := variables.Summary(update.Value, 40)
+			if row > 0 && !variables.Equal(shown[row-1].Value, update.Value) {
+				value = "[orange]" + tview.Escape(value)
+			} else {
+				value = tview.Escape(value)
+			}
+			table.SetCell(row+1, 0, tview.NewTableCell(update.Time.Format("2006-01-02 15:04:05")))
+			table.SetCell(row+1, 1, tview.NewTableCell("|"+value))
+			table.SetCell(row+1, 2, tview.NewTableCell("|"+update.VariableType))
+			table.SetCell(row+1, 3, tview.NewTableCell("|"+activityLabel(update)))
+			table.SetCell(row+1, 4, tview.NewTableCell("|"+update.TaskID))
+			table.SetCell(row+1, 5, tview.NewTableCell("|"+update.UserID).SetTextColor(tcell.ColorYellow))
+			table.SetCell(row+1, 6, tview.NewTableCell(fmt.Sprintf("|%d", update.Revision)))
+		}
+		table.SetTitle(fmt.Sprintf(" %s • %d changes ", name, len(shown)))
+		table.Select(1, 0)
+	}
+
+	showDiff := func() {
+		text := ""
+		if pointA != nil {
+			text += fmt.Sprintf("[yellow]A:[white] %s (%s)\n", pointA.Time.Format("2006-01-02 15:04:05"), activityLabel(*pointA))
+		}
+		if pointB != nil {
+			text += fmt.Sprintf("[yellow]B:[white] %s (%s)\n", pointB.Time.Format("2006-01-02 15:04:05"), activityLabel(*pointB))
+		}
+		if pointA != nil && pointB != nil {
+			from, to := pointA, pointB
+			if to.Time.Before(from.Time) {
+				from, to = to, from
+			}
+			changes := variables.Diff(variables.SnapshotAt(updates, from.Time), variables.SnapshotAt(updates, to.Time))
+			if len(changes) == 0 {
+				text += "\n[gray]No differences"
+			}
+			for _, change := range changes {
+				text += "\n" + tview.Escape(change.String())
+			}
+		}
+		diffView.SetText(text)
+	}
+
+	for _, name := range names {
+		list.AddItem(fmt.Sprintf("%s (%d)", name, len(byName[name])), "", 0, nil)
+	}
+	list.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		showVariable(names[index])
+	})
+	list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		m.app.SetFocus(table)
+	})
+	if len(names) > 0 {
+		showVariable(names[0])
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+			m.app.SetFocus(list)
+			return nil
+		}
+		if row < 1 || row > len(shown) {
+			return event
+		}
+		update := shown[row-1]
+		switch event.Rune() {
+		case 'a':
+			pointA = &update
+			showDiff()
+			return nil
+		case 'b':
+			pointB = &update
+			showDiff()
+			return nil
+		}
+		return event
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+			m.app.SetFocus(table)
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Tab switch panel • a/b mark points to diff • [orange]orange[white] = value changed • Esc back")
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 2, false).
+		AddItem(diffView, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(list, 35, 1, true).
+		AddItem(right, 0, 3, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).SetTitle(fmt.Sprintf(" Variable History • %s ", instanceID)).SetBorderColor(tcell.Color102)
+	m.backOnEsc(layout, "main")
+
+	if len(names) == 0 {
+		diffView.SetText("⚠️ No variable updates recorded for this instance")
+	}
+	return layout
+}
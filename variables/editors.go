@@ -0,0 +1,38 @@
+package variables
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// DefaultEditorGroups may modify variables when no config file names
+// the groups
+var DefaultEditorGroups = []string{"camunda-admin", "workflow-operators"}
+
+// Editors names the groups whose members may modify variables of
+// running instances
+type Editors struct {
+	Groups []string `json:"editorGroups"`
+}
+
+// -----------------------------------------------------------------------
+// LoadEditors reads the editor groups from a JSON file. A missing file,
+// or one without editorGroups, gives DefaultEditorGroups.
+func LoadEditors(path string) (*Editors, error) {
+	editors := &Editors{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, editors); err != nil {
+			return nil, fmt.Errorf("invalid variables config %s: %w", path, err)
+		}
+	}
+	if editors.Groups == nil {
+		editors.Groups = DefaultEditorGroups
+	}
+	return editors, nil
+}
@@ -0,0 +1,39 @@
+package variables
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadEditors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, content string
+		want          []string
+		err           bool
+	}{
+		{"missing", "", DefaultEditorGroups, false},
+		{"groups", `{"editorGroups": ["finance"]}`, []string{"finance"}, false},
+		{"nobody", `{"editorGroups": []}`, []string{}, false},
+		{"no key", `{}`, DefaultEditorGroups, false},
+		{"broken", `{"editorGroups": "finance"}`, nil, true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".json")
+		if tt.content != "" {
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		editors, err := LoadEditors(path)
+		if (err != nil) != tt.err {
+			t.Errorf("LoadEditors(%s) error = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(editors.Groups, tt.want) {
+			t.Errorf("LoadEditors(%s) = %v, want %v", tt.name, editors.Groups, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package variables
+
+import (
+	"bpmn-manager/models"
+	"sort"
+	"time"
+)
+
+// -----------------------------------------------------------------------
+// SortUpdates orders updates by time, then revision
+func SortUpdates(updates []models.VariableUpdate) {
+	sort.SliceStable(updates, func(i, j int) bool {
+		if updates[i].Time.Equal(updates[j].Time) {
+			return updates[i].Revision < updates[j].Revision
+		}
+		return updates[i].Time.Before(updates[j].Time)
+	})
+}
+
+// -----------------------------------------------------------------------
+// ByName groups time-ordered updates per variable
+func ByName(updates []models.VariableUpdate) map[string][]models.VariableUpdate {
+	grouped := make(map[string][]models.VariableUpdate)
+	for _, update := range updates {
+		grouped[update.VariableName] = append(grouped[update.VariableName], update)
+	}
+	for _, list := range grouped {
+		SortUpdates(list)
+	}
+	return grouped
+}
+
+// -----------------------------------------------------------------------
+// SnapshotAt replays updates up to and including t and returns the
+// variable values the instance held at that moment
+func SnapshotAt(updates []models.VariableUpdate, t time.Time) []Variable {
+	ordered := append([]models.VariableUpdate(nil), updates...)
+	SortUpdates(ordered)
+
+	state := make(map[string]Variable)
+	for _, update := range ordered {
+		if update.Time.After(t) {
+			break
+		}
+		typ := update.VariableType
+		if typ == "" {
+			typ = TypeOf(update.Value)
+		}
+		state[update.VariableName] = Variable{Name: update.VariableName, Type: typ, Value: update.Value}
+	}
+
+	list := make([]Variable, 0, len(state))
+	for _, variable := range state {
+		list = append(list, variable)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,366 @@
+package variables
+
+import (
+	"bpmn-manager/models"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Engine variable types
+const (
+	TypeString  = "String"
+	TypeBoolean = "Boolean"
+	TypeShort   = "Short"
+	TypeInteger = "Integer"
+	TypeLong    = "Long"
+	TypeDouble  = "Double"
+	TypeDate    = "Date"
+	TypeJSON    = "Json"
+	TypeNull    = "Null"
+)
+
+// Types lists the types offered when editing a variable
+var Types = []string{TypeString, TypeBoolean, TypeShort, TypeInteger, TypeLong, TypeDouble, TypeDate, TypeJSON, TypeNull}
+
+// dateLayouts are the date formats recognised in string values
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Variable is a process variable with its engine or inferred type
+type Variable struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// Change is one difference between two variable sets
+type Change struct {
+	Name string
+	Old  *Variable // nil when added
+	New  *Variable // nil when deleted
+}
+
+// -----------------------------------------------------------------------
+// FromMap converts decoded JSON variables into a name-sorted list. Values
+// the engine sends as {"type": ..., "value": ...} keep the engine's type;
+// plain values get the type TypeOf infers.
+func FromMap(values map[string]interface{}) []Variable {
+	list := make([]Variable, 0, len(values))
+	for name, value := range values {
+		if typ, typed, ok := fromEngine(value); ok {
+			list = append(list, Variable{Name: name, Type: typ, Value: typed})
+			continue
+		}
+		list = append(list, Variable{Name: name, Type: TypeOf(value), Value: value})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// -----------------------------------------------------------------------
+// fromEngine unwraps a typed engine value. Known types are spelled as in
+// Types; Json text is decoded so it can be browsed like any JSON value.
+func fromEngine(value interface{}) (string, interface{}, bool) {
+	wrapped, ok := value.(map[string]interface{})
+	if !ok {
+		return "", nil, false
+	}
+	typ, ok := wrapped["type"].(string)
+	if !ok || typ == "" {
+		return "", nil, false
+	}
+	inner, ok := wrapped["value"]
+	if !ok {
+		return "", nil, false
+	}
+	for key := range wrapped {
+		if key != "type" && key != "value" && key != "valueInfo" {
+			return "", nil, false
+		}
+	}
+
+	for _, known := range Types {
+		if strings.EqualFold(typ, known) {
+			typ = known
+		}
+	}
+	if text, isText := inner.(string); isText && typ == TypeJSON {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+			inner = decoded
+		}
+	}
+	return typ, inner, true
+}
+
+// -----------------------------------------------------------------------
+// Editable reports whether values of the engine type can be parsed from
+// text; serialized objects and files cannot
+func Editable(typ string) bool {
+	for _, known := range Types {
+		if typ == known {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------
+// TypeOf infers the engine type of a decoded JSON value
+func TypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			if v >= math.MinInt32 && v <= math.MaxInt32 {
+				return TypeInteger
+			}
+			return TypeLong
+		}
+		return TypeDouble
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return TypeLong
+		}
+		return TypeDouble
+	case string:
+		if _, ok := parseDate(v); ok {
+			return TypeDate
+		}
+		return TypeString
+	case map[string]interface{}, []interface{}:
+		return TypeJSON
+	}
+	return TypeString
+}
+
+// -----------------------------------------------------------------------
+func parseDate(text string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// -----------------------------------------------------------------------
+// Format renders a value as editable text
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// -----------------------------------------------------------------------
+// Summary renders a value on a single line, shortening long text
+func Summary(value interface{}, limit int) string {
+	text := Format(value)
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if data, err := json.Marshal(value); err == nil {
+			text = string(data)
+		}
+	}
+	text = strings.ReplaceAll(text, "\n", " ")
+	if limit > 3 && len([]rune(text)) > limit {
+		text = string([]rune(text)[:limit-3]) + "..."
+	}
+	return text
+}
+
+// -----------------------------------------------------------------------
+// Parse converts edited text into a value of the given type
+func Parse(text, typ string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	switch typ {
+	case TypeString:
+		return text, nil
+	case TypeNull:
+		return nil, nil
+	case TypeBoolean:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean (use true or false)", text)
+		}
+		return value, nil
+	case TypeShort:
+		value, err := strconv.ParseInt(text, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 16-bit integer", text)
+		}
+		return value, nil
+	case TypeInteger:
+		value, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 32-bit integer", text)
+		}
+		return value, nil
+	case TypeLong:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", text)
+		}
+		return value, nil
+	case TypeDouble:
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return value, nil
+	case TypeDate:
+		t, ok := parseDate(text)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a date (use 2006-01-02T15:04:05)", text)
+		}
+		return t.Format("2006-01-02T15:04:05.000-0700"), nil
+	case TypeJSON:
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unknown variable type %q", typ)
+}
+
+// -----------------------------------------------------------------------
+// ToEngine converts a variable into the payload the engine expects
+func ToEngine(v Variable) models.VariableValue {
+	value := v.Value
+	if v.Type == TypeJSON {
+		// the engine stores Json variables as their serialized text
+		if data, err := json.Marshal(value); err == nil {
+			value = string(data)
+		}
+	}
+	return models.VariableValue{Value: value, Type: v.Type}
+}
+
+// -----------------------------------------------------------------------
+// Equal compares two values by their JSON form
+func Equal(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// -----------------------------------------------------------------------
+// Diff lists the variables added, removed or changed from before to after
+func Diff(before, after []Variable) []Change {
+	old := make(map[string]*Variable, len(before))
+	for i := range before {
+		old[before[i].Name] = &before[i]
+	}
+	seen := make(map[string]bool, len(after))
+
+	var changes []Change
+	for i := range after {
+		variable := &after[i]
+		seen[variable.Name] = true
+		previous, ok := old[variable.Name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Name: variable.Name, New: variable})
+		case previous.Type != variable.Type || !Equal(previous.Value, variable.Value):
+			changes = append(changes, Change{Name: variable.Name, Old: previous, New: variable})
+		}
+	}
+	for i := range before {
+		if !seen[before[i].Name] {
+			changes = append(changes, Change{Name: before[i].Name, Old: &before[i]})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// -----------------------------------------------------------------------
+// String renders a change as "name: old → new"
+func (c Change) String() string {
+	describe := func(v *Variable) string {
+		if v == nil {
+			return "∅"
+		}
+		return fmt.Sprintf("%s (%s)", Summary(v.Value, 40), v.Type)
+	}
+	return fmt.Sprintf("%s: %s → %s", c.Name, describe(c.Old), describe(c.New))
+}
+
+// -----------------------------------------------------------------------
+// ParseAssignments reads one "name = value" or "name:Type = value" per
+// line. Without a type, JSON literals keep their JSON type and anything
+// else is a String.
+func ParseAssignments(text string) ([]Variable, error) {
+	var list []Variable
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 1 {
+			return nil, fmt.Errorf("line %d: expected name = value", n+1)
+		}
+		name, raw := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+
+		typ := ""
+		if colon := strings.Index(name, ":"); colon > 0 {
+			name, typ = strings.TrimSpace(name[:colon]), strings.TrimSpace(name[colon+1:])
+		}
+		if typ == "" {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(raw), &decoded); err == nil {
+				list = append(list, Variable{Name: name, Type: TypeOf(decoded), Value: decoded})
+				continue
+			}
+			typ = TypeString
+		}
+		value, err := Parse(raw, typ)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+1, err)
+		}
+		list = append(list, Variable{Name: name, Type: typ, Value: value})
+	}
+	return list, nil
+}
+
+// -----------------------------------------------------------------------
+// ToEngineMap converts variables into the engine's name -> value payload
+func ToEngineMap(list []Variable) map[string]models.VariableValue {
+	if len(list) == 0 {
+		return nil
+	}
+	payload := make(map[string]models.VariableValue, len(list))
+	for _, v := range list {
+		payload[v.Name] = ToEngine(v)
+	}
+	return payload
+}
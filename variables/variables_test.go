@@ -0,0 +1,148 @@
+package variables
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, TypeNull},
+		{true, TypeBoolean},
+		{float64(42), TypeInteger},
+		{float64(1 << 40), TypeLong},
+		{2.5, TypeDouble},
+		{json.Number("7"), TypeLong},
+		{json.Number("7.5"), TypeDouble},
+		{"hello", TypeString},
+		{"2024-05-07", TypeDate},
+		{"2024-05-07T10:00:00.000+0330", TypeDate},
+		{map[string]interface{}{"a": 1.0}, TypeJSON},
+		{[]interface{}{1.0}, TypeJSON},
+	}
+	for _, tt := range tests {
+		if got := TypeOf(tt.value); got != tt.want {
+			t.Errorf("TypeOf(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantType  string
+		wantValue interface{}
+	}{
+		{"plain", "2024-05-07", TypeDate, "2024-05-07"},
+		{"engine string", map[string]interface{}{"type": "String", "value": "2024-05-07"}, TypeString, "2024-05-07"},
+		{"engine short", map[string]interface{}{"type": "Short", "value": 7.0}, TypeShort, 7.0},
+		{"lower case type", map[string]interface{}{"type": "long", "value": 7.0}, TypeLong, 7.0},
+		{"engine json", map[string]interface{}{"type": "Json", "value": `{"a":1}`, "valueInfo": map[string]interface{}{}},
+			TypeJSON, map[string]interface{}{"a": 1.0}},
+		{"engine object", map[string]interface{}{"type": "Object", "value": "rO0AB"}, "Object", "rO0AB"},
+		{"plain object", map[string]interface{}{"type": "gold", "value": 1.0, "level": 2.0}, TypeJSON,
+			map[string]interface{}{"type": "gold", "value": 1.0, "level": 2.0}},
+	}
+	for _, tt := range tests {
+		got := FromMap(map[string]interface{}{"v": tt.value})
+		if len(got) != 1 || got[0].Type != tt.wantType || !reflect.DeepEqual(got[0].Value, tt.wantValue) {
+			t.Errorf("FromMap(%s) = %#v, want %s %#v", tt.name, got, tt.wantType, tt.wantValue)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text, typ string
+		want      interface{}
+		err       bool
+	}{
+		{" text ", TypeString, "text", false},
+		{"true", TypeBoolean, true, false},
+		{"yes", TypeBoolean, nil, true},
+		{"1200", TypeShort, int64(1200), false},
+		{"40000", TypeShort, nil, true},
+		{"42", TypeInteger, int64(42), false},
+		{"3000000000", TypeInteger, nil, true},
+		{"3000000000", TypeLong, int64(3000000000), false},
+		{"2.5", TypeDouble, 2.5, false},
+		{"abc", TypeDouble, nil, true},
+		{"2024-05-07", TypeDate, "2024-05-07T00:00:00.000+0000", false},
+		{"07/05/2024", TypeDate, nil, true},
+		{`{"a": [1, 2]}`, TypeJSON, map[string]interface{}{"a": []interface{}{1.0, 2.0}}, false},
+		{`{"a":`, TypeJSON, nil, true},
+		{"anything", TypeNull, nil, false},
+		{"1", "Bytes", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.text, tt.typ)
+		if (err != nil) != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q, %s) = %#v, %v; want %#v, error %v", tt.text, tt.typ, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestFormatAndSummary(t *testing.T) {
+	value := map[string]interface{}{"name": "علی", "items": []interface{}{1.0, 2.0}}
+	if got := Format(value); !strings.Contains(got, "\n  \"items\": [") {
+		t.Errorf("Format(json) = %q, want indented JSON", got)
+	}
+	tests := []struct {
+		value interface{}
+		limit int
+		want  string
+	}{
+		{nil, 10, "null"},
+		{2.50, 10, "2.5"},
+		{false, 10, "false"},
+		{value, 100, `{"items":[1,2],"name":"علی"}`},
+		{"سلام دنیا و همه", 10, "سلام دن..."},
+		{"line\nbreak", 20, "line break"},
+	}
+	for _, tt := range tests {
+		if got := Summary(tt.value, tt.limit); got != tt.want {
+			t.Errorf("Summary(%#v, %d) = %q, want %q", tt.value, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestToEngine(t *testing.T) {
+	payload := ToEngine(Variable{Name: "order", Type: TypeJSON, Value: map[string]interface{}{"id": 7.0}})
+	if payload.Type != TypeJSON || payload.Value != `{"id":7}` {
+		t.Errorf("ToEngine(json) = %#v, want serialized text", payload)
+	}
+	payload = ToEngine(Variable{Name: "amount", Type: TypeLong, Value: int64(5)})
+	if payload.Type != TypeLong || payload.Value != int64(5) {
+		t.Errorf("ToEngine(long) = %#v", payload)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := FromMap(map[string]interface{}{"amount": 10.0, "approved": false, "note": "x"})
+	after := FromMap(map[string]interface{}{"amount": 10.0, "approved": true, "customer": "ali"})
+
+	var got []string
+	for _, change := range Diff(before, after) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"approved: false (Boolean) → true (Boolean)",
+		"customer: ∅ → ali (String)",
+		"note: x (String) → ∅",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+
+	// the same value under another type is a change
+	retyped := []Variable{{Name: "amount", Type: TypeLong, Value: 10.0}}
+	if changes := Diff(before[:1], retyped); len(changes) != 1 {
+		t.Errorf("retyped amount: %v", changes)
+	}
+}
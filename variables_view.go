@@ -0,0 +1,381 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"bpmn-manager/models"
+	"bpmn-manager/variables"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// variablesPanel lists the variables of one instance as a tree
+type variablesPanel struct {
+	instanceID string
+	vars       []variables.Variable
+	tree       *tview.TreeView
+	search     *tview.InputField
+	layout     *tview.Flex
+}
+
+// -----------------------------------------------------------------------
+// variablesConfigPath returns where the variable editor groups are
+// configured
+func variablesConfigPath() string {
+	return filepath.Join(viewsDir(), "variables.json")
+}
+
+// -----------------------------------------------------------------------
+// fetchCurrentUser returns cached, the m.currentUser read on the UI
+// thread, or loads the user from the API when it is nil. It is called
+// from background goroutines, so callers store the result in
+// m.currentUser from the UI thread.
+func (m *BPMNManager) fetchCurrentUser(cached *models.User) *models.User {
+	if cached != nil {
+		return cached
+	}
+	user, err := m.apiClient.GetCurrentUser()
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) canModifyVariables() bool {
+	return m.editorsErr == nil && m.currentUser.IsMemberOf(m.editors.Groups...)
+}
+
+// -----------------------------------------------------------------------
+// refuseVariableChange explains why the variables cannot be modified
+func (m *BPMNManager) refuseVariableChange() {
+	if m.editorsErr != nil {
+		m.showError("Variable editors could not be loaded: " + m.editorsErr.Error())
+		return
+	}
+	m.showError("You are not authorised to modify variables")
+}
+
+// -----------------------------------------------------------------------
+// showVariables opens the variables panel of a process instance
+func (m *BPMNManager) showVariables(instanceID string) {
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		m.showError("Select a process instance first")
+		return
+	}
+
+	cached := m.currentUser
+	go func() {
+		user := m.fetchCurrentUser(cached)
+		process, err := m.apiClient.GetProcessDetails(instanceID)
+		m.app.QueueUpdateDraw(func() {
+			m.currentUser = user
+			if err != nil {
+				m.showError("Failed to load process details: " + err.Error())
+				return
+			}
+			panel := m.newVariablesPanel(instanceID, variables.FromMap(process.CurrentVariables))
+			m.pages.AddPage("variables", panel.layout, true, true)
+			m.pages.SwitchToPage("variables")
+			m.app.SetFocus(panel.tree)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) newVariablesPanel(instanceID string, vars []variables.Variable) *variablesPanel {
+	panel := &variablesPanel{
+		instanceID: instanceID,
+		vars:       vars,
+		tree:       tview.NewTreeView(),
+		search:     tview.NewInputField().SetLabel("🔎 ").SetFieldWidth(0),
+	}
+
+	panel.tree.SetBorder(true).SetBorderColor(tcell.Color102)
+	panel.tree.SetGraphicsColor(tcell.Color102)
+	panel.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	panel.search.SetChangedFunc(func(text string) {
+		panel.rebuild()
+	})
+	panel.search.SetDoneFunc(func(key tcell.Key) {
+		m.app.SetFocus(panel.tree)
+	})
+
+	help := "/ search • Enter expand/collapse"
+	if m.canModifyVariables() {
+		help += " • e edit • a add • d delete"
+	} else {
+		help += " • [gray]read only[white]"
+	}
+	help += " • h history • r reload • Esc back"
+	footer := tview.NewTextView().SetDynamicColors(true).SetText(help)
+
+	panel.tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '/':
+			m.app.SetFocus(panel.search)
+			return nil
+		case 'r':
+			m.showVariables(instanceID)
+			return nil
+		case 'h':
+			m.showVariableHistory(instanceID)
+			return nil
+		case 'a':
+			m.showVariableEditor(panel, nil)
+			return nil
+		case 'e', 'd':
+			variable := panel.selectedVariable()
+			if variable == nil {
+				return nil
+			}
+			if event.Rune() == 'e' {
+				m.showVariableEditor(panel, variable)
+			} else {
+				m.confirmVariableChanges(panel, []variables.Variable{*variable}, nil)
+			}
+			return nil
+		}
+		return event
+	})
+
+	panel.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panel.search, 1, 0, false).
+		AddItem(panel.tree, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	panel.layout.SetBorder(true).SetTitle(fmt.Sprintf(" Variables • %s ", instanceID)).SetBorderColor(tcell.Color102)
+	m.backOnEsc(panel.layout, "main")
+
+	panel.rebuild()
+	return panel
+}
+
+// -----------------------------------------------------------------------
+// rebuild fills the tree with the variables matching the search text
+func (p *variablesPanel) rebuild() {
+	query := strings.ToLower(strings.TrimSpace(p.search.GetText()))
+	root := tview.NewTreeNode(fmt.Sprintf("[yellow]Variables (%d)", len(p.vars))).SetSelectable(false)
+
+	for i := range p.vars {
+		variable := &p.vars[i]
+		summary := variables.Summary(variable.Value, 60)
+		if query != "" &&
+			!strings.Contains(strings.ToLower(variable.Name), query) &&
+			!strings.Contains(strings.ToLower(variables.Summary(variable.Value, 0)), query) {
+			continue
+		}
+
+		node := tview.NewTreeNode(fmt.Sprintf("[violet]%s[white] [gray](%s)[white] = %s",
+			tview.Escape(variable.Name), variable.Type, tview.Escape(rtl(summary)))).
+			SetReference(variable)
+		addJSONChildren(node, variable.Value)
+		node.SetExpanded(query != "")
+		root.AddChild(node)
+	}
+
+	p.tree.SetRoot(root).SetCurrentNode(nil)
+	if children := root.GetChildren(); len(children) > 0 {
+		p.tree.SetCurrentNode(children[0])
+	}
+}
+
+// -----------------------------------------------------------------------
+// addJSONChildren adds one child per object key or array element
+func addJSONChildren(parent *tview.TreeNode, value interface{}) {
+	add := func(key string, child interface{}) {
+		text := fmt.Sprintf("[darkcyan]%s[white] [gray](%s)[white]", tview.Escape(key), variables.TypeOf(child))
+		switch child.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			text += " = " + tview.Escape(rtl(variables.Summary(child, 60)))
+		}
+		node := tview.NewTreeNode(text).SetExpanded(false)
+		addJSONChildren(node, child)
+		parent.AddChild(node)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			add(key, v[key])
+		}
+	case []interface{}:
+		for i, item := range v {
+			add(fmt.Sprintf("[%d]", i), item)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------
+// selectedVariable returns the top-level variable of the selected node
+func (p *variablesPanel) selectedVariable() *variables.Variable {
+	node := p.tree.GetCurrentNode()
+	for node != nil {
+		if variable, ok := node.GetReference().(*variables.Variable); ok {
+			return variable
+		}
+		node = findParent(p.tree.GetRoot(), node)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+func findParent(root, target *tview.TreeNode) *tview.TreeNode {
+	for _, child := range root.GetChildren() {
+		if child == target {
+			return root
+		}
+		if parent := findParent(child, target); parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// showVariableEditor edits an existing variable, or adds one when
+// variable is nil
+func (m *BPMNManager) showVariableEditor(panel *variablesPanel, variable *variables.Variable) {
+	if !m.canModifyVariables() {
+		m.refuseVariableChange()
+		return
+	}
+
+	name, typ, value := "", variables.TypeString, ""
+	if variable != nil {
+		if !variables.Editable(variable.Type) {
+			m.showError(fmt.Sprintf("%s variables cannot be edited here", variable.Type))
+			return
+		}
+		name, typ, value = variable.Name, variable.Type, variables.Format(variable.Value)
+	}
+	typeIndex := 0
+	for i, t := range variables.Types {
+		if t == typ {
+			typeIndex = i
+		}
+	}
+
+	form := tview.NewForm()
+	if variable == nil {
+		form.AddInputField("Name", "", 40, nil, nil)
+	} else {
+		form.AddTextView("Name", name, 40, 1, false, false)
+	}
+	form.AddDropDown("Type", variables.Types, typeIndex, nil).
+		AddTextArea("Value", value, 60, 10, 0, nil)
+
+	form.AddButton("Review", func() {
+		if variable == nil {
+			name = strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		}
+		if name == "" {
+			m.showError("Variable name is required")
+			return
+		}
+		_, newType := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		parsed, err := variables.Parse(form.GetFormItem(2).(*tview.TextArea).GetText(), newType)
+		if err != nil {
+			m.showError(err.Error())
+			return
+		}
+
+		var before []variables.Variable
+		if variable != nil {
+			before = []variables.Variable{*variable}
+		} else {
+			for _, existing := range panel.vars {
+				if existing.Name == name {
+					before = []variables.Variable{existing}
+				}
+			}
+		}
+		m.confirmVariableChanges(panel, before, []variables.Variable{{Name: name, Type: newType, Value: parsed}})
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("variables")
+		})
+
+	title := " Add Variable "
+	if variable != nil {
+		title = " Edit Variable "
+	}
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("variables")
+	})
+	form.SetBorder(true).SetTitle(title).SetBorderColor(tcell.Color102)
+	m.pages.AddPage("variable_edit", form, true, true)
+	m.pages.SwitchToPage("variable_edit")
+}
+
+// -----------------------------------------------------------------------
+// confirmVariableChanges shows the diff between before and after and
+// submits it on confirmation. Variables missing from after are deleted.
+func (m *BPMNManager) confirmVariableChanges(panel *variablesPanel, before, after []variables.Variable) {
+	if !m.canModifyVariables() {
+		m.refuseVariableChange()
+		return
+	}
+
+	changes := variables.Diff(before, after)
+	if len(changes) == 0 {
+		m.showMessage("Nothing changed")
+		return
+	}
+
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = change.String()
+	}
+
+	modal := tview.NewModal().
+		SetText("Apply to instance " + panel.instanceID + "?\n\n" + strings.Join(lines, "\n")).
+		AddButtons([]string{"Submit", "Back"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel != "Submit" {
+				m.pages.SwitchToPage("variables")
+				return
+			}
+			go func() {
+				err := m.applyVariableChanges(panel.instanceID, changes)
+				m.app.QueueUpdateDraw(func() {
+					if err != nil {
+						m.showError(err.Error())
+						return
+					}
+					m.showVariables(panel.instanceID)
+				})
+			}()
+		})
+	m.pages.AddPage("variable_confirm", modal, true, true)
+	m.pages.SwitchToPage("variable_confirm")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) applyVariableChanges(instanceID string, changes []variables.Change) error {
+	for _, change := range changes {
+		var err error
+		if change.New == nil {
+			err = m.apiClient.DeleteVariable(instanceID, change.Name)
+		} else {
+			err = m.apiClient.SetVariable(instanceID, change.Name, variables.ToEngine(*change.New))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
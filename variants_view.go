@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bpmn-manager/mining"
+	"bpmn-manager/models"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showVariants loads the completed instances and lets the user pick the
+// process whose paths to explore
+func (m *BPMNManager) showVariants() {
+	go func() {
+		processes, err := m.apiClient.GetCompletedProcesses()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load completed processes: " + err.Error())
+				return
+			}
+			if len(processes) == 0 {
+				m.showError("There are no completed process instances to analyse")
+				return
+			}
+			m.chooseDefinition("variants", processes, m.showVariantExplorer)
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+// chooseDefinition asks which process definition to analyse when the
+// instances belong to several, and calls open with the chosen ones
+func (m *BPMNManager) chooseDefinition(page string, processes []models.ProcessDetails, open func(key string, processes []models.ProcessDetails)) {
+	byKey := make(map[string][]models.ProcessDetails)
+	for _, process := range processes {
+		byKey[process.ProcessDefinitionKey] = append(byKey[process.ProcessDefinitionKey], process)
+	}
+	if len(byKey) == 1 {
+		open(processes[0].ProcessDefinitionKey, processes)
+		return
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(byKey[keys[i]]) != len(byKey[keys[j]]) {
+			return len(byKey[keys[i]]) > len(byKey[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	list := tview.NewList()
+	for _, key := range keys {
+		key := key
+		list.AddItem(key, fmt.Sprintf("%d completed instances", len(byKey[key])), 0, func() {
+			open(key, byKey[key])
+		})
+	}
+	list.AddItem("All processes", fmt.Sprintf("%d completed instances", len(processes)), 0, func() {
+		open("all processes", processes)
+	})
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	list.SetDoneFunc(func() {
+		m.pages.SwitchToPage("main")
+	})
+	list.SetBorder(true).SetTitle(" Choose a process ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage(page+"_definitions", list, true, true)
+	m.pages.SwitchToPage(page + "_definitions")
+}
+
+// -----------------------------------------------------------------------
+// showVariantExplorer ranks the paths taken by the instances; selecting a
+// variant lists its instances
+func (m *BPMNManager) showVariantExplorer(key string, processes []models.ProcessDetails) {
+	order := mining.ByFrequency
+	variants := mining.Variants(processes, m.clock(), order)
+
+	table := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	path := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	path.SetBorder(true).SetTitle(" Path ").SetBorderColor(tcell.Color102)
+
+	instances := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	instances.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	instances.SetBorder(true).SetBorderColor(tcell.Color102)
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+
+	fill := func() {
+		table.Clear()
+		table.SetTitle(fmt.Sprintf(" Variants • %s • %d instances, %d paths • by %s • %s ",
+			key, len(processes), len(variants), order, m.timeMode()))
+		headers := []string{"#", "|Instances", "|Share", "|Mean", "|Min", "|Max", "|Steps", "|Path"}
+		for i, header := range headers {
+			table.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		for row, variant := range variants {
+			table.SetCell(row+1, 0, tview.NewTableCell(fmt.Sprintf("%d", row+1)))
+			table.SetCell(row+1, 1, tview.NewTableCell(fmt.Sprintf("| %d", len(variant.Instances))))
+			table.SetCell(row+1, 2, tview.NewTableCell(fmt.Sprintf("| %5.1f%%", variant.Share*100)))
+			table.SetCell(row+1, 3, tview.NewTableCell("| "+formatAge(variant.MeanDuration)))
+			table.SetCell(row+1, 4, tview.NewTableCell("| "+formatAge(variant.MinDuration)))
+			table.SetCell(row+1, 5, tview.NewTableCell("| "+formatAge(variant.MaxDuration)))
+			table.SetCell(row+1, 6, tview.NewTableCell(fmt.Sprintf("| %d", len(variant.Activities))))
+			table.SetCell(row+1, 7, tview.NewTableCell("| "+tview.Escape(variantPath(variant, 60))).SetExpansion(1))
+		}
+	}
+
+	selectVariant := func(row int) {
+		if row < 1 || row > len(variants) {
+			return
+		}
+		variant := variants[row-1]
+
+		steps := make([]string, len(variant.Activities))
+		for i, name := range variant.Activities {
+			steps[i] = fmt.Sprintf("[yellow]%d.[white] %s", i+1, tview.Escape(rtl(name)))
+		}
+		path.SetText(strings.Join(steps, "\n")).ScrollToBeginning()
+
+		instances.Clear()
+		instances.SetTitle(fmt.Sprintf(" Instances of variant %d ", row))
+		for i, header := range []string{"ID", "|Started", "|Ended", "|Duration"} {
+			instances.SetCell(0, i, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		for i, process := range variant.Instances {
+			instances.SetCell(i+1, 0, tview.NewTableCell(process.ID))
+			instances.SetCell(i+1, 1, tview.NewTableCell("|"+process.StartTime))
+			instances.SetCell(i+1, 2, tview.NewTableCell("|"+process.EndTime))
+			instances.SetCell(i+1, 3, tview.NewTableCell("| "+formatAge(mining.Duration(process, m.clock()))))
+		}
+		instances.Select(1, 0).ScrollToBeginning()
+	}
+
+	fill()
+	table.SetSelectionChangedFunc(func(row, column int) {
+		selectVariant(row)
+	})
+	table.Select(1, 0)
+	selectVariant(1)
+
+	table.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(variants) && len(variants[row-1].Instances) > 0 {
+			m.app.SetFocus(instances)
+		}
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 's' {
+			if order == mining.ByFrequency {
+				order = mining.ByDuration
+			} else {
+				order = mining.ByFrequency
+			}
+			mining.Sort(variants, order)
+			fill()
+			table.Select(1, 0)
+			selectVariant(1)
+			return nil
+		}
+		return event
+	})
+
+	instances.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+			m.app.SetFocus(table)
+			return nil
+		}
+		row, _ := instances.GetSelection()
+		variantRow, _ := table.GetSelection()
+		if row < 1 || variantRow < 1 || variantRow > len(variants) || row > len(variants[variantRow-1].Instances) {
+			return event
+		}
+		id := variants[variantRow-1].Instances[row-1].ID
+		if event.Key() == tcell.KeyEnter {
+			m.showInstanceDetails(id, "variants")
+			return nil
+		}
+		return m.handleProcessKey(id, event)
+	})
+
+	footer.SetText("Enter instances • Tab variants • s sort by frequency/duration • on an instance: Enter details, t timeline, v variables, e export • Esc back")
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(path, 0, 1, false).
+		AddItem(instances, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(table, 0, 3, true).
+		AddItem(right, 0, 2, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	m.pages.AddPage("variants", layout, true, true)
+	m.pages.SwitchToPage("variants")
+	m.app.SetFocus(table)
+}
+
+// -----------------------------------------------------------------------
+// variantPath abbreviates the activity sequence of a variant to width
+// characters
+func variantPath(variant mining.Variant, width int) string {
+	names := make([]string, len(variant.Activities))
+	for i, name := range variant.Activities {
+		names[i] = rtl(name)
+	}
+	return truncate(strings.Join(names, " → "), width)
+}
+
+// -----------------------------------------------------------------------
+// showInstanceDetails shows the details of an instance on their own page;
+// Enter or Esc returns to back
+func (m *BPMNManager) showInstanceDetails(instanceID, back string) {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetText(m.createProcessDetails(instanceID, view))
+	view.SetBorder(true).SetTitle(" Process Details • Enter back ").SetBorderColor(tcell.Color102)
+	view.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter || key == tcell.KeyEscape {
+			m.pages.SwitchToPage(back)
+		}
+	})
+	m.pages.AddPage("instance_details", view, true, true)
+	m.pages.SwitchToPage("instance_details")
+}
@@ -0,0 +1,95 @@
+package workload
+
+import (
+	"sort"
+	"time"
+
+	"bpmn-manager/calendar"
+	"bpmn-manager/models"
+)
+
+// Unassigned is the entry name of tasks nobody has claimed
+const Unassigned = ""
+
+// Entry summarises the open tasks of one assignee
+type Entry struct {
+	Assignee   string
+	Open       int
+	Overdue    int
+	AverageAge time.Duration
+	OldestAge  time.Duration
+	Tasks      []models.UserTask // oldest first
+}
+
+// -----------------------------------------------------------------------
+// Compute groups open tasks by assignee, busiest first. Tasks without an
+// assignee are collected under Unassigned. Ages are measured by clock.
+func Compute(tasks []models.UserTask, now time.Time, clock calendar.Clock) []Entry {
+	byAssignee := make(map[string]*Entry)
+	var order []string
+	for _, task := range tasks {
+		entry, ok := byAssignee[task.Assignee]
+		if !ok {
+			entry = &Entry{Assignee: task.Assignee}
+			byAssignee[task.Assignee] = entry
+			order = append(order, task.Assignee)
+		}
+		entry.Tasks = append(entry.Tasks, task)
+	}
+
+	entries := make([]Entry, 0, len(order))
+	for _, assignee := range order {
+		entry := byAssignee[assignee]
+		entry.Open = len(entry.Tasks)
+		sort.SliceStable(entry.Tasks, func(i, j int) bool {
+			return entry.Tasks[i].CreatedAt.Before(entry.Tasks[j].CreatedAt)
+		})
+
+		var total time.Duration
+		aged := 0
+		for _, task := range entry.Tasks {
+			if !task.DueDate.IsZero() && task.DueDate.Before(now) {
+				entry.Overdue++
+			}
+			if task.CreatedAt.IsZero() {
+				continue
+			}
+			age := clock.Between(task.CreatedAt, now)
+			total += age
+			aged++
+			if age > entry.OldestAge {
+				entry.OldestAge = age
+			}
+		}
+		if aged > 0 {
+			entry.AverageAge = total / time.Duration(aged)
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Open != entries[j].Open {
+			return entries[i].Open > entries[j].Open
+		}
+		return entries[i].Assignee < entries[j].Assignee
+	})
+	return entries
+}
+
+// -----------------------------------------------------------------------
+// Average returns the mean number of open tasks per assignee, ignoring
+// unassigned tasks. Team leads compare entries against it.
+func Average(entries []Entry) float64 {
+	total, assignees := 0, 0
+	for _, entry := range entries {
+		if entry.Assignee == Unassigned {
+			continue
+		}
+		total += entry.Open
+		assignees++
+	}
+	if assignees == 0 {
+		return 0
+	}
+	return float64(total) / float64(assignees)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bpmn-manager/models"
+	"bpmn-manager/workload"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// -----------------------------------------------------------------------
+// showWorkload shows open task counts and ages per assignee so work can
+// be rebalanced
+func (m *BPMNManager) showWorkload() {
+	go func() {
+		tasks, err := m.apiClient.GetUserTasks()
+		m.app.QueueUpdateDraw(func() {
+			if err != nil {
+				m.showError("Failed to load tasks: " + err.Error())
+				return
+			}
+			m.pages.AddPage("workload", m.createWorkloadView(workload.Compute(tasks, time.Now(), m.clock())), true, true)
+			m.pages.SwitchToPage("workload")
+		})
+	}()
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) createWorkloadView(entries []workload.Entry) tview.Primitive {
+	average := workload.Average(entries)
+
+	table := tview.NewTable().
+		SetFixed(1, 0).
+		SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen))
+	table.SetBorder(true).SetBorderColor(tcell.Color102).
+		SetTitle(fmt.Sprintf(" Workload • %.1f open tasks per assignee • ages in %s ", average, m.timeMode()))
+
+	headers := []string{"Assignee", "|Open", "|Overdue", "|Avg Age", "|Oldest", "|Load"}
+	for i, header := range headers {
+		table.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	most := 1
+	for _, entry := range entries {
+		if entry.Open > most {
+			most = entry.Open
+		}
+	}
+	for row, entry := range entries {
+		name := entry.Assignee
+		color := tcell.ColorWhite
+		switch {
+		case entry.Assignee == workload.Unassigned:
+			name, color = "(unassigned)", tcell.ColorGray
+		case average > 0 && float64(entry.Open) > 1.5*average:
+			color = tcell.ColorRed
+		}
+		overdue := tview.NewTableCell(fmt.Sprintf("| %d", entry.Overdue))
+		if entry.Overdue > 0 {
+			overdue.SetTextColor(tcell.ColorRed)
+		}
+		bar := strings.Repeat("█", (entry.Open*20+most-1)/most)
+
+		table.SetCell(row+1, 0, tview.NewTableCell(tview.Escape(name)).SetTextColor(color))
+		table.SetCell(row+1, 1, tview.NewTableCell(fmt.Sprintf("| %d", entry.Open)))
+		table.SetCell(row+1, 2, overdue)
+		table.SetCell(row+1, 3, tview.NewTableCell("| "+formatAge(entry.AverageAge)))
+		table.SetCell(row+1, 4, tview.NewTableCell("| "+formatAge(entry.OldestAge)))
+		table.SetCell(row+1, 5, tview.NewTableCell("| "+bar).SetTextColor(color))
+	}
+	table.Select(1, 0)
+
+	table.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(entries) {
+			m.showAssigneeTasks(entries[row-1])
+		}
+	})
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("Enter assignee's tasks • [red]red[white]: more than 1.5× the average • Esc back")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "main")
+	return layout
+}
+
+// -----------------------------------------------------------------------
+// showAssigneeTasks lists the open tasks of one assignee, oldest first,
+// for reassigning them
+func (m *BPMNManager) showAssigneeTasks(entry workload.Entry) {
+	name := entry.Assignee
+	if name == workload.Unassigned {
+		name = "unassigned"
+	}
+	table := newDataTable(m.app, "Tasks • "+name,
+		taskColumns("id", "name", "processName", "candidateGroup", "created", "due"))
+	rows := make([][]string, len(entry.Tasks))
+	for row, task := range entry.Tasks {
+		rows[row] = taskRow(task)
+	}
+	table.setRows(rows)
+
+	table.setInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		selected := table.selectedRow()
+		if selected < 0 {
+			return event
+		}
+		task := entry.Tasks[selected]
+		switch {
+		case event.Key() == tcell.KeyEnter:
+			m.showTaskDetail(task)
+			return nil
+		case event.Rune() == 'a':
+			m.showReassignForm(task)
+			return nil
+		}
+		return event
+	})
+
+	footer := tview.NewTextView().SetText("a reassign • Enter details • /: search • Esc back")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table.layout, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	m.backOnEsc(layout, "workload")
+	m.pages.AddPage("workload_tasks", layout, true, true)
+	m.pages.SwitchToPage("workload_tasks")
+}
+
+// -----------------------------------------------------------------------
+func (m *BPMNManager) showReassignForm(task models.UserTask) {
+	form := tview.NewForm().
+		AddTextView("Task", tview.Escape(task.ID+" "+rtl(task.Name)), 60, 1, true, false).
+		AddInputField("Assignee", task.Assignee, 30, nil, nil)
+
+	form.AddButton("Assign", func() {
+		assignee := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		go func() {
+			var err error
+			if assignee == "" {
+				err = m.apiClient.UnclaimTask(task.ID)
+			} else {
+				err = m.apiClient.SetAssignee(task.ID, assignee)
+			}
+			m.app.QueueUpdateDraw(func() {
+				if err != nil {
+					m.showError(err.Error())
+					return
+				}
+				m.showWorkload()
+			})
+		}()
+	}).
+		AddButton("Cancel", func() {
+			m.pages.SwitchToPage("workload_tasks")
+		})
+
+	form.SetCancelFunc(func() {
+		m.pages.SwitchToPage("workload_tasks")
+	})
+	form.SetBorder(true).SetTitle(" Reassign Task • empty returns it to the group ").SetBorderColor(tcell.Color102)
+	m.pages.AddPage("workload_reassign", form, true, true)
+	m.pages.SwitchToPage("workload_reassign")
+}
+
+// -----------------------------------------------------------------------
+// formatAge formats a task age in days and hours
+func formatAge(age time.Duration) string {
+	if age <= 0 {
+		return "—"
+	}
+	days := int(age.Hours()) / 24
+	hours := int(age.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(age.Minutes())%60)
+}